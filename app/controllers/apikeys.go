@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	eapikeys "github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/entities"
+	iapikeys "github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/interfaces"
+	apikeys "github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/logic"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	eusers "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/sirupsen/logrus"
+)
+
+// APIKeysController is a wrapper for controllers that manage API keys of users.
+type APIKeysController struct {
+	api.ControllerSuite
+	service iapikeys.APIKeysService
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *APIKeysController) MustInitialize() {
+	c.service = mustAPIKeysService(context.Background())
+}
+
+// CreateKey creates an API key of the current user, serving the key itself only this once.
+func (c *APIKeysController) CreateKey() {
+	ctx := context.Background()
+	identity, ok := self(&c.ControllerSuite, "api keys")
+	if !ok {
+		return
+	}
+	var key eapikeys.APIKey
+	err := c.ParseJSONBody(&key)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	key.UserID = eusers.UserID(identity.UserID)
+	token, err := c.service.CreateKey(ctx, &key)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeCreated(eapikeys.CreatedKey{APIKey: key, Key: token})
+}
+
+// IndexKeys fetches API keys of the current user.
+func (c *APIKeysController) IndexKeys() {
+	ctx := context.Background()
+	identity, ok := self(&c.ControllerSuite, "api keys")
+	if !ok {
+		return
+	}
+	keys, err := c.service.IndexKeys(ctx, eusers.UserID(identity.UserID))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(keys)
+}
+
+// RevokeKey revokes an API key of the current user.
+func (c *APIKeysController) RevokeKey() {
+	ctx := context.Background()
+	identity, ok := self(&c.ControllerSuite, "api keys")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.ParseURLParams()["key"], 10, 64)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	err = c.service.RevokeKey(ctx, eusers.UserID(identity.UserID), eapikeys.KeyID(id))
+	if err != nil {
+		if errors.Is(err, eapikeys.ErrKeyNotFound) {
+			c.ServeMessage(http.StatusNotFound, err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessageOK("api key revoked")
+}
+
+// APIKeysMiddleware authenticates requests carrying an API key, either in the X-API-Key header or as a bearer token,
+// restricting the current user to the scopes of the key. Requests with an invalid key are rejected.
+type APIKeysMiddleware struct {
+	service iapikeys.APIKeysService
+}
+
+// MustInitialize performs all the setup needed for the middleware.
+func (m *APIKeysMiddleware) MustInitialize() {
+	m.service = mustAPIKeysService(context.Background())
+}
+
+// Wrap implements api.Middleware.
+func (m *APIKeysMiddleware) Wrap(serve api.Serve) api.Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		token, bearer := bearerToken(request)
+		if _, ok := eapikeys.KeyPrefixOf(token); !bearer || !ok {
+			bearer, token = false, request.Header.Get("X-API-Key")
+		}
+		if token == "" {
+			serve(writer, request)
+			return
+		}
+		key, err := m.service.Authenticate(context.Background(), token)
+		if err != nil {
+			var suite api.ControllerSuite
+			suite.NewRequest(writer, request)
+			if !errors.Is(err, eapikeys.ErrInvalidKey) {
+				logrus.WithError(err).Errorf("failed to authenticate api key")
+				suite.ServeInternalError("failed to authenticate api key")
+				return
+			}
+			if bearer {
+				writer.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			suite.ServeUnauthorized(err.Error())
+			return
+		}
+		identity := api.Identity{UserID: uint64(key.UserID), Scopes: key.Scopes}
+		if identity.Scopes == nil {
+			identity.Scopes = []string{}
+		}
+		serve(writer, api.WithIdentity(request, identity))
+	}
+}
+
+// mustAPIKeysService instantiates an APIKeysService, panicking on failure.
+func mustAPIKeysService(ctx context.Context) *apikeys.APIKeysService {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := apikeys.NewAPIKeysService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
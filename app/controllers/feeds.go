@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/feeds"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	iposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	helpers "github.com/samber/lo"
+)
+
+// feedFormat is a format feeds are served in.
+type feedFormat struct {
+	// name suffixes names of handlers serving feeds in the format.
+	name        string
+	contentType string
+	render      func(feed feeds.Feed) ([]byte, error)
+}
+
+var (
+	rssFormat  = feedFormat{name: "RSS", contentType: feeds.RSSContentType, render: feeds.RSS}
+	atomFormat = feedFormat{name: "Atom", contentType: feeds.AtomContentType, render: feeds.Atom}
+)
+
+// FeedsController is a wrapper for controllers that serve syndication feeds of published posts.
+type FeedsController struct {
+	api.ControllerSuite
+	service iposts.PostsService
+	// metadata describes feeds, it's completed for each one of them.
+	metadata feeds.Feed
+	limit    int
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *FeedsController) MustInitialize() {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	c.service = service
+	cfg := config.MustConfig()
+	c.metadata = feeds.Feed{
+		Title:       cfg["feeds.title"],
+		Description: cfg["feeds.description"],
+		Link:        strings.TrimSuffix(cfg["self.url"], "/"),
+		Author:      cfg["feeds.author"],
+		Language:    cfg["feeds.language"],
+	}
+	if c.limit, err = strconv.Atoi(cfg["feeds.limit"]); err != nil {
+		panic(err)
+	}
+}
+
+// PostsRSS serves an RSS feed of the latest published posts.
+func (c *FeedsController) PostsRSS() {
+	c.serveFeed(eposts.FeedFilter{}, rssFormat)
+}
+
+// PostsAtom serves an Atom feed of the latest published posts.
+func (c *FeedsController) PostsAtom() {
+	c.serveFeed(eposts.FeedFilter{}, atomFormat)
+}
+
+// UserPostsRSS serves an RSS feed of the latest published posts of an author.
+func (c *FeedsController) UserPostsRSS() {
+	c.serveUserFeed(rssFormat)
+}
+
+// UserPostsAtom serves an Atom feed of the latest published posts of an author.
+func (c *FeedsController) UserPostsAtom() {
+	c.serveUserFeed(atomFormat)
+}
+
+// TagPostsRSS serves an RSS feed of the latest published posts with a tag.
+func (c *FeedsController) TagPostsRSS() {
+	c.serveFeed(eposts.FeedFilter{Tag: c.ParseURLParams()["tag"]}, rssFormat)
+}
+
+// TagPostsAtom serves an Atom feed of the latest published posts with a tag.
+func (c *FeedsController) TagPostsAtom() {
+	c.serveFeed(eposts.FeedFilter{Tag: c.ParseURLParams()["tag"]}, atomFormat)
+}
+
+// serveUserFeed serves a feed of the author identified by the id URL param.
+func (c *FeedsController) serveUserFeed(format feedFormat) {
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.serveFeed(eposts.FeedFilter{UserID: userentities.UserID(id)}, format)
+}
+
+// serveFeed serves a feed of posts matching filter, Last-Modified is the last time any of them changed.
+func (c *FeedsController) serveFeed(filter eposts.FeedFilter, format feedFormat) {
+	ctx := context.Background()
+	ps, updated, err := c.service.FeedPosts(ctx, filter, c.limit)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	feed := c.metadata
+	var self string
+	switch {
+	case filter.UserID != 0:
+		self, err = urlFor("FeedsController.UserPosts"+format.name, "id", strconv.FormatUint(uint64(filter.UserID), 10))
+		if len(ps) > 0 && ps[0].User != nil {
+			feed.Title = fmt.Sprintf("%v: %v", feed.Title, ps[0].User.Fullname)
+		}
+	case filter.Tag != "":
+		self, err = urlFor("FeedsController.TagPosts"+format.name, "tag", filter.Tag)
+		feed.Title = fmt.Sprintf("%v: %v", feed.Title, filter.Tag)
+	default:
+		self, err = urlFor("FeedsController.Posts" + format.name)
+	}
+	if err != nil {
+		c.ServeInternalError(err.Error())
+		return
+	}
+	feed.Self = c.metadata.Link + self
+	feed.Updated = updated
+	if updated.IsZero() {
+		feed.Updated = time.Unix(0, 0).UTC()
+	} else {
+		c.SetLastModified(updated)
+	}
+	for _, p := range ps {
+		item, err := c.feedItem(p)
+		if err != nil {
+			c.ServeInternalError(err.Error())
+			return
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	body, err := format.render(feed)
+	if err != nil {
+		c.ServeInternalError(err.Error())
+		return
+	}
+	c.ServeBytes(http.StatusOK, format.contentType, body)
+}
+
+// feedItem describes a post as a feed item, identified by its id since slugs change along with titles.
+func (c *FeedsController) feedItem(p eposts.Post) (feeds.Item, error) {
+	id, err := urlFor("PostsController.FindPost", "id", strconv.FormatUint(uint64(p.ID), 10))
+	if err != nil {
+		return feeds.Item{}, err
+	}
+	link, err := urlFor("PostsController.FindPostBySlug", "slug", p.Slug)
+	if err != nil {
+		return feeds.Item{}, err
+	}
+	item := feeds.Item{
+		ID:          c.metadata.Link + id,
+		Title:       p.Title,
+		Link:        c.metadata.Link + link,
+		Summary:     p.Excerpt,
+		ContentHTML: p.ContentHTML,
+		Categories: helpers.Map(p.Tags, func(tag eposts.Tag, _ int) string {
+			return tag.Name
+		}),
+		Published: p.CreatedAt,
+		Updated:   p.UpdatedAt,
+	}
+	if p.PublishedAt != nil {
+		item.Published = *p.PublishedAt
+	}
+	if p.User != nil {
+		item.Author = p.User.Fullname
+	}
+	return item, nil
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	eidempotency "github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/entities"
+	iidempotency "github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/interfaces"
+	idempotency "github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/logic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IdempotencyMiddleware replays the first response to POST requests retried with the same Idempotency-Key header.
+// Keys are scoped to the user sending them, anonymous requests are served as if they had no key. Responses that
+// mustn't be stored, such as credentials, are never replayed, and neither are cookies they set.
+type IdempotencyMiddleware struct {
+	service iidempotency.IdempotencyService
+	// maxBodySize is the size of the biggest body, in bytes, of a request sent with a key.
+	maxBodySize int64
+}
+
+// MustInitialize performs all the setup needed for the middleware, and starts periodic cleanup of expired keys.
+func (m *IdempotencyMiddleware) MustInitialize() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	ttl, err := time.ParseDuration(cfg["idempotency.ttl"])
+	if err != nil {
+		panic(err)
+	}
+	interval, err := time.ParseDuration(cfg["idempotency.cleanup_interval"])
+	if err != nil {
+		panic(err)
+	}
+	if m.maxBodySize, err = strconv.ParseInt(cfg["idempotency.max_body_size"], 10, 64); err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := idempotency.NewIdempotencyService(ctx, writer, ttl)
+	if err != nil {
+		panic(err)
+	}
+	m.service = service
+	go m.cleanup(ctx, interval)
+}
+
+// Wrap implements api.Middleware.
+func (m *IdempotencyMiddleware) Wrap(serve api.Serve) api.Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		key := request.Header.Get("Idempotency-Key")
+		owner, ok := idempotencyOwner(request)
+		if request.Method != http.MethodPost || key == "" || !ok {
+			serve(writer, request)
+			return
+		}
+		ctx := context.Background()
+		var suite api.ControllerSuite
+		suite.NewRequest(writer, request)
+		// The body is held in memory to be fingerprinted, so its size is limited.
+		body, err := io.ReadAll(io.LimitReader(request.Body, m.maxBodySize+1))
+		if err != nil {
+			suite.ServeBadRequest(err.Error())
+			return
+		}
+		if int64(len(body)) > m.maxBodySize {
+			suite.ServeMessage(http.StatusRequestEntityTooLarge, fmt.Sprintf("requests sent with an idempotency key can't be bigger than %v bytes", m.maxBodySize))
+			return
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, err := m.service.Begin(ctx, owner, key, fingerprint(request, body))
+		switch {
+		case errors.Is(err, eidempotency.ErrInvalidKey):
+			suite.ServeBadRequest(err.Error())
+			return
+		case errors.Is(err, eidempotency.ErrKeyInProgress):
+			suite.ServeConflict(err.Error())
+			return
+		case errors.Is(err, eidempotency.ErrKeyReused):
+			suite.ServeUnprocessableEntity(err.Error())
+			return
+		case err != nil:
+			suite.ServeInternalError(err.Error())
+			return
+		}
+		if record.Completed() {
+			for k, v := range replayedHeader(record.Header()) {
+				writer.Header()[k] = v
+			}
+			writer.Header().Set("Idempotent-Replayed", "true")
+			writer.WriteHeader(record.ResponseStatus)
+			_, _ = writer.Write(record.ResponseBody)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: writer}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := m.service.Release(ctx, owner, key); err != nil {
+				logrus.WithError(err).Errorf("failed to release idempotency key")
+			}
+		}()
+		serve(recorder, request)
+		// Server errors are not stored, so that the request can be retried.
+		if recorder.status >= http.StatusInternalServerError || noStore(writer.Header()) {
+			return
+		}
+		record = eidempotency.Record{Owner: owner, Key: key, ResponseStatus: recorder.status, ResponseBody: recorder.body.Bytes()}
+		record.SetHeader(replayedHeader(writer.Header()))
+		if err = m.service.Complete(ctx, record); err != nil {
+			logrus.WithError(err).Errorf("failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+// cleanup periodically deletes expired keys.
+func (m *IdempotencyMiddleware) cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		deleted, err := m.service.Cleanup(ctx)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to clean up idempotency keys")
+			continue
+		}
+		logrus.Infof("cleaned up %v expired idempotency keys", deleted)
+	}
+}
+
+// fingerprint identifies a request, so that key reuse for a different request can be detected.
+func fingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyOwner scopes idempotency keys to the user sending them, returns false for anonymous requests, since
+// their keys could be guessed and replayed by anyone.
+func idempotencyOwner(request *http.Request) (string, bool) {
+	identity, ok := api.RequestIdentity(request)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("user:%v", identity.UserID), true
+}
+
+// noStore checks whether a response is marked as one that mustn't be stored, which is how routes serving credentials
+// are declared.
+func noStore(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+// replayedHeader drops headers of a response that mustn't be replayed, cookies may carry sessions.
+func replayedHeader(header http.Header) http.Header {
+	replayed := header.Clone()
+	replayed.Del("Set-Cookie")
+	return replayed
+}
+
+// responseRecorder passes a response through, keeping a copy of its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader ...
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write ...
+func (r *responseRecorder) Write(body []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(body)
+	return r.ResponseWriter.Write(body)
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+)
+
+// MustInitializeMiddlewares performs all the needed setup for middlewares, returning them in order they wrap endpoints.
+func MustInitializeMiddlewares() []api.Middleware {
+	var sessions SessionsMiddleware
+	sessions.MustInitialize()
+	var tokens JWTMiddleware
+	tokens.MustInitialize()
+	var keys APIKeysMiddleware
+	keys.MustInitialize()
+	var roles RolesMiddleware
+	roles.MustInitialize()
+	var idempotency IdempotencyMiddleware
+	idempotency.MustInitialize()
+	return []api.Middleware{sessions.Wrap, tokens.Wrap, keys.Wrap, roles.Wrap, idempotency.Wrap}
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail"
+	enewsletter "github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	inewsletter "github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/interfaces"
+	newsletter "github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/logic"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// subscriptionRequest is a body of a subscription, or of a bounce report.
+type subscriptionRequest struct {
+	Email string `json:"email"`
+}
+
+// NewsletterController is a wrapper for controllers that manage subscribers and deliver posts to them.
+type NewsletterController struct {
+	api.ControllerSuite
+	service inewsletter.NewsletterService
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *NewsletterController) MustInitialize() {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	postsService, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	cfg := config.MustConfig()
+	sender, err := mail.NewSMTPSender(cfg["newsletter.smtp_addr"], cfg["newsletter.smtp_username"], cfg["newsletter.smtp_password"])
+	if err != nil {
+		panic(err)
+	}
+	settings := enewsletter.Settings{From: cfg["newsletter.from"]}
+	if settings.RetryDelay, err = time.ParseDuration(cfg["newsletter.retry_delay"]); err != nil {
+		panic(err)
+	}
+	if settings.SendTimeout, err = time.ParseDuration(cfg["newsletter.send_timeout"]); err != nil {
+		panic(err)
+	}
+	if settings.MaxAttempts, err = strconv.Atoi(cfg["newsletter.max_attempts"]); err != nil {
+		panic(err)
+	}
+	if settings.MaxBounces, err = strconv.Atoi(cfg["newsletter.max_bounces"]); err != nil {
+		panic(err)
+	}
+	urls := newsletterURLs{link: strings.TrimSuffix(cfg["self.url"], "/")}
+	service, err := newsletter.NewNewsletterService(ctx, reader, writer, postsService, sender, urls, settings)
+	if err != nil {
+		panic(err)
+	}
+	c.service = service
+	interval, err := time.ParseDuration(cfg["newsletter.delivery_interval"])
+	if err != nil {
+		panic(err)
+	}
+	batchSize, err := strconv.Atoi(cfg["newsletter.batch_size"])
+	if err != nil {
+		panic(err)
+	}
+	go c.deliver(ctx, interval, batchSize)
+}
+
+// deliver periodically sends a batch of due deliveries.
+func (c *NewsletterController) deliver(ctx context.Context, interval time.Duration, batchSize int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		report, err := c.service.Deliver(ctx, batchSize)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to deliver posts")
+			continue
+		}
+		if report != (enewsletter.DeliveryReport{}) {
+			logrus.WithFields(logrus.Fields{
+				"sent":    report.Sent,
+				"retried": report.Retried,
+				"failed":  report.Failed,
+				"bounced": report.Bounced,
+				"skipped": report.Skipped,
+			}).Infof("delivered posts")
+		}
+	}
+}
+
+// Subscribe subscribes an email address, which has to be confirmed with a link sent to it.
+// Addresses that are already subscribed are served the same response, so that subscriptions can't be probed.
+func (c *NewsletterController) Subscribe() {
+	ctx := context.Background()
+	var body subscriptionRequest
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, err := c.service.Subscribe(ctx, body.Email); err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessage(http.StatusAccepted, "a confirmation link has been sent to the address")
+}
+
+// IndexSubscribers fetches a page of subscribers.
+func (c *NewsletterController) IndexSubscribers() {
+	ctx := context.Background()
+	if !c.Authorize(policy.NewsletterManage, nil) {
+		return
+	}
+	opts, err := enewsletter.SubscriberResource.Parse(c.ParseQueryParams())
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	subscribers, page, err := c.service.IndexSubscribers(ctx, opts)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServePage(api.Sparse(subscribers, opts.Keys()), page.Next, page.Prev, page.Total)
+}
+
+// ConfirmSubscriber confirms the subscription a confirmation link was sent for.
+func (c *NewsletterController) ConfirmSubscriber() {
+	ctx := context.Background()
+	_, err := c.service.Confirm(ctx, c.ParseURLParams()["token"])
+	if err != nil {
+		c.serveTokenError(err)
+		return
+	}
+	c.ServeMessageOK("subscription confirmed")
+}
+
+// Unsubscribe ends the subscription an unsubscribe link was sent for, it is served on POST as well for one-click
+// unsubscribe buttons of mail clients.
+func (c *NewsletterController) Unsubscribe() {
+	ctx := context.Background()
+	_, err := c.service.Unsubscribe(ctx, c.ParseURLParams()["token"])
+	if err != nil {
+		c.serveTokenError(err)
+		return
+	}
+	c.ServeMessageOK("unsubscribed")
+}
+
+// RecordBounce records a message bounced by the mail server of a subscriber after it was accepted for delivery.
+// Mail providers report bounces with an API key of a user allowed to manage the newsletter.
+func (c *NewsletterController) RecordBounce() {
+	ctx := context.Background()
+	if !c.Authorize(policy.NewsletterManage, nil) {
+		return
+	}
+	var body subscriptionRequest
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	subscriber, err := c.service.RecordBounce(ctx, body.Email)
+	if err != nil {
+		if errors.Is(err, enewsletter.ErrSubscriberNotFound) {
+			c.ServeMessage(http.StatusNotFound, err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(subscriber)
+}
+
+// QueueDelivery queues a published post to be delivered to active subscribers.
+func (c *NewsletterController) QueueDelivery() {
+	ctx := context.Background()
+	if !c.Authorize(policy.NewsletterManage, nil) {
+		return
+	}
+	id, err := strconv.Atoi(c.ParseURLParams()["id"])
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	queued, err := c.service.QueueDelivery(ctx, eposts.PostID(id))
+	if err != nil {
+		if errors.Is(err, enewsletter.ErrPostNotPublished) {
+			c.ServeConflict(err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeJSON(http.StatusAccepted, map[string]int64{"queued": queued})
+}
+
+// DeliveryStats fetches counts of deliveries of a post by status.
+func (c *NewsletterController) DeliveryStats() {
+	ctx := context.Background()
+	if !c.Authorize(policy.NewsletterManage, nil) {
+		return
+	}
+	id, err := strconv.Atoi(c.ParseURLParams()["id"])
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	stats, err := c.service.DeliveryStats(ctx, eposts.PostID(id))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(stats)
+}
+
+// serveTokenError serves an error of a confirmation or an unsubscribe link.
+func (c *NewsletterController) serveTokenError(err error) {
+	if errors.Is(err, enewsletter.ErrInvalidToken) {
+		c.ServeMessage(http.StatusNotFound, err.Error())
+		return
+	}
+	c.ServeBadRequest(err.Error())
+}
+
+// newsletterURLs builds absolute URLs of routes emails link to.
+type newsletterURLs struct {
+	// link is the public URL of the app.
+	link string
+}
+
+// Post implements inewsletter.URLs.
+func (u newsletterURLs) Post(post eposts.Post) (string, error) {
+	return u.absolute("PostsController.FindPostBySlug", "slug", post.Slug)
+}
+
+// Confirm implements inewsletter.URLs.
+func (u newsletterURLs) Confirm(token string) (string, error) {
+	return u.absolute("NewsletterController.ConfirmSubscriber", "token", token)
+}
+
+// Unsubscribe implements inewsletter.URLs.
+func (u newsletterURLs) Unsubscribe(token string) (string, error) {
+	return u.absolute("NewsletterController.Unsubscribe", "token", token)
+}
+
+func (u newsletterURLs) absolute(handler string, params ...string) (string, error) {
+	path, err := urlFor(handler, params...)
+	if err != nil {
+		return "", err
+	}
+	return u.link + path, nil
+}
@@ -2,17 +2,55 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
 	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
 	iposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
 	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/sirupsen/logrus"
 )
 
 var ()
 
+// batchRequest is a body of a batch write.
+type batchRequest struct {
+	Posts []batchItem `json:"posts"`
+}
+
+// batchItem is a post of a batch write, optionally conditioned on an entity tag the way If-Match conditions single
+// writes.
+type batchItem struct {
+	eposts.Post
+	IfMatch string `json:"if_match,omitempty"`
+}
+
+// batchResponse lists outcomes of a batch write in the order items were provided.
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// batchResult is an outcome of a single item of a batch write, status is the one a single item write would respond
+// with.
+type batchResult struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	ETag   string       `json:"etag,omitempty"`
+	Post   *eposts.Post `json:"post,omitempty"`
+}
+
 // PostsController is a wrapper for controllers that interact with posts.
 type PostsController struct {
 	api.ControllerSuite
@@ -35,33 +73,101 @@ func (c *PostsController) MustInitialize() {
 		panic(err)
 	}
 	c.service = service
+	interval, err := time.ParseDuration(config.MustConfig()["posts.publish_interval"])
+	if err != nil {
+		panic(err)
+	}
+	go c.publishScheduled(ctx, interval)
 }
 
-// IndexPosts fetches all posts.
+// publishScheduled periodically publishes scheduled posts that are due.
+func (c *PostsController) publishScheduled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		published, err := c.service.PublishScheduledPosts(ctx)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to publish scheduled posts")
+			continue
+		}
+		if published > 0 {
+			logrus.Infof("published %v scheduled posts", published)
+		}
+	}
+}
+
+// IndexPosts fetches a page of posts.
 func (c *PostsController) IndexPosts() {
 	ctx := context.Background()
-	ps, err := c.service.IndexPosts(ctx)
+	opts, err := eposts.PostResource.Parse(c.ParseQueryParams())
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	ps, page, err := c.service.IndexPosts(ctx, c.viewer(), opts)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServePage(api.Sparse(ps, opts.Keys()), page.Next, page.Prev, page.Total)
+}
+
+// SearchPosts fetches a page of posts matching ?q= full-text search query.
+func (c *PostsController) SearchPosts() {
+	ctx := context.Background()
+	opts, err := eposts.SearchResultResource.Parse(c.ParseQueryParams())
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	results, page, err := c.service.SearchPosts(ctx, c.viewer(), c.ParseQueryParams().Get("q"), opts)
 	if err != nil {
 		c.ServeBadRequest(err.Error())
 		return
 	}
-	c.ServeOK(ps)
+	c.ServePage(api.Sparse(results, opts.Keys()), page.Next, page.Prev, page.Total)
 }
 
 // FindPost fetches a single post.
 func (c *PostsController) FindPost() {
-	ctx := context.Background()
 	rawID := c.ParseURLParams()["id"]
 	id, err := strconv.Atoi(rawID)
 	if err != nil {
 		c.ServeBadRequest(err.Error())
 		return
 	}
-	p, err := c.service.FindPost(ctx, eposts.PostID(id))
+	p, ok := c.findVisiblePost(eposts.PostID(id))
+	if !ok {
+		return
+	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
+	c.SetLastModified(p.UpdatedAt)
+	c.ServeOK(p)
+}
+
+// FindPostBySlug fetches a single post by its slug, former slugs are permanently redirected to the current one.
+func (c *PostsController) FindPostBySlug() {
+	ctx := context.Background()
+	slug := c.ParseURLParams()["slug"]
+	p, err := c.service.FindPostBySlug(ctx, slug)
+	if err == nil && !c.visible(p) {
+		err = eposts.ErrSlugNotFound
+	}
 	if err != nil {
-		c.ServeBadRequest(err.Error())
+		c.serveReadError(err)
+		return
+	}
+	if p.Slug != slug {
+		location, err := urlFor("PostsController.FindPostBySlug", "slug", p.Slug)
+		if err != nil {
+			c.ServeInternalError(err.Error())
+			return
+		}
+		c.ServeRedirect(http.StatusMovedPermanently, location)
 		return
 	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
+	c.SetLastModified(p.UpdatedAt)
 	c.ServeOK(p)
 }
 
@@ -81,14 +187,79 @@ func (c *PostsController) UpdatePost() {
 		return
 	}
 	p.ID = eposts.PostID(id)
-	err = c.service.UpdatePost(ctx, &p)
+	stored, ok := c.authorizePost(policy.PostsUpdate, p.ID)
+	if !ok {
+		return
+	}
+	if p.UserID == 0 {
+		p.UserID = stored.UserID
+	}
+	if !c.Authorize(policy.PostsUpdate, p) {
+		return
+	}
+	p.Version, ok = c.ifMatch(p.ID)
+	if !ok {
+		c.ServePreconditionFailed(eposts.ErrVersionMismatch.Error())
+		return
+	}
+	err = c.service.UpdatePost(ctx, &p, userentities.UserID(c.UserID()))
 	if err != nil {
-		c.ServeBadRequest(err.Error())
+		c.serveWriteError(err)
 		return
 	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
 	c.ServeCreated(p)
 }
 
+// PatchPost partially updates a post with a JSON Merge Patch or a JSON Patch.
+func (c *PostsController) PatchPost() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	user, ok := c.Authenticate()
+	if !ok {
+		return
+	}
+	version, ok := c.ifMatch(eposts.PostID(id))
+	if !ok {
+		c.ServePreconditionFailed(eposts.ErrVersionMismatch.Error())
+		return
+	}
+	// The patch is read before the post is locked, so that slow clients don't hold the lock.
+	patch, err := c.ParsePatch()
+	if err != nil {
+		if errors.Is(err, api.ErrUnsupportedPatchType) {
+			c.ServeUnsupportedMediaType(err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	// The post is authorized both as stored and as patched, so that authors can't give their posts away.
+	p, err := c.service.PatchPost(ctx, eposts.PostID(id), version, userentities.UserID(c.UserID()), func(post *eposts.Post) error {
+		if !c.visible(*post) {
+			return eposts.ErrPostNotFound
+		}
+		if err := policy.Authorize(user, policy.PostsUpdate, *post); err != nil {
+			return err
+		}
+		if err := patch.Apply(post); err != nil {
+			return err
+		}
+		return policy.Authorize(user, policy.PostsUpdate, *post)
+	})
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
+	c.ServeOK(p)
+}
+
 // CreatePost creates a post.
 func (c *PostsController) CreatePost() {
 	ctx := context.Background()
@@ -98,11 +269,18 @@ func (c *PostsController) CreatePost() {
 		c.ServeBadRequest(err.Error())
 		return
 	}
-	err = c.service.CreatePost(ctx, &p)
+	if p.UserID == 0 {
+		p.UserID = userentities.UserID(c.UserID())
+	}
+	if !c.Authorize(policy.PostsCreate, p) {
+		return
+	}
+	err = c.service.CreatePost(ctx, &p, userentities.UserID(c.UserID()))
 	if err != nil {
 		c.ServeBadRequest(err.Error())
 		return
 	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
 	c.ServeCreated(p)
 }
 
@@ -115,10 +293,505 @@ func (c *PostsController) DeletePost() {
 		c.ServeBadRequest(err.Error())
 		return
 	}
-	err = c.service.DeletePost(ctx, eposts.PostID(id))
+	if _, ok := c.authorizePost(policy.PostsDelete, eposts.PostID(id)); !ok {
+		return
+	}
+	version, ok := c.ifMatch(eposts.PostID(id))
+	if !ok {
+		c.ServePreconditionFailed(eposts.ErrVersionMismatch.Error())
+		return
+	}
+	err = c.service.DeletePost(ctx, eposts.PostID(id), version)
 	if err != nil {
-		c.ServeBadRequest(err.Error())
+		c.serveWriteError(err)
 		return
 	}
 	c.ServeMessageOK("post deleted")
 }
+
+// CreatePosts creates a batch of posts, ?atomic=false lets every post succeed or fail on its own.
+func (c *PostsController) CreatePosts() {
+	ctx := context.Background()
+	ps, atomic, err := c.parseBatch()
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	for i := range ps {
+		if ps[i].UserID == 0 {
+			ps[i].UserID = userentities.UserID(c.UserID())
+		}
+	}
+	if !c.authorizeBatch(policy.PostsCreate, ps, false) {
+		return
+	}
+	results, err := c.service.CreatePosts(ctx, ps, userentities.UserID(c.UserID()), atomic)
+	c.serveBatch(results, err, http.StatusCreated, true)
+}
+
+// UpdatePosts updates a batch of posts, ?atomic=false lets every post succeed or fail on its own.
+func (c *PostsController) UpdatePosts() {
+	ctx := context.Background()
+	ps, atomic, err := c.parseBatch()
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if !c.authorizeBatch(policy.PostsUpdate, ps, true) {
+		return
+	}
+	results, err := c.service.UpdatePosts(ctx, ps, userentities.UserID(c.UserID()), atomic)
+	c.serveBatch(results, err, http.StatusCreated, true)
+}
+
+// DeletePosts deletes a batch of posts, ?atomic=false lets every post succeed or fail on its own.
+func (c *PostsController) DeletePosts() {
+	ctx := context.Background()
+	ps, atomic, err := c.parseBatch()
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if !c.authorizeBatch(policy.PostsDelete, ps, true) {
+		return
+	}
+	results, err := c.service.DeletePosts(ctx, ps, atomic)
+	c.serveBatch(results, err, http.StatusOK, false)
+}
+
+// ExportPosts streams posts matching filter[...] params as a file, ?format= is one of csv, json (default) or ndjson,
+// and ?columns= lists CSV columns.
+func (c *PostsController) ExportPosts() {
+	ctx := context.Background()
+	user, ok := c.Authenticate()
+	if !ok {
+		return
+	}
+	// Users granted the export of their own posts only export the posts they can see, like IndexPosts lists them.
+	viewer := eposts.AnyViewer
+	if !policy.Can(user, policy.PostsExport, nil) {
+		viewer = userentities.UserID(user.ID)
+		if !c.Authorize(policy.PostsExport, eposts.Post{UserID: viewer}) {
+			return
+		}
+	}
+	params := c.ParseQueryParams()
+	opts, err := eposts.PostResource.Parse(params)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	format := eposts.TransferFormat(params.Get("format"))
+	if format == "" {
+		format = eposts.TransferJSON
+	}
+	if !format.Valid() {
+		c.ServeBadRequest(eposts.ErrUnknownTransferFormat.Error())
+		return
+	}
+	columns := strings.FieldsFunc(params.Get("columns"), func(r rune) bool {
+		return r == ','
+	})
+	c.ServeStream(format.ContentType(), "posts."+string(format), func(w io.Writer) error {
+		_, err := c.service.ExportPosts(ctx, w, format, columns, viewer, opts)
+		return err
+	})
+}
+
+// ImportPosts imports posts from the body, ?format= is one of csv, json (default) or ndjson, ?mode= matches stored
+// posts by id (default) or slug, ?dry_run=true only validates them, and ?map=Header:column,... maps CSV headers.
+// Responds with a report, 207 Multi-Status is used if any of the records failed.
+func (c *PostsController) ImportPosts() {
+	ctx := context.Background()
+	if !c.Authorize(policy.PostsImport, nil) {
+		return
+	}
+	opts, err := c.parseImport()
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	report, err := c.service.ImportPosts(ctx, c.Body(), opts, userentities.UserID(c.UserID()))
+	switch {
+	case err != nil && report.Records == 0:
+		c.ServeBadRequest(err.Error())
+	case err != nil:
+		c.ServeJSON(http.StatusBadRequest, report)
+	case report.Failed > 0:
+		c.ServeJSON(http.StatusMultiStatus, report)
+	default:
+		c.ServeOK(report)
+	}
+}
+
+// PublishPost publishes a post, or schedules it if an "at" time in the future is provided.
+func (c *PostsController) PublishPost() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, ok := c.authorizePost(policy.PostsPublish, eposts.PostID(id)); !ok {
+		return
+	}
+	var body struct {
+		At time.Time `json:"at"`
+	}
+	err = c.ParseJSONBody(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	p, err := c.service.PublishPost(ctx, eposts.PostID(id), body.At)
+	c.serveTransition(p, err)
+}
+
+// UnpublishPost moves a post back to drafts.
+func (c *PostsController) UnpublishPost() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, ok := c.authorizePost(policy.PostsPublish, eposts.PostID(id)); !ok {
+		return
+	}
+	p, err := c.service.UnpublishPost(ctx, eposts.PostID(id))
+	c.serveTransition(p, err)
+}
+
+// ArchivePost archives a post.
+func (c *PostsController) ArchivePost() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, ok := c.authorizePost(policy.PostsPublish, eposts.PostID(id)); !ok {
+		return
+	}
+	p, err := c.service.ArchivePost(ctx, eposts.PostID(id))
+	c.serveTransition(p, err)
+}
+
+// IndexRevisions fetches all revisions of a post.
+func (c *PostsController) IndexRevisions() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, ok := c.findVisiblePost(eposts.PostID(id)); !ok {
+		return
+	}
+	revisions, err := c.service.IndexRevisions(ctx, eposts.PostID(id))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(revisions)
+}
+
+// DiffRevisions serves a unified diff between ?from= and ?to= revisions of a post.
+func (c *PostsController) DiffRevisions() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if _, ok := c.findVisiblePost(eposts.PostID(id)); !ok {
+		return
+	}
+	from, err := strconv.Atoi(c.ParseQueryParams().Get("from"))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	to, err := strconv.Atoi(c.ParseQueryParams().Get("to"))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	diff, err := c.service.DiffRevisions(ctx, eposts.PostID(id), eposts.RevisionID(from), eposts.RevisionID(to))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeBytes(http.StatusOK, "text/x-diff; charset=utf-8", []byte(diff))
+}
+
+// RevertPost restores a post to one of its revisions.
+func (c *PostsController) RevertPost() {
+	ctx := context.Background()
+	params := c.ParseURLParams()
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	revision, err := strconv.Atoi(params["revision"])
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	stored, ok := c.authorizePost(policy.PostsUpdate, eposts.PostID(id))
+	if !ok {
+		return
+	}
+	// The post is authorized both as stored and as reverted, since reverting restores the owner of the snapshot.
+	snapshot, err := c.service.FindRevision(ctx, stored.ID, eposts.RevisionID(revision))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	reverted := stored
+	reverted.UserID = snapshot.UserID
+	if !c.Authorize(policy.PostsUpdate, reverted) {
+		return
+	}
+	version, ok := c.ifMatch(stored.ID)
+	if !ok {
+		c.ServePreconditionFailed(eposts.ErrVersionMismatch.Error())
+		return
+	}
+	p, err := c.service.RevertPost(ctx, stored.ID, snapshot.ID, version, userentities.UserID(c.UserID()))
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
+	c.ServeOK(p)
+}
+
+// findVisiblePost fetches a post the current user can see, serving 404 Not Found otherwise.
+func (c *PostsController) findVisiblePost(id eposts.PostID) (eposts.Post, bool) {
+	p, err := c.service.FindPost(context.Background(), id)
+	if err == nil && !c.visible(p) {
+		err = eposts.ErrPostNotFound
+	}
+	if err != nil {
+		c.serveReadError(err)
+		return eposts.Post{}, false
+	}
+	return p, true
+}
+
+// visible checks whether the current user can see a post the way IndexPosts lists them: published posts are public,
+// others are only seen by their owner and by users allowed to update them. Posts users can't see are served as if
+// they didn't exist, so that their ids and slugs don't leak.
+func (c *PostsController) visible(p eposts.Post) bool {
+	if p.Status == eposts.StatusPublished {
+		return true
+	}
+	identity, ok := c.CurrentUser()
+	return ok && (identity.UserID == uint64(p.UserID) || policy.Can(identity.PolicyUser(), policy.PostsUpdate, p))
+}
+
+// viewer resolves the user posts are listed for, so that lists show the same posts visible lets through: users
+// allowed to update any post see every post, others see published posts and their own.
+func (c *PostsController) viewer() userentities.UserID {
+	identity, ok := c.CurrentUser()
+	if ok && policy.Can(identity.PolicyUser(), policy.PostsUpdate, nil) {
+		return eposts.AnyViewer
+	}
+	return userentities.UserID(c.UserID())
+}
+
+// serveReadError serves an error thrown by a read operation.
+func (c *PostsController) serveReadError(err error) {
+	if errors.Is(err, eposts.ErrPostNotFound) || errors.Is(err, eposts.ErrSlugNotFound) {
+		c.ServeMessage(http.StatusNotFound, err.Error())
+		return
+	}
+	c.ServeBadRequest(err.Error())
+}
+
+// authorizePost fetches a stored post and authorizes action on it, serving an error otherwise. Posts the current user
+// can't see are served as not found rather than forbidden.
+func (c *PostsController) authorizePost(action string, id eposts.PostID) (eposts.Post, bool) {
+	if _, ok := c.Authenticate(); !ok {
+		return eposts.Post{}, false
+	}
+	p, ok := c.findVisiblePost(id)
+	if !ok {
+		return eposts.Post{}, false
+	}
+	return p, c.Authorize(action, p)
+}
+
+// authorizeBatch authorizes action on every item of a batch up front, so that a batch is either allowed or forbidden
+// as a whole. If stored is set, items are also authorized as they're stored and default to the stored owner, items
+// that aren't found are left to the write to fail.
+func (c *PostsController) authorizeBatch(action string, ps []eposts.Post, stored bool) bool {
+	user, ok := c.Authenticate()
+	if !ok {
+		return false
+	}
+	for i := range ps {
+		if stored {
+			found, err := c.service.FindPost(context.Background(), ps[i].ID)
+			if err != nil {
+				continue
+			}
+			if err = policy.Authorize(user, action, found); err != nil {
+				c.ServeForbidden(fmt.Sprintf("item %v: %v", i, err))
+				return false
+			}
+			if ps[i].UserID == 0 {
+				ps[i].UserID = found.UserID
+			}
+		}
+		if err := policy.Authorize(user, action, ps[i]); err != nil {
+			c.ServeForbidden(fmt.Sprintf("item %v: %v", i, err))
+			return false
+		}
+	}
+	return true
+}
+
+// serveTransition serves a result of a lifecycle transition.
+func (c *PostsController) serveTransition(p eposts.Post, err error) {
+	if err != nil {
+		if errors.Is(err, eposts.ErrInvalidTransition) {
+			c.ServeConflict(err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.SetETag(c.RepresentationTag(p.ETag()))
+	c.ServeOK(p)
+}
+
+// ifMatch resolves If-Match header into the version a write is conditioned on.
+// Missing header and a wildcard resolve to any version, returns false if none of the tags describe the post.
+func (c *PostsController) ifMatch(id eposts.PostID) (eposts.Version, bool) {
+	tags, ok := c.ParseIfMatch()
+	if !ok {
+		return eposts.AnyVersion, true
+	}
+	for _, tag := range tags {
+		if tag == "*" {
+			return eposts.AnyVersion, true
+		}
+		if version, ok := eposts.ParseETag(id, tag); ok {
+			return version, true
+		}
+	}
+	return eposts.AnyVersion, false
+}
+
+// serveWriteError serves an error thrown by a write operation.
+func (c *PostsController) serveWriteError(err error) {
+	c.ServeMessage(writeStatus(err), err.Error())
+}
+
+// parseBatch parses a batch write body and ?atomic= param, which defaults to true.
+// if_match of an item is resolved into the version the write of the item is conditioned on.
+func (c *PostsController) parseBatch() ([]eposts.Post, bool, error) {
+	atomic := true
+	if raw := c.ParseQueryParams().Get("atomic"); raw != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(raw); err != nil {
+			return nil, false, err
+		}
+	}
+	var body batchRequest
+	if err := c.ParseJSONBody(&body); err != nil {
+		return nil, false, err
+	}
+	ps := make([]eposts.Post, 0, len(body.Posts))
+	for i, item := range body.Posts {
+		if item.IfMatch != "" && item.IfMatch != "*" {
+			version, ok := eposts.ParseETag(item.ID, item.IfMatch)
+			if !ok {
+				return nil, false, fmt.Errorf("if_match of item %v doesn't describe post %v", i, item.ID)
+			}
+			item.Post.Version = version
+		}
+		ps = append(ps, item.Post)
+	}
+	return ps, atomic, nil
+}
+
+// parseImport parses params of an import.
+func (c *PostsController) parseImport() (eposts.ImportOptions, error) {
+	params := c.ParseQueryParams()
+	opts := eposts.ImportOptions{
+		Format:  eposts.TransferFormat(params.Get("format")),
+		Mode:    eposts.ImportMode(params.Get("mode")),
+		Columns: make(map[string]string),
+	}
+	if opts.Format == "" {
+		opts.Format = eposts.TransferJSON
+	}
+	if opts.Mode == "" {
+		opts.Mode = eposts.ImportByID
+	}
+	if raw := params.Get("dry_run"); raw != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(raw); err != nil {
+			return eposts.ImportOptions{}, err
+		}
+	}
+	for _, pair := range strings.FieldsFunc(params.Get("map"), func(r rune) bool {
+		return r == ','
+	}) {
+		header, column, ok := strings.Cut(pair, ":")
+		if !ok {
+			return eposts.ImportOptions{}, fmt.Errorf("map has to be a list of header:column pairs, got %v", pair)
+		}
+		opts.Columns[header] = column
+	}
+	return opts, nil
+}
+
+// serveBatch serves results of a batch write, 207 Multi-Status is used if any of the items failed.
+func (c *PostsController) serveBatch(results []eposts.BatchResult, err error, success int, withPosts bool) {
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	status := http.StatusOK
+	response := batchResponse{Results: make([]batchResult, 0, len(results))}
+	for i, result := range results {
+		item := batchResult{Index: i, Status: success}
+		switch {
+		case result.Err != nil:
+			item.Status = writeStatus(result.Err)
+			item.Error = result.Err.Error()
+			status = http.StatusMultiStatus
+		case withPosts:
+			p := result.Post
+			item.ETag = p.ETag()
+			item.Post = &p
+		}
+		response.Results = append(response.Results, item)
+	}
+	c.ServeJSON(status, response)
+}
+
+// writeStatus maps an error thrown by a write operation to a response status.
+func writeStatus(err error) int {
+	switch {
+	case errors.Is(err, eposts.ErrPostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, eposts.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, eposts.ErrBatchAborted):
+		return http.StatusFailedDependency
+	case errors.Is(err, policy.ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
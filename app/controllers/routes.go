@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+)
+
+// urlFor builds the path of a route served by handler, like "PostsController.FindPost", out of params given as name,
+// value pairs, see api.ReverseURL.
+func urlFor(handler string, params ...string) (string, error) {
+	pattern, ok := Routes[handler]
+	if !ok {
+		return "", fmt.Errorf("no route is served by %v", handler)
+	}
+	return api.ReverseURL(pattern, params...)
+}
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	eauth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	iauth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/interfaces"
+	auth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/logic"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/passwords"
+	eusers "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SessionsController is a wrapper for controllers that log users in and manage their sessions.
+type SessionsController struct {
+	api.ControllerSuite
+	service iauth.AuthService
+	cookie  sessionCookie
+}
+
+// credentialsRequest is a body of a login request.
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// passwordRequest is a body of a password change request.
+type passwordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *SessionsController) MustInitialize() {
+	c.service = mustAuthService(context.Background())
+	c.cookie = mustSessionCookie()
+}
+
+// CreateSession logs a user in with email and password, setting a session cookie.
+func (c *SessionsController) CreateSession() {
+	ctx := context.Background()
+	var credentials credentialsRequest
+	err := c.ParseJSONBody(&credentials)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	client := eauth.Client{UserAgent: c.UserAgent(), IP: c.RemoteIP()}
+	session, token, err := c.service.Login(ctx, credentials.Email, credentials.Password, client)
+	var lockout eauth.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		c.ServeTooManyRequests(err.Error(), time.Until(lockout.Until))
+		return
+	case errors.Is(err, eauth.ErrInvalidCredentials):
+		c.ServeUnauthorized(err.Error())
+		return
+	case err != nil:
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.SetCookie(c.cookie.new(token, session.ExpiresAt))
+	session.Current = true
+	c.ServeCreated(session)
+}
+
+// DeleteSession logs the current session out, clearing the session cookie.
+func (c *SessionsController) DeleteSession() {
+	ctx := context.Background()
+	identity, ok := c.CurrentUser()
+	if !ok || identity.SessionID == "" {
+		c.ServeUnauthorized(eauth.ErrSessionNotFound.Error())
+		return
+	}
+	err := c.service.RevokeSession(ctx, eusers.UserID(identity.UserID), identity.SessionID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.SetCookie(c.cookie.clear())
+	c.ServeMessageOK("logged out")
+}
+
+// IndexSessions fetches unexpired sessions of the current user.
+func (c *SessionsController) IndexSessions() {
+	ctx := context.Background()
+	identity, ok := c.self()
+	if !ok {
+		return
+	}
+	sessions, err := c.service.IndexSessions(ctx, eusers.UserID(identity.UserID))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == identity.SessionID
+	}
+	c.ServeOK(sessions)
+}
+
+// RevokeSession logs out a single session of the current user.
+func (c *SessionsController) RevokeSession() {
+	ctx := context.Background()
+	identity, ok := c.self()
+	if !ok {
+		return
+	}
+	id := c.ParseURLParams()["session"]
+	err := c.service.RevokeSession(ctx, eusers.UserID(identity.UserID), id)
+	if err != nil {
+		if errors.Is(err, eauth.ErrSessionNotFound) {
+			c.ServeMessage(http.StatusNotFound, err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if id == identity.SessionID {
+		c.SetCookie(c.cookie.clear())
+	}
+	c.ServeMessageOK("session revoked")
+}
+
+// RevokeSessions logs out all the other sessions of the current user.
+func (c *SessionsController) RevokeSessions() {
+	ctx := context.Background()
+	identity, ok := c.self()
+	if !ok {
+		return
+	}
+	revoked, err := c.service.RevokeSessions(ctx, eusers.UserID(identity.UserID), identity.SessionID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessageOK(fmt.Sprintf("%v sessions revoked", revoked))
+}
+
+// ChangePassword changes a password of the current user, logging out all the other sessions.
+func (c *SessionsController) ChangePassword() {
+	ctx := context.Background()
+	identity, ok := c.self()
+	if !ok {
+		return
+	}
+	var request passwordRequest
+	err := c.ParseJSONBody(&request)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	id := eusers.UserID(identity.UserID)
+	err = c.service.ChangePassword(ctx, id, request.CurrentPassword, request.Password)
+	if err != nil {
+		if errors.Is(err, eauth.ErrInvalidCredentials) {
+			c.ServeForbidden(err.Error())
+			return
+		}
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	_, err = c.service.RevokeSessions(ctx, id, identity.SessionID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessageOK("password changed")
+}
+
+// self checks that the user in url is the one logged in, serving 401 or 403 otherwise.
+func (c *SessionsController) self() (api.Identity, bool) {
+	return self(&c.ControllerSuite, "sessions")
+}
+
+// self checks that the user in url is the one logged in with unrestricted credentials, so that API keys can't be used
+// to manage credentials. Serves 401 or 403 otherwise.
+func self(c *api.ControllerSuite, managed string) (api.Identity, bool) {
+	identity, ok := c.CurrentUser()
+	if !ok {
+		c.ServeUnauthorized("authentication required")
+		return api.Identity{}, false
+	}
+	if c.ParseURLParams()["id"] != strconv.FormatUint(identity.UserID, 10) {
+		c.ServeForbidden(fmt.Sprintf("%v can only be managed by their own user", managed))
+		return api.Identity{}, false
+	}
+	if identity.Scopes != nil {
+		c.ServeForbidden(fmt.Sprintf("%v can't be managed with api keys", managed))
+		return api.Identity{}, false
+	}
+	return identity, true
+}
+
+// SessionsMiddleware authenticates requests carrying a session cookie, so that controllers can access CurrentUser.
+// Requests with an invalid or expired session are served anonymously.
+type SessionsMiddleware struct {
+	service iauth.AuthService
+	cookie  sessionCookie
+}
+
+// MustInitialize performs all the setup needed for the middleware, and starts periodic cleanup of expired sessions.
+func (m *SessionsMiddleware) MustInitialize() {
+	ctx := context.Background()
+	m.service = mustAuthService(ctx)
+	m.cookie = mustSessionCookie()
+	interval, err := time.ParseDuration(config.MustConfig()["sessions.cleanup_interval"])
+	if err != nil {
+		panic(err)
+	}
+	go m.cleanup(ctx, interval)
+}
+
+// Wrap implements api.Middleware.
+func (m *SessionsMiddleware) Wrap(serve api.Serve) api.Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		cookie, err := request.Cookie(m.cookie.name)
+		if err != nil || cookie.Value == "" {
+			serve(writer, request)
+			return
+		}
+		session, err := m.service.Authenticate(context.Background(), cookie.Value)
+		if err != nil {
+			if !errors.Is(err, eauth.ErrSessionNotFound) {
+				logrus.WithError(err).Errorf("failed to authenticate session")
+			}
+			serve(writer, request)
+			return
+		}
+		serve(writer, api.WithIdentity(request, api.Identity{UserID: uint64(session.UserID), SessionID: session.ID}))
+	}
+}
+
+// cleanup periodically deletes expired sessions.
+func (m *SessionsMiddleware) cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		deleted, err := m.service.CleanupSessions(ctx)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to clean up sessions")
+			continue
+		}
+		logrus.Infof("cleaned up %v expired sessions", deleted)
+	}
+}
+
+// sessionCookie builds session cookies according to configs.
+type sessionCookie struct {
+	name   string
+	secure bool
+}
+
+// new builds a cookie carrying a session token.
+func (s sessionCookie) new(token string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     s.name,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   s.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// clear builds a cookie removing the session token from client.
+func (s sessionCookie) clear() *http.Cookie {
+	cookie := s.new("", time.Unix(0, 0))
+	cookie.MaxAge = -1
+	return cookie
+}
+
+// mustSessionCookie reads session cookie configs, panicking if they're malformed.
+func mustSessionCookie() sessionCookie {
+	cfg := config.MustConfig()
+	secure, err := strconv.ParseBool(cfg["sessions.secure_cookie"])
+	if err != nil {
+		panic(err)
+	}
+	return sessionCookie{name: cfg["sessions.cookie"], secure: secure}
+}
+
+// mustAuthService instantiates an AuthService with configured settings, panicking on failure.
+func mustAuthService(ctx context.Context) *auth.AuthService {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	cfg := config.MustConfig()
+	settings := eauth.Settings{Params: passwords.DefaultParams}
+	memory, err := strconv.ParseUint(cfg["auth.argon2_memory"], 10, 32)
+	if err != nil {
+		panic(err)
+	}
+	iterations, err := strconv.ParseUint(cfg["auth.argon2_iterations"], 10, 32)
+	if err != nil {
+		panic(err)
+	}
+	parallelism, err := strconv.ParseUint(cfg["auth.argon2_parallelism"], 10, 8)
+	if err != nil {
+		panic(err)
+	}
+	settings.Params.Memory = uint32(memory)
+	settings.Params.Iterations = uint32(iterations)
+	settings.Params.Parallelism = uint8(parallelism)
+	if settings.MaxFailedLogins, err = strconv.Atoi(cfg["auth.max_failed_logins"]); err != nil {
+		panic(err)
+	}
+	if settings.Lockout, err = time.ParseDuration(cfg["auth.lockout"]); err != nil {
+		panic(err)
+	}
+	if settings.SessionTTL, err = time.ParseDuration(cfg["sessions.ttl"]); err != nil {
+		panic(err)
+	}
+	service, err := auth.NewAuthService(ctx, reader, writer, settings)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
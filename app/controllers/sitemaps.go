@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	iposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/sitemaps"
+)
+
+// SitemapsController is a wrapper for controllers that serve sitemaps of published posts.
+type SitemapsController struct {
+	api.ControllerSuite
+	service iposts.PostsService
+	cache   *sitemaps.Cache
+	// link is the public URL of the app, sitemaps list absolute URLs.
+	link string
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *SitemapsController) MustInitialize() {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	c.service = service
+	cfg := config.MustConfig()
+	ttl, err := time.ParseDuration(cfg["sitemap.ttl"])
+	if err != nil {
+		panic(err)
+	}
+	c.cache = sitemaps.NewCache(ttl)
+	c.link = strings.TrimSuffix(cfg["self.url"], "/")
+}
+
+// Sitemap serves the sitemap of published posts, or an index of sitemap pages once there are more posts than a
+// single sitemap can list.
+func (c *SitemapsController) Sitemap() {
+	ctx := context.Background()
+	body, err := c.cache.Get("sitemap", func() ([]byte, error) {
+		pages, err := c.service.SitemapPages(ctx, sitemaps.MaxURLs)
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) <= 1 {
+			return c.renderPage(ctx, 1)
+		}
+		urls := make([]sitemaps.URL, 0, len(pages))
+		for i, updated := range pages {
+			path, err := urlFor("SitemapsController.SitemapPage", "page", strconv.Itoa(i+1))
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, sitemaps.URL{Loc: c.link + path, LastModified: updated})
+		}
+		return sitemaps.Index(urls)
+	})
+	c.serveSitemap(body, err)
+}
+
+// SitemapPage serves a page of the sitemap index.
+func (c *SitemapsController) SitemapPage() {
+	ctx := context.Background()
+	rawPage := c.ParseURLParams()["page"]
+	page, err := strconv.Atoi(rawPage)
+	if err != nil || page < 1 {
+		c.ServeMessage(http.StatusNotFound, sitemaps.ErrPageNotFound.Error())
+		return
+	}
+	body, err := c.cache.Get("page-"+strconv.Itoa(page), func() ([]byte, error) {
+		return c.renderPage(ctx, page)
+	})
+	c.serveSitemap(body, err)
+}
+
+// renderPage renders a sitemap listing a page of published posts, only the first page can be empty.
+func (c *SitemapsController) renderPage(ctx context.Context, page int) ([]byte, error) {
+	ps, err := c.service.SitemapPosts(ctx, page, sitemaps.MaxURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 && page > 1 {
+		return nil, sitemaps.ErrPageNotFound
+	}
+	urls := make([]sitemaps.URL, 0, len(ps))
+	for _, p := range ps {
+		path, err := urlFor("PostsController.FindPostBySlug", "slug", p.Slug)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, sitemaps.URL{Loc: c.link + path, LastModified: p.UpdatedAt})
+	}
+	return sitemaps.Sitemap(urls)
+}
+
+// serveSitemap serves a rendered sitemap.
+func (c *SitemapsController) serveSitemap(body []byte, err error) {
+	switch {
+	case errors.Is(err, sitemaps.ErrPageNotFound):
+		c.ServeMessage(http.StatusNotFound, err.Error())
+	case err != nil:
+		c.ServeBadRequest(err.Error())
+	default:
+		c.ServeBytes(http.StatusOK, sitemaps.ContentType, body)
+	}
+}
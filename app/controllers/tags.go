@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	iposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+)
+
+// TagsController is a wrapper for controllers that interact with tags.
+type TagsController struct {
+	api.ControllerSuite
+	service iposts.PostsService
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *TagsController) MustInitialize() {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	c.service = service
+}
+
+// IndexTags fetches all tags with their post counts.
+func (c *TagsController) IndexTags() {
+	ctx := context.Background()
+	tags, err := c.service.IndexTags(ctx)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(tags)
+}
+
+// FindTag fetches a single tag.
+func (c *TagsController) FindTag() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	t, err := c.service.FindTag(ctx, eposts.TagID(id))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeOK(t)
+}
+
+// UpdateTag renames a tag.
+func (c *TagsController) UpdateTag() {
+	ctx := context.Background()
+	if !c.Authorize(policy.TagsManage, nil) {
+		return
+	}
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	var t eposts.Tag
+	err = c.ParseJSONBody(&t)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	t.ID = eposts.TagID(id)
+	err = c.service.UpdateTag(ctx, &t)
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.ServeCreated(t)
+}
+
+// CreateTag creates a tag.
+func (c *TagsController) CreateTag() {
+	ctx := context.Background()
+	if !c.Authorize(policy.TagsManage, nil) {
+		return
+	}
+	var t eposts.Tag
+	err := c.ParseJSONBody(&t)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	err = c.service.CreateTag(ctx, &t)
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.ServeCreated(t)
+}
+
+// DeleteTag deletes a tag.
+func (c *TagsController) DeleteTag() {
+	ctx := context.Background()
+	if !c.Authorize(policy.TagsManage, nil) {
+		return
+	}
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	err = c.service.DeleteTag(ctx, eposts.TagID(id))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessageOK("tag deleted")
+}
+
+// serveWriteError serves an error thrown by a write operation.
+func (c *TagsController) serveWriteError(err error) {
+	if errors.Is(err, eposts.ErrDuplicateTag) {
+		c.ServeConflict(err.Error())
+		return
+	}
+	c.ServeBadRequest(err.Error())
+}
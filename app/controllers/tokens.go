@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	eauth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	iauth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/jwt"
+	etokens "github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/entities"
+	itokens "github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/interfaces"
+	tokens "github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/logic"
+	eusers "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TokensController is a wrapper for controllers that issue JWTs.
+type TokensController struct {
+	api.ControllerSuite
+	auth    iauth.AuthService
+	service itokens.TokensService
+}
+
+// refreshRequest is a body of a token refresh or revocation request.
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *TokensController) MustInitialize() {
+	ctx := context.Background()
+	c.auth = mustAuthService(ctx)
+	c.service = mustTokensService(ctx)
+}
+
+// CreateToken issues a token pair for a user logging in with email and password, or for the user logged in with a
+// session cookie if the body is empty.
+func (c *TokensController) CreateToken() {
+	ctx := context.Background()
+	var credentials credentialsRequest
+	err := c.ParseJSONBody(&credentials)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	var id eusers.UserID
+	if identity, ok := c.CurrentUser(); ok && identity.SessionID != "" && credentials.Email == "" {
+		id, err = eusers.UserID(identity.UserID), nil
+	} else {
+		id, err = c.auth.VerifyCredentials(ctx, credentials.Email, credentials.Password)
+	}
+	var lockout eauth.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		c.ServeTooManyRequests(err.Error(), time.Until(lockout.Until))
+		return
+	case errors.Is(err, eauth.ErrInvalidCredentials):
+		c.ServeUnauthorized(err.Error())
+		return
+	case err != nil:
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	pair, err := c.service.Issue(ctx, id)
+	if err != nil {
+		c.ServeInternalError(err.Error())
+		return
+	}
+	c.ServeCreated(pair)
+}
+
+// RefreshToken exchanges a refresh token for a new token pair.
+func (c *TokensController) RefreshToken() {
+	ctx := context.Background()
+	var request refreshRequest
+	err := c.ParseJSONBody(&request)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	pair, err := c.service.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		if errors.Is(err, etokens.ErrInvalidRefreshToken) {
+			c.ServeUnauthorized(err.Error())
+			return
+		}
+		c.ServeInternalError(err.Error())
+		return
+	}
+	c.ServeCreated(pair)
+}
+
+// RevokeToken revokes a refresh token. Like RFC 7009 asks, invalid tokens are reported as revoked, as there's
+// nothing left to revoke.
+func (c *TokensController) RevokeToken() {
+	ctx := context.Background()
+	var request refreshRequest
+	err := c.ParseJSONBody(&request)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	err = c.service.Revoke(ctx, request.RefreshToken)
+	if err != nil && !errors.Is(err, etokens.ErrInvalidRefreshToken) {
+		c.ServeInternalError(err.Error())
+		return
+	}
+	c.ServeMessageOK("token revoked")
+}
+
+// JWTMiddleware authenticates requests carrying a JWT in the Authorization header, attaching its claims to them.
+// Tokens issued by this API also authenticate their subject as the current user. Requests with an invalid token are
+// rejected, other bearer tokens are left to other middlewares.
+type JWTMiddleware struct {
+	service itokens.TokensService
+	issuer  string
+}
+
+// MustInitialize performs all the setup needed for the middleware, and starts periodic cleanup of expired refresh
+// tokens.
+func (m *JWTMiddleware) MustInitialize() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	m.service = mustTokensService(ctx)
+	m.issuer = cfg["jwt.issuer"]
+	interval, err := time.ParseDuration(cfg["jwt.cleanup_interval"])
+	if err != nil {
+		panic(err)
+	}
+	go m.cleanup(ctx, interval)
+}
+
+// Wrap implements api.Middleware.
+func (m *JWTMiddleware) Wrap(serve api.Serve) api.Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		token, ok := bearerToken(request)
+		if !ok || strings.Count(token, ".") != 2 {
+			serve(writer, request)
+			return
+		}
+		claims, err := m.service.Verify(context.Background(), token)
+		if err != nil {
+			var suite api.ControllerSuite
+			suite.NewRequest(writer, request)
+			writer.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			suite.ServeUnauthorized(err.Error())
+			return
+		}
+		request = api.WithClaims(request, claims.Raw)
+		if id, err := strconv.ParseUint(claims.Subject, 10, 32); err == nil && claims.Issuer == m.issuer {
+			request = api.WithIdentity(request, api.Identity{UserID: id})
+		}
+		serve(writer, request)
+	}
+}
+
+// cleanup periodically deletes expired refresh tokens.
+func (m *JWTMiddleware) cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		deleted, err := m.service.Cleanup(ctx)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to clean up refresh tokens")
+			continue
+		}
+		logrus.Infof("cleaned up %v expired refresh tokens", deleted)
+	}
+}
+
+// bearerToken extracts a bearer token from the Authorization header.
+func bearerToken(request *http.Request) (string, bool) {
+	authorization := request.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[7:]), true
+}
+
+// mustTokensService instantiates a TokensService with configured keys and settings, panicking on failure.
+func mustTokensService(ctx context.Context) *tokens.TokensService {
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	cfg := config.MustConfig()
+	settings := etokens.Settings{Issuer: cfg["jwt.issuer"], Audience: cfg["jwt.audience"]}
+	for _, issuer := range strings.Split(cfg["jwt.trusted_issuers"], ",") {
+		if issuer = strings.TrimSpace(issuer); issuer != "" {
+			settings.TrustedIssuers = append(settings.TrustedIssuers, issuer)
+		}
+	}
+	if settings.AccessTTL, err = time.ParseDuration(cfg["jwt.access_ttl"]); err != nil {
+		panic(err)
+	}
+	if settings.RefreshTTL, err = time.ParseDuration(cfg["jwt.refresh_ttl"]); err != nil {
+		panic(err)
+	}
+	if settings.Leeway, err = time.ParseDuration(cfg["jwt.leeway"]); err != nil {
+		panic(err)
+	}
+	service, err := tokens.NewTokensService(ctx, writer, mustKeyStore(), settings)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+// mustKeyStore loads the configured secret and JWKS file, panicking on failure. The JWKS file is reloaded periodically,
+// so that keys can be rotated without a restart.
+func mustKeyStore() *jwt.KeyStore {
+	cfg := config.MustConfig()
+	var static jwt.KeySet
+	if cfg["jwt.secret"] != "" {
+		static = append(static, jwt.Key{ID: cfg["jwt.secret_kid"], Algorithm: jwt.HS256, Secret: []byte(cfg["jwt.secret"])})
+	}
+	path := cfg["jwt.jwks_file"]
+	if path == "" {
+		return jwt.NewKeyStore(static, cfg["jwt.signing_kid"])
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.BasePath(), path)
+	}
+	loaded, err := jwt.LoadJWKS(path)
+	if err != nil {
+		panic(err)
+	}
+	interval, err := time.ParseDuration(cfg["jwt.jwks_reload_interval"])
+	if err != nil {
+		panic(err)
+	}
+	store := jwt.NewKeyStore(append(loaded, static...), cfg["jwt.signing_kid"])
+	go reloadKeys(store, static, path, interval)
+	return store
+}
+
+// reloadKeys periodically reloads keys from a JWKS file, keeping the current keys if it fails to load.
+func reloadKeys(store *jwt.KeyStore, static jwt.KeySet, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		loaded, err := jwt.LoadJWKS(path)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to reload jwks file %v", path)
+			continue
+		}
+		store.SetKeys(append(loaded, static...))
+	}
+}
@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/api"
+	iauth "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/passwords"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	eusers "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	iusers "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/interfaces"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UsersController is a wrapper for controllers that interact with users.
+type UsersController struct {
+	api.ControllerSuite
+	service iusers.UsersService
+	auth    iauth.AuthService
+	// defaultRole is given to users, unless they're created by an admin with another role.
+	defaultRole policy.Role
+}
+
+// userRequest is a body of a user creation request, password is optional.
+type userRequest struct {
+	eusers.User
+	Password string `json:"password"`
+}
+
+// roleRequest is a body of a role change request.
+type roleRequest struct {
+	Role policy.Role `json:"role"`
+}
+
+// MustInitialize performs all the setup needed for the controller.
+func (c *UsersController) MustInitialize() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	c.service = mustUsersService(ctx)
+	c.auth = mustAuthService(ctx)
+	c.defaultRole = policy.Role(cfg["rbac.default_role"])
+	if err := c.defaultRole.Validate(); err != nil {
+		panic(err)
+	}
+}
+
+// IndexUsers fetches a page of users.
+func (c *UsersController) IndexUsers() {
+	ctx := context.Background()
+	opts, err := eusers.UserResource.Parse(c.ParseQueryParams())
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	us, page, err := c.service.IndexUsers(ctx, opts)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	for i := range us {
+		c.redactEmail(&us[i])
+	}
+	c.ServePage(api.Sparse(us, opts.Keys()), page.Next, page.Prev, page.Total)
+}
+
+// FindUser fetches a single user.
+func (c *UsersController) FindUser() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	u, err := c.service.FindUser(ctx, eusers.UserID(id))
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.redactEmail(&u)
+	c.ServeOK(u)
+}
+
+// UpdateUser updates a user.
+func (c *UsersController) UpdateUser() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if !c.Authorize(policy.UsersUpdate, eusers.User{ID: eusers.UserID(id)}) {
+		return
+	}
+	var u eusers.User
+	err = c.ParseJSONBody(&u)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	u.ID = eusers.UserID(id)
+	err = c.service.UpdateUser(ctx, &u)
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.ServeCreated(u)
+}
+
+// CreateUser creates a user, setting a password if one is provided.
+func (c *UsersController) CreateUser() {
+	ctx := context.Background()
+	var u userRequest
+	err := c.ParseJSONBody(&u)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if u.Password != "" {
+		if err = passwords.Validate(u.Password); err != nil {
+			c.ServeBadRequest(err.Error())
+			return
+		}
+	}
+	if identity, ok := c.CurrentUser(); u.Role == "" || !ok || !policy.Can(identity.PolicyUser(), policy.UsersRoles, nil) {
+		u.Role = c.defaultRole
+	}
+	err = c.service.CreateUser(ctx, &u.User)
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	if u.Password != "" {
+		if err = c.auth.SetPassword(ctx, u.ID, u.Password); err != nil {
+			c.ServeBadRequest(err.Error())
+			return
+		}
+	}
+	c.ServeCreated(u.User)
+}
+
+// DeleteUser deletes a user.
+func (c *UsersController) DeleteUser() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if !c.Authorize(policy.UsersDelete, eusers.User{ID: eusers.UserID(id)}) {
+		return
+	}
+	err = c.service.DeleteUser(ctx, eusers.UserID(id))
+	if err != nil {
+		c.serveWriteError(err)
+		return
+	}
+	c.ServeMessageOK("user deleted")
+}
+
+// SetRole changes a role of a user, admins can't change their own role so that there's always one left.
+func (c *UsersController) SetRole() {
+	ctx := context.Background()
+	rawID := c.ParseURLParams()["id"]
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	if !c.Authorize(policy.UsersRoles, eusers.User{ID: eusers.UserID(id)}) {
+		return
+	}
+	if uint64(id) == c.UserID() {
+		c.ServeForbidden("users can't change their own role")
+		return
+	}
+	var request roleRequest
+	err = c.ParseJSONBody(&request)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	err = c.service.SetRole(ctx, eusers.UserID(id), request.Role)
+	if err != nil {
+		c.ServeBadRequest(err.Error())
+		return
+	}
+	c.ServeMessageOK("role changed")
+}
+
+// redactEmail hides the email of a user from everyone but the user and those allowed to update any user.
+func (c *UsersController) redactEmail(u *eusers.User) {
+	if identity, ok := c.CurrentUser(); ok && policy.Can(identity.PolicyUser(), policy.UsersUpdate, *u) {
+		return
+	}
+	u.Email = ""
+}
+
+// serveWriteError serves an error thrown by a write operation.
+func (c *UsersController) serveWriteError(err error) {
+	if errors.Is(err, eusers.ErrDuplicateUser) || errors.Is(err, eusers.ErrDuplicateEmail) || errors.Is(err, eusers.ErrUserHasPosts) {
+		c.ServeConflict(err.Error())
+		return
+	}
+	c.ServeBadRequest(err.Error())
+}
+
+// RolesMiddleware loads the role of the authenticated user, so that controllers can authorize them with the policy.
+// Users deleted since they've been authenticated keep no role, and aren't allowed anything by the policy.
+type RolesMiddleware struct {
+	service iusers.UsersService
+}
+
+// MustInitialize performs all the setup needed for the middleware.
+func (m *RolesMiddleware) MustInitialize() {
+	m.service = mustUsersService(context.Background())
+}
+
+// Wrap implements api.Middleware.
+func (m *RolesMiddleware) Wrap(serve api.Serve) api.Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		identity, ok := api.RequestIdentity(request)
+		if !ok {
+			serve(writer, request)
+			return
+		}
+		user, err := m.service.FindUser(context.Background(), eusers.UserID(identity.UserID))
+		if err != nil && !errors.Is(err, eusers.ErrUserNotFound) {
+			logrus.WithError(err).Errorf("failed to load role of user %v", identity.UserID)
+		}
+		identity.Role = user.Role
+		serve(writer, api.WithIdentity(request, identity))
+	}
+}
+
+// mustUsersService instantiates a UsersService, panicking on failure.
+func mustUsersService(ctx context.Context) *users.UsersService {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		panic(err)
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err := users.NewUsersService(ctx, reader, writer)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
@@ -30,10 +30,17 @@ func main() {
 	}
 	logrus.Infof("starting the app at %v:%v", host, port)
 
+	// Trust proxies to report addresses of clients.
+	proxies, err := api.ParseTrustedProxies(cfg["http.trusted_proxies"])
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to parse trusted proxies")
+	}
+	api.SetTrustedProxies(proxies)
+
 	// Instantiate the api server.
 	controllers.MustInitialize()
-	server := api.NewServer(host, port, controllers.Controllers)
-	err := server.Run()
+	server := api.NewServer(host, port, controllers.Controllers, controllers.MustInitializeMiddlewares()...)
+	err = server.Run()
 	if err != nil {
 		logrus.WithError(err).Fatal("server existed with error")
 	}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ParseIfMatch returns entity tags listed in If-Match request header, returns false if the header is absent.
+// Weak tags are dropped, since If-Match requires strong comparison. A wildcard is returned as is.
+func (s *ControllerSuite) ParseIfMatch() ([]string, bool) {
+	values := s.request.Header.Values("If-Match")
+	if len(values) == 0 {
+		return nil, false
+	}
+	tags := make([]string, 0)
+	for _, tag := range parseETags(strings.Join(values, ",")) {
+		if !strings.HasPrefix(tag, "W/") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, true
+}
+
+// SetETag sets ETag response header, has to be called before the response is served.
+func (s *ControllerSuite) SetETag(tag string) {
+	s.writer.Header().Set("ETag", tag)
+}
+
+// RepresentationTag qualifies an entity tag of a resource with the representation it's served in, so that JSON and
+// JSON:API responses of the same resource version have different tags. Tags of JSON responses are left as is.
+func (s *ControllerSuite) RepresentationTag(tag string) string {
+	if !s.WantsJSONAPI() || !strings.HasSuffix(tag, "\"") {
+		return tag
+	}
+	return strings.TrimSuffix(tag, "\"") + ";jsonapi\""
+}
+
+// SetLastModified sets Last-Modified response header, has to be called before the response is served.
+func (s *ControllerSuite) SetLastModified(t time.Time) {
+	s.writer.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// CacheControl wraps an endpoint so that its responses carry a Cache-Control header, used by routes that declare one.
+// Responses may depend on who's asking, so they vary on the headers identities are read from, and the responses to
+// requests with an identity are only ever cached privately.
+func CacheControl(value string, serve Serve) Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		header := writer.Header()
+		if _, ok := RequestIdentity(request); ok {
+			header.Set("Cache-Control", privateCacheControl(value))
+		} else {
+			header.Set("Cache-Control", value)
+		}
+		header.Add("Vary", "Authorization, Cookie, X-API-Key")
+		serve(writer, request)
+	}
+}
+
+// privateCacheControl turns Cache-Control directives that let shared caches store a response into private ones.
+func privateCacheControl(value string) string {
+	directives := []string{"private"}
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		name, _, _ := strings.Cut(strings.ToLower(directive), "=")
+		if name != "" && name != "public" && name != "private" && name != "s-maxage" && name != "proxy-revalidate" {
+			directives = append(directives, directive)
+		}
+	}
+	return strings.Join(directives, ",")
+}
+
+// ServePreconditionFailed serves a 412 response with a provided message.
+func (s *ControllerSuite) ServePreconditionFailed(message string) {
+	s.ServeMessage(http.StatusPreconditionFailed, message)
+}
+
+// parseETags splits a comma-separated list of entity tags, keeping commas inside quotes.
+func parseETags(header string) []string {
+	tags := make([]string, 0)
+	quoted := false
+	start := 0
+	for i := 0; i <= len(header); i++ {
+		if i < len(header) && header[i] == '"' {
+			quoted = !quoted
+		}
+		if i == len(header) || (header[i] == ',' && !quoted) {
+			if tag := strings.TrimSpace(header[start:i]); tag != "" {
+				tags = append(tags, tag)
+			}
+			start = i + 1
+		}
+	}
+	return tags
+}
+
+// cacheable checks whether a response can be served conditionally.
+func (s *ControllerSuite) cacheable(status int) bool {
+	return status == http.StatusOK && (s.request.Method == http.MethodGet || s.request.Method == http.MethodHead)
+}
+
+// notModified evaluates If-None-Match and If-Modified-Since request headers against response validators.
+// If-Modified-Since is only considered when If-None-Match is absent.
+func (s *ControllerSuite) notModified() bool {
+	if header := s.request.Header.Values("If-None-Match"); len(header) > 0 {
+		etag := weakTag(s.writer.Header().Get("ETag"))
+		for _, tag := range parseETags(strings.Join(header, ",")) {
+			if tag == "*" || (etag != "" && weakTag(tag) == etag) {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(s.request.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(s.writer.Header().Get("Last-Modified"))
+	return err == nil && !modified.After(since)
+}
+
+// bodyETag derives a strong entity tag from a rendered response body.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("\"%x\"", sum[:16])
+}
+
+// weakTag strips the weakness indicator, so tags can be compared with the weak comparison function.
+func weakTag(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerSuite_ParseIfMatch(t *testing.T) {
+	cases := []struct {
+		header    []string
+		assertion func([]string, bool)
+	}{
+		// Missing.
+		{
+			header: nil,
+			assertion: func(tags []string, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		// List, weak tags are dropped.
+		{
+			header: []string{`"1-2", W/"1-3"`, `"a,b"`},
+			assertion: func(tags []string, ok bool) {
+				if assert.True(t, ok) {
+					assert.Equal(t, []string{`"1-2"`, `"a,b"`}, tags)
+				}
+			},
+		},
+		// Wildcard.
+		{
+			header: []string{"*"},
+			assertion: func(tags []string, ok bool) {
+				if assert.True(t, ok) {
+					assert.Equal(t, []string{"*"}, tags)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodPut, "/posts/1", nil)
+		for _, value := range c.header {
+			request.Header.Add("If-Match", value)
+		}
+		var s ControllerSuite
+		s.NewRequest(httptest.NewRecorder(), request)
+		c.assertion(s.ParseIfMatch())
+	}
+}
+
+func TestControllerSuite_renderBytes(t *testing.T) {
+	SetJSONConfig(JSONConfig{})
+	modified := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		method    string
+		header    map[string]string
+		etag      string
+		status    int
+		assertion func(*httptest.ResponseRecorder)
+	}{
+		// Generated ETag.
+		{
+			method: http.MethodGet,
+			status: http.StatusOK,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+				assert.Regexp(t, `^"[0-9a-f]{32}"$`, response.Header().Get("ETag"))
+				assert.Equal(t, "public", response.Header().Get("Cache-Control"))
+			},
+		},
+		// Matching If-None-Match.
+		{
+			method: http.MethodGet,
+			header: map[string]string{"If-None-Match": `"x", W/"1-2"`},
+			etag:   `"1-2"`,
+			status: http.StatusOK,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, response.Code)
+				assert.Empty(t, response.Body.String())
+				assert.Equal(t, `"1-2"`, response.Header().Get("ETag"))
+			},
+		},
+		// Stale If-None-Match wins over If-Modified-Since.
+		{
+			method: http.MethodGet,
+			header: map[string]string{"If-None-Match": `"1-1"`, "If-Modified-Since": modified.Format(http.TimeFormat)},
+			etag:   `"1-2"`,
+			status: http.StatusOK,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+			},
+		},
+		// If-Modified-Since.
+		{
+			method: http.MethodGet,
+			header: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			status: http.StatusOK,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, response.Code)
+			},
+		},
+		// Writes are never conditional.
+		{
+			method: http.MethodPut,
+			header: map[string]string{"If-None-Match": "*"},
+			status: http.StatusOK,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+				assert.Empty(t, response.Header().Get("ETag"))
+			},
+		},
+		// Errors are not cached.
+		{
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, response.Code)
+				assert.Empty(t, response.Header().Get("Cache-Control"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, "/posts/1", nil)
+		for k, v := range c.header {
+			request.Header.Set(k, v)
+		}
+		response := httptest.NewRecorder()
+		CacheControl("public", func(writer http.ResponseWriter, request *http.Request) {
+			var s ControllerSuite
+			s.NewRequest(writer, request)
+			if c.etag != "" {
+				s.SetETag(c.etag)
+			}
+			s.SetLastModified(modified)
+			s.ServeMessage(c.status, "message")
+		})(response, request)
+		c.assertion(response)
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	cases := []struct {
+		value    string
+		identity *Identity
+		expected string
+	}{
+		{value: "public,max-age=60", identity: nil, expected: "public,max-age=60"},
+		{value: "public,max-age=60", identity: &Identity{UserID: 1}, expected: "private,max-age=60"},
+		{value: "public, s-maxage=300, max-age=60", identity: &Identity{UserID: 1}, expected: "private,max-age=60"},
+		{value: "no-store", identity: &Identity{UserID: 1}, expected: "private,no-store"},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		if c.identity != nil {
+			request = WithIdentity(request, *c.identity)
+		}
+		response := httptest.NewRecorder()
+		CacheControl(c.value, func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		})(response, request)
+		assert.Equal(t, c.expected, response.Header().Get("Cache-Control"), c.value)
+		assert.Equal(t, "Authorization, Cookie, X-API-Key", response.Header().Get("Vary"))
+	}
+}
+
+func TestControllerSuite_RepresentationTag(t *testing.T) {
+	cases := []struct {
+		accept   string
+		expected string
+	}{
+		{accept: "", expected: `"1-2"`},
+		{accept: "application/json", expected: `"1-2"`},
+		{accept: JSONAPIContentType, expected: `"1-2;jsonapi"`},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+		request.Header.Set("Accept", c.accept)
+		var s ControllerSuite
+		s.NewRequest(httptest.NewRecorder(), request)
+		assert.Equal(t, c.expected, s.RepresentationTag(`"1-2"`), c.accept)
+	}
+}
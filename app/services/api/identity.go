@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+)
+
+// trustedProxies are the networks of proxies trusted to set X-Forwarded-For.
+var trustedProxies []*net.IPNet
+
+// identityKey is a request context key of the authenticated identity.
+type identityKey struct{}
+
+// claimsKey is a request context key of the claims of a verified bearer token.
+type claimsKey struct{}
+
+// Identity describes who a request has been authenticated as.
+type Identity struct {
+	// UserID is an id of the authenticated user.
+	UserID uint64
+	// SessionID is an id of the session the request has been authenticated with, empty for other credentials.
+	SessionID string
+	// Scopes restrict what the identity can do, nil for credentials that aren't restricted, like sessions.
+	Scopes []string
+	// Role is the role of the user, loaded once the user has been authenticated.
+	Role policy.Role
+}
+
+// PolicyUser describes the identity to the policy.
+func (i Identity) PolicyUser() policy.User {
+	return policy.User{ID: i.UserID, Role: i.Role}
+}
+
+// HasScope checks whether the identity is allowed to use endpoints that require a given scope.
+func (i Identity) HasScope(scope string) bool {
+	if i.Scopes == nil {
+		return true
+	}
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithIdentity attaches an authenticated identity to a request, used by authentication middlewares.
+func WithIdentity(request *http.Request, identity Identity) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), identityKey{}, identity))
+}
+
+// WithUserID attaches an authenticated user id to a request, used by authentication middlewares.
+func WithUserID(request *http.Request, id uint64) *http.Request {
+	return WithIdentity(request, Identity{UserID: id})
+}
+
+// WithClaims attaches the claims of a verified bearer token to a request, used by authentication middlewares.
+func WithClaims(request *http.Request, claims map[string]interface{}) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), claimsKey{}, claims))
+}
+
+// Claims returns the claims of the bearer token a request has been authenticated with, nil if there's none.
+func (s *ControllerSuite) Claims() map[string]interface{} {
+	if s.request == nil {
+		return nil
+	}
+	claims, _ := s.request.Context().Value(claimsKey{}).(map[string]interface{})
+	return claims
+}
+
+// CurrentUser returns the authenticated identity, false for anonymous requests.
+func (s *ControllerSuite) CurrentUser() (Identity, bool) {
+	if s.request == nil {
+		return Identity{}, false
+	}
+	return RequestIdentity(s.request)
+}
+
+// UserID returns an id of the authenticated user, 0 for anonymous requests.
+func (s *ControllerSuite) UserID() uint64 {
+	identity, _ := s.CurrentUser()
+	return identity.UserID
+}
+
+// Authenticate returns the current user as the policy sees it, serving 401 to anonymous requests.
+func (s *ControllerSuite) Authenticate() (policy.User, bool) {
+	identity, ok := s.CurrentUser()
+	if !ok {
+		s.ServeUnauthorized("authentication required")
+		return policy.User{}, false
+	}
+	return identity.PolicyUser(), true
+}
+
+// Authorize checks whether the current user is allowed to perform action on resource, see policy.Can. Serves 401 to
+// anonymous requests and 403 to users who aren't allowed, so controllers only have to return when it fails.
+func (s *ControllerSuite) Authorize(action string, resource interface{}) bool {
+	user, ok := s.Authenticate()
+	if !ok {
+		return false
+	}
+	if err := policy.Authorize(user, action, resource); err != nil {
+		s.ServeForbidden(err.Error())
+		return false
+	}
+	return true
+}
+
+// RequestIdentity returns the identity a request has been authenticated as, used by middlewares.
+func RequestIdentity(request *http.Request) (Identity, bool) {
+	identity, ok := request.Context().Value(identityKey{}).(Identity)
+	return identity, ok && identity.UserID != 0
+}
+
+// RequireScope wraps an endpoint so that identities restricted to scopes can only use it if they've been granted
+// scope, used by routes that declare one. Anonymous requests and unrestricted identities are let through.
+func RequireScope(scope string, serve Serve) Serve {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		identity, _ := RequestIdentity(request)
+		if !identity.HasScope(scope) {
+			var suite ControllerSuite
+			suite.NewRequest(writer, request)
+			writer.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%v"`, scope))
+			suite.ServeForbidden(fmt.Sprintf("%v scope is required", scope))
+			return
+		}
+		serve(writer, request)
+	}
+}
+
+// SetCookie adds a Set-Cookie header to response.
+func (s *ControllerSuite) SetCookie(cookie *http.Cookie) {
+	http.SetCookie(s.writer, cookie)
+}
+
+// UserAgent returns the User-Agent header of request.
+func (s *ControllerSuite) UserAgent() string {
+	return s.request.UserAgent()
+}
+
+// RemoteIP returns an ip address of the client. X-Forwarded-For is only honored for the addresses trusted proxies
+// added to it, read right to left, so that clients can't spoof their address.
+func (s *ControllerSuite) RemoteIP() string {
+	ip, _, err := net.SplitHostPort(s.request.RemoteAddr)
+	if err != nil {
+		ip = s.request.RemoteAddr
+	}
+	forwarded := strings.Split(strings.Join(s.request.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && trustedProxy(ip); i-- {
+		if address := strings.TrimSpace(forwarded[i]); address != "" {
+			ip = address
+		}
+	}
+	return ip
+}
+
+// ParseTrustedProxies parses a comma separated list of ip addresses and CIDR networks of trusted proxies.
+func ParseTrustedProxies(raw string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// SetTrustedProxies sets the networks of proxies trusted to set X-Forwarded-For, none are trusted by default. It has
+// to be called before the server starts.
+func SetTrustedProxies(proxies []*net.IPNet) {
+	trustedProxies = proxies
+}
+
+// trustedProxy checks whether an ip address belongs to a trusted proxy.
+func trustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireScope(t *testing.T) {
+	cases := []struct {
+		identity  *Identity
+		assertion func(*httptest.ResponseRecorder)
+	}{
+		// Anonymous.
+		{
+			identity: nil,
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+			},
+		},
+		// Unrestricted.
+		{
+			identity: &Identity{UserID: 1},
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+			},
+		},
+		// Granted.
+		{
+			identity: &Identity{UserID: 1, Scopes: []string{"posts:read", "posts:write"}},
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, response.Code)
+			},
+		},
+		// Not granted.
+		{
+			identity: &Identity{UserID: 1, Scopes: []string{"posts:read"}},
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, response.Code)
+				assert.Equal(t, `Bearer error="insufficient_scope", scope="posts:write"`, response.Header().Get("WWW-Authenticate"))
+			},
+		},
+		// No scopes.
+		{
+			identity: &Identity{UserID: 1, Scopes: []string{}},
+			assertion: func(response *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, response.Code)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		if c.identity != nil {
+			request = WithIdentity(request, *c.identity)
+		}
+		response := httptest.NewRecorder()
+		RequireScope("posts:write", func(writer http.ResponseWriter, request *http.Request) {
+			var s ControllerSuite
+			s.NewRequest(writer, request)
+			s.ServeMessageOK("message")
+		})(response, request)
+		c.assertion(response)
+	}
+}
+
+func TestControllerSuite_RemoteIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = ParseTrustedProxies("10.0.0.0/8, proxy")
+	assert.Error(t, err)
+	SetTrustedProxies(proxies)
+	defer SetTrustedProxies(nil)
+
+	cases := []struct {
+		remoteAddr string
+		forwarded  []string
+		ip         string
+	}{
+		// Direct client.
+		{remoteAddr: "203.0.113.7:1234", ip: "203.0.113.7"},
+		// Direct client spoofing its address.
+		{remoteAddr: "203.0.113.7:1234", forwarded: []string{"198.51.100.1"}, ip: "203.0.113.7"},
+		// Trusted proxy.
+		{remoteAddr: "10.1.2.3:1234", forwarded: []string{"198.51.100.1"}, ip: "198.51.100.1"},
+		// Chain of trusted proxies, the client spoofing an address in front of it.
+		{remoteAddr: "10.1.2.3:1234", forwarded: []string{"198.51.100.1, 203.0.113.7", "192.168.1.1"}, ip: "203.0.113.7"},
+		// Trusted proxy without a forwarded address.
+		{remoteAddr: "192.168.1.1:1234", ip: "192.168.1.1"},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+		request.RemoteAddr = c.remoteAddr
+		for _, value := range c.forwarded {
+			request.Header.Add("X-Forwarded-For", value)
+		}
+		var s ControllerSuite
+		s.NewRequest(httptest.NewRecorder(), request)
+		assert.Equal(t, c.ip, s.RemoteIP(), "%v %v", c.remoteAddr, c.forwarded)
+	}
+}
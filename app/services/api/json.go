@@ -0,0 +1,608 @@
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+)
+
+var (
+	// jsonConfigCache stores the value of JSONSettings after its first call, guarded by jsonConfigMutex since the first
+	// call is made by a request.
+	jsonConfigCache *JSONConfig
+	jsonConfigMutex sync.Mutex
+	// jsonEngine is the encoder used for rendering and binding, see SetJSONEngine.
+	jsonEngine JSONEngine = StdJSONEngine{}
+	// timeType is the reflected type of time.Time.
+	timeType = reflect.TypeOf(time.Time{})
+	// marshalerType is the reflected type of json.Marshaler.
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	// textMarshalerType is the reflected type of encoding.TextMarshaler.
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	// jsonFieldsCache stores the result of jsonFields per struct type.
+	jsonFieldsCache sync.Map
+	// unmarshalerType is the reflected type of json.Unmarshaler.
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+const (
+	// maxSafeInteger is the biggest integer that survives a round trip through a float64 (and therefore javascript).
+	maxSafeInteger = 1<<53 - 1
+)
+
+// JSONConfig describes how objects are serialized to and deserialized from json.
+type JSONConfig struct {
+	// Pretty toggles indented output, can also be requested with ?pretty query param.
+	Pretty bool
+	// EscapeHTML toggles escaping of <, > and & characters in strings.
+	EscapeHTML bool
+	// TimeLayout is the layout used to render and parse time.Time values.
+	TimeLayout string
+	// TimeLocation is the location every time.Time value is normalized to.
+	TimeLocation *time.Location
+	// BigIntAsString renders integers that overflow a float64 mantissa as strings, and accepts strings for integers.
+	BigIntAsString bool
+}
+
+// JSONEngine is an encoder/decoder pair that can be swapped for a faster implementation.
+// Values passed to an engine are already normalized according to JSONConfig.
+type JSONEngine interface {
+	Marshal(v any, escapeHTML bool) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// StdJSONEngine implements JSONEngine using encoding/json.
+type StdJSONEngine struct{}
+
+// Marshal ...
+func (StdJSONEngine) Marshal(v any, escapeHTML bool) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(escapeHTML)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// Unmarshal ...
+func (StdJSONEngine) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// SetJSONEngine replaces the engine used for rendering and binding.
+func SetJSONEngine(engine JSONEngine) {
+	jsonEngine = engine
+}
+
+// SetJSONConfig overrides the json settings loaded from configs.
+func SetJSONConfig(cfg JSONConfig) {
+	jsonConfigMutex.Lock()
+	defer jsonConfigMutex.Unlock()
+	jsonConfigCache = &cfg
+}
+
+// JSONSettings fetches json settings based on current ENV, caching the result.
+func JSONSettings() JSONConfig {
+	jsonConfigMutex.Lock()
+	defer jsonConfigMutex.Unlock()
+	if jsonConfigCache == nil {
+		cfg := evaluateJSONConfig(config.MustConfig())
+		jsonConfigCache = &cfg
+	}
+	return *jsonConfigCache
+}
+
+// evaluateJSONConfig builds JSONConfig out of json.* config options.
+func evaluateJSONConfig(cfg map[string]string) JSONConfig {
+	result := JSONConfig{
+		TimeLayout:   time.RFC3339Nano,
+		TimeLocation: time.UTC,
+	}
+	result.Pretty, _ = strconv.ParseBool(cfg["json.pretty"])
+	result.EscapeHTML, _ = strconv.ParseBool(cfg["json.escape_html"])
+	result.BigIntAsString, _ = strconv.ParseBool(cfg["json.bigint_as_string"])
+	if layout := cfg["json.time_layout"]; layout != "" {
+		result.TimeLayout = layout
+	}
+	if timezone := cfg["json.timezone"]; timezone != "" {
+		if location, err := time.LoadLocation(timezone); err == nil {
+			result.TimeLocation = location
+		}
+	}
+	return result
+}
+
+// MarshalJSON serializes an object according to provided settings.
+func MarshalJSON(object any, cfg JSONConfig) ([]byte, error) {
+	var marshalled bytes.Buffer
+	if err := writeJSON(&marshalled, normalizeOutput(reflect.ValueOf(object), cfg), cfg.EscapeHTML); err != nil {
+		return nil, err
+	}
+	if !cfg.Pretty {
+		return marshalled.Bytes(), nil
+	}
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, marshalled.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeJSON writes a normalized value out. Objects and lists are written in place, and only the values they hold are
+// marshalled by the json engine, so that every value is encoded once however deeply it's nested.
+func writeJSON(buffer *bytes.Buffer, value any, escapeHTML bool) error {
+	switch v := value.(type) {
+	case orderedObject:
+		buffer.WriteByte('{')
+		for i, pair := range v.pairs {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSON(buffer, pair.key, escapeHTML); err != nil {
+				return err
+			}
+			buffer.WriteByte(':')
+			if err := writeJSON(buffer, pair.value, escapeHTML); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+		return nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		object := orderedObject{pairs: make([]orderedPair, 0, len(keys))}
+		for _, key := range keys {
+			object.pairs = append(object.pairs, orderedPair{key: key, value: v[key]})
+		}
+		return writeJSON(buffer, object, escapeHTML)
+	case []any:
+		buffer.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSON(buffer, element, escapeHTML); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+		return nil
+	}
+	marshalled, err := jsonEngine.Marshal(value, escapeHTML)
+	if err != nil {
+		return err
+	}
+	buffer.Write(marshalled)
+	return nil
+}
+
+// UnmarshalJSON deserializes data into target according to provided settings.
+func UnmarshalJSON(data []byte, target any, cfg JSONConfig) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw any
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	normalized, err := json.Marshal(normalizeInput(raw, reflect.TypeOf(target), cfg))
+	if err != nil {
+		return err
+	}
+	return jsonEngine.Unmarshal(normalized, target)
+}
+
+// DecodeJSON reads the whole reader and deserializes it into target.
+func DecodeJSON(reader io.Reader, target any, cfg JSONConfig) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	// Match json.Decoder, which reports an empty body as io.EOF.
+	if len(bytes.TrimSpace(data)) == 0 {
+		return io.EOF
+	}
+	return UnmarshalJSON(data, target, cfg)
+}
+
+// jsonField describes a single serialized struct field.
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	asString  bool
+	// tagged is set for fields named by a json tag.
+	tagged bool
+}
+
+// jsonFields lists serialized fields of a struct type in declaration order, flattening embedded structs the way
+// encoding/json does: of the fields sharing a name, the least nested one wins, then the only tagged one, and if
+// that's still ambiguous none of them is serialized.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldsCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+	candidates := collectJSONFields(t, nil, map[reflect.Type]bool{})
+	byName := make(map[string][]jsonField, len(candidates))
+	for _, f := range candidates {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	fields := make([]jsonField, 0, len(candidates))
+	for _, f := range candidates {
+		if dominant, ok := dominantJSONField(byName[f.name]); ok && sameIndex(dominant.index, f.index) {
+			fields = append(fields, f)
+		}
+	}
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectJSONFields lists every serialized field of a struct type, embedded ones included, prefixing their indexes
+// with index. Embedded types that are already being collected are skipped, so that cyclic embeddings terminate.
+func collectJSONFields(t reflect.Type, index []int, collecting map[reflect.Type]bool) []jsonField {
+	if collecting[t] {
+		return nil
+	}
+	collecting[t] = true
+	defer delete(collecting, t)
+	fields := make([]jsonField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, collectJSONFields(embedded, fieldIndex, collecting)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		field := jsonField{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(","+options+",", ",omitempty,"),
+			asString:  strings.Contains(","+options+",", ",string,"),
+			tagged:    name != "",
+		}
+		if field.name == "" {
+			field.name = f.Name
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// dominantJSONField picks the field that's serialized out of fields sharing a name, returns false if there's none.
+func dominantJSONField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+	shallowest := make([]jsonField, 0, len(fields))
+	tagged := make([]jsonField, 0, len(fields))
+	for _, f := range fields {
+		if len(f.index) != depth {
+			continue
+		}
+		shallowest = append(shallowest, f)
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// sameIndex checks whether two field indexes are equal.
+func sameIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// orderedObject is a json object that keeps struct field order when marshalled.
+type orderedObject struct {
+	pairs      []orderedPair
+	escapeHTML bool
+}
+
+// orderedPair is a single key of orderedObject.
+type orderedPair struct {
+	key   string
+	value any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writeJSON(&buffer, o, o.escapeHTML); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// sparse wraps an object (or a list of objects) that should only render some of its keys.
+type sparse struct {
+	object any
+	keys   map[string]struct{}
+}
+
+// Sparse limits rendered keys of object (or of every element of a list) to provided ones, nil keys render everything.
+func Sparse(object any, keys []string) any {
+	if keys == nil {
+		return object
+	}
+	s := sparse{object: object, keys: make(map[string]struct{}, len(keys))}
+	for _, k := range keys {
+		s.keys[k] = struct{}{}
+	}
+	return s
+}
+
+// filter drops keys that are not whitelisted from a normalized object or list of objects.
+func (s sparse) filter(value any) any {
+	switch v := value.(type) {
+	case orderedObject:
+		pairs := make([]orderedPair, 0, len(s.keys))
+		for _, pair := range v.pairs {
+			if _, ok := s.keys[pair.key]; ok {
+				pairs = append(pairs, pair)
+			}
+		}
+		v.pairs = pairs
+		return v
+	case map[string]any:
+		for k := range v {
+			if _, ok := s.keys[k]; !ok {
+				delete(v, k)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = s.filter(v[i])
+		}
+		return v
+	}
+	return value
+}
+
+// normalizeOutput converts a value into a tree of plain values with times and big integers formatted per cfg.
+func normalizeOutput(v reflect.Value, cfg JSONConfig) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	if s, ok := v.Interface().(sparse); ok {
+		return s.filter(normalizeOutput(reflect.ValueOf(s.object), cfg))
+	}
+	if v.Type() == timeType {
+		location := cfg.TimeLocation
+		if location == nil {
+			location = time.UTC
+		}
+		return v.Interface().(time.Time).In(location).Format(cfg.TimeLayout)
+	}
+	if marshaler, ok := jsonMarshaler(v); ok {
+		return marshaler
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return normalizeOutput(v.Elem(), cfg)
+	case reflect.Struct:
+		object := orderedObject{escapeHTML: cfg.EscapeHTML}
+		for _, f := range jsonFields(v.Type()) {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || (f.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			value := normalizeOutput(fv, cfg)
+			if f.asString {
+				value = stringify(value)
+			}
+			object.pairs = append(object.pairs, orderedPair{key: f.name, value: value})
+		}
+		return object
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		object := make(map[string]any, v.Len())
+		iterator := v.MapRange()
+		for iterator.Next() {
+			object[mapKey(iterator.Key())] = normalizeOutput(iterator.Value(), cfg)
+		}
+		return object
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		array := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			array[i] = normalizeOutput(v.Index(i), cfg)
+		}
+		return array
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if cfg.BigIntAsString && (v.Int() > maxSafeInteger || v.Int() < -maxSafeInteger) {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if cfg.BigIntAsString && v.Uint() > maxSafeInteger {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+			return nil
+		}
+	}
+	return v.Interface()
+}
+
+// jsonMarshaler returns a value that marshals itself, as json or as text, the way encoding/json would call it:
+// methods with pointer receivers are only called on addressable values.
+func jsonMarshaler(v reflect.Value) (any, bool) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, false
+	}
+	for _, t := range []reflect.Type{marshalerType, textMarshalerType} {
+		if v.Type().Implements(t) {
+			return v.Interface(), true
+		}
+		if v.Kind() != reflect.Pointer && v.CanAddr() && reflect.PointerTo(v.Type()).Implements(t) {
+			return v.Addr().Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// normalizeInput converts a decoded json tree so that it can be unmarshalled into t by the json engine.
+func normalizeInput(raw any, t reflect.Type, cfg JSONConfig) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		if s, ok := raw.(string); ok {
+			if parsed, err := time.Parse(cfg.TimeLayout, s); err == nil {
+				return parsed.UTC().Format(time.RFC3339Nano)
+			}
+		}
+		return raw
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return raw
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return raw
+		}
+		for _, f := range jsonFields(t) {
+			for key, value := range object {
+				if strings.EqualFold(key, f.name) {
+					object[key] = normalizeInput(value, fieldType(t, f.index), cfg)
+				}
+			}
+		}
+		return object
+	case reflect.Map:
+		if object, ok := raw.(map[string]any); ok {
+			for key, value := range object {
+				object[key] = normalizeInput(value, t.Elem(), cfg)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if array, ok := raw.([]any); ok {
+			for i, value := range array {
+				array[i] = normalizeInput(value, t.Elem(), cfg)
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if s, ok := raw.(string); ok && cfg.BigIntAsString {
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return json.Number(s)
+			}
+			if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return json.Number(s)
+			}
+		}
+	}
+	return raw
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that doesn't panic on nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldType resolves the type of a (possibly embedded) struct field.
+func fieldType(t reflect.Type, index []int) reflect.Type {
+	for _, x := range index {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		t = t.Field(x).Type
+	}
+	return t
+}
+
+// mapKey formats a map key the same way encoding/json does.
+func mapKey(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	key, _ := json.Marshal(v.Interface())
+	return strings.Trim(string(key), "\"")
+}
+
+// stringify implements the ",string" json tag option on normalized values.
+func stringify(value any) any {
+	switch value.(type) {
+	case string, nil, orderedObject, map[string]any, []any:
+		return value
+	}
+	marshalled, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	return string(marshalled)
+}
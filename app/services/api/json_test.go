@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	type sample struct {
+		ID      uint64    `json:"id"`
+		Title   string    `json:"title"`
+		Hidden  string    `json:"-"`
+		Empty   string    `json:"empty,omitempty"`
+		Created time.Time `json:"created_at"`
+	}
+	kyiv := time.FixedZone("UTC+2", 2*60*60)
+	object := sample{
+		ID:      1 << 60,
+		Title:   "<b>&</b>",
+		Hidden:  "hidden",
+		Created: time.Date(2026, 1, 1, 12, 0, 0, 0, kyiv),
+	}
+
+	cases := []struct {
+		cfg       JSONConfig
+		assertion func([]byte, error)
+	}{
+		// Defaults.
+		{
+			cfg: JSONConfig{TimeLayout: time.RFC3339Nano, TimeLocation: time.UTC},
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, `{"id":1152921504606846976,"title":"<b>&</b>","created_at":"2026-01-01T10:00:00Z"}`, string(value))
+				}
+			},
+		},
+		// Big integers as strings, html escaping, custom layout.
+		{
+			cfg: JSONConfig{TimeLayout: "2006-01-02", TimeLocation: time.UTC, BigIntAsString: true, EscapeHTML: true},
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, `{"id":"1152921504606846976","title":"\u003cb\u003e\u0026\u003c/b\u003e","created_at":"2026-01-01"}`, string(value))
+				}
+			},
+		},
+		// Pretty.
+		{
+			cfg: JSONConfig{TimeLayout: time.RFC3339, TimeLocation: time.UTC, Pretty: true},
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "{\n  \"id\": 1152921504606846976,\n  \"title\": \"<b>&</b>\",\n  \"created_at\": \"2026-01-01T10:00:00Z\"\n}", string(value))
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(MarshalJSON(object, c.cfg))
+	}
+}
+
+// jsonLevel marshals itself as text.
+type jsonLevel int
+
+// MarshalText implements encoding.TextMarshaler.
+func (l jsonLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+// jsonPointerMarshaler marshals itself only through a pointer.
+type jsonPointerMarshaler struct {
+	Value string
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *jsonPointerMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal("pointer:" + m.Value)
+}
+
+func TestMarshalJSON_EncodingRules(t *testing.T) {
+	type base struct {
+		ID      uint64    `json:"id"`
+		Name    string    `json:"name"`
+		Note    string    `json:"note"`
+		Created time.Time `json:"created_at"`
+	}
+	type other struct {
+		Note  string `json:"note"`
+		Label string
+	}
+	type tagged struct {
+		Label string `json:"Label"`
+	}
+	type nested struct {
+		Child *nested   `json:"child,omitempty"`
+		At    time.Time `json:"at"`
+	}
+	type sample struct {
+		base
+		*other
+		tagged
+		// Name dominates the embedded one.
+		Name    string               `json:"name"`
+		Level   jsonLevel            `json:"level"`
+		Levels  map[string]jsonLevel `json:"levels"`
+		Pointer jsonPointerMarshaler `json:"pointer"`
+		Nested  nested               `json:"nested"`
+	}
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	object := sample{
+		base:    base{ID: 1, Name: "embedded", Note: "base", Created: at},
+		other:   &other{Note: "other", Label: "untagged"},
+		tagged:  tagged{Label: "tagged"},
+		Name:    "outer",
+		Level:   2,
+		Levels:  map[string]jsonLevel{"b": 1, "a": 3},
+		Pointer: jsonPointerMarshaler{Value: "x"},
+		Nested:  nested{At: at, Child: &nested{At: at, Child: &nested{At: at}}},
+	}
+	cfg := JSONConfig{TimeLayout: "2006-01-02", TimeLocation: time.UTC}
+
+	// Addressable values call pointer receivers, like encoding/json does, and the rest of the rules match it too.
+	value, err := MarshalJSON(&object, cfg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"id":1,"created_at":"2026-01-01","Label":"tagged","name":"outer","level":"**",`+
+			`"levels":{"a":"***","b":"*"},"pointer":"pointer:x",`+
+			`"nested":{"child":{"child":{"at":"2026-01-01"},"at":"2026-01-01"},"at":"2026-01-01"}}`, string(value))
+		expected, err := json.Marshal(&object)
+		if assert.NoError(t, err) {
+			assert.Equal(t, keys(t, expected), keys(t, value))
+		}
+	}
+
+	// Values that aren't addressable don't.
+	value, err = MarshalJSON(object, cfg)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(value), `"pointer":{"Value":"x"}`)
+	}
+}
+
+// keys lists the keys of a json object.
+func keys(t *testing.T, data []byte) []string {
+	var object map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &object))
+	result := make([]string, 0, len(object))
+	for key := range object {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	type sample struct {
+		ID      uint64    `json:"id"`
+		Created time.Time `json:"created_at"`
+	}
+
+	cases := []struct {
+		cfg       JSONConfig
+		data      string
+		assertion func(sample, error)
+	}{
+		// Custom layout, normalized to UTC.
+		{
+			cfg:  JSONConfig{TimeLayout: "2006-01-02 15:04 -0700", TimeLocation: time.UTC},
+			data: `{"id":1,"created_at":"2026-01-01 12:00 +0200"}`,
+			assertion: func(value sample, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, uint64(1), value.ID)
+					assert.Equal(t, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), value.Created)
+				}
+			},
+		},
+		// Big integer as string.
+		{
+			cfg:  JSONConfig{TimeLayout: time.RFC3339Nano, TimeLocation: time.UTC, BigIntAsString: true},
+			data: `{"id":"1152921504606846977"}`,
+			assertion: func(value sample, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, uint64(1<<60+1), value.ID)
+				}
+			},
+		},
+		// Big integer as string, option disabled.
+		{
+			cfg:  JSONConfig{TimeLayout: time.RFC3339Nano, TimeLocation: time.UTC},
+			data: `{"id":"1152921504606846977"}`,
+			assertion: func(value sample, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var value sample
+		err := UnmarshalJSON([]byte(c.data), &value, c.cfg)
+		c.assertion(value, err)
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JSONAPIContentType is the media type of JSON:API documents.
+	JSONAPIContentType = "application/vnd.api+json"
+)
+
+var (
+	// ErrJSONAPIMissingData is thrown when a JSON:API request document has no primary data.
+	ErrJSONAPIMissingData = errors.New("json:api document is missing primary data")
+	// ErrJSONAPITypeMismatch is thrown when a JSON:API request document describes a resource of unexpected type.
+	ErrJSONAPITypeMismatch = errors.New("json:api resource type doesn't match the endpoint")
+	// resourceType is the reflected type of JSONAPIResource.
+	resourceType = reflect.TypeOf((*JSONAPIResource)(nil)).Elem()
+)
+
+// JSONAPIResource is implemented (with value receivers) by entities that can be rendered as JSON:API resource objects.
+// Fields of such entities that hold other resources (or slices of them) are rendered as relationships.
+type JSONAPIResource interface {
+	JSONAPIType() string
+	JSONAPIID() string
+}
+
+// WantsJSONAPI checks whether the client negotiated JSON:API responses.
+func (s *ControllerSuite) WantsJSONAPI() bool {
+	if s.request == nil {
+		return false
+	}
+	for _, accepted := range strings.Split(s.request.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == JSONAPIContentType {
+			return true
+		}
+	}
+	return s.request.Header.Get("Accept") == "" && s.IsJSONAPIRequest()
+}
+
+// IsJSONAPIRequest checks whether the request body is a JSON:API document.
+func (s *ControllerSuite) IsJSONAPIRequest() bool {
+	if s.request == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(s.request.Header.Get("Content-Type"))
+	return err == nil && mediaType == JSONAPIContentType
+}
+
+// marshalJSONAPI wraps an object into a JSON:API document and marshals it.
+func (s *ControllerSuite) marshalJSONAPI(status int, object interface{}) []byte {
+	return s.marshalJSON(jsonAPIDocument(object, status, s.request.URL.RequestURI(), s.JSONSettings()))
+}
+
+// jsonAPIDocument builds a top-level JSON:API document.
+// Resources (and lists of them) become primary data, error messages become errors, anything else becomes meta.
+func jsonAPIDocument(object any, status int, self string, cfg JSONConfig) any {
+	links := map[string]string{"self": self}
+	meta := make(map[string]any)
+	if p, ok := object.(paged); ok {
+		object = p.object
+		for k, v := range p.links {
+			links[k] = v
+		}
+		for k, v := range p.meta {
+			meta[k] = v
+		}
+	}
+	var keys map[string]struct{}
+	if s, ok := object.(sparse); ok {
+		object, keys = s.object, s.keys
+	}
+	document := orderedObject{escapeHTML: cfg.EscapeHTML}
+	if status >= http.StatusBadRequest {
+		detail := normalizeOutput(reflect.ValueOf(object), cfg)
+		if message, ok := object.(map[string]string); ok {
+			detail = message["message"]
+		}
+		document.pairs = append(document.pairs, orderedPair{key: "errors", value: []any{map[string]any{
+			"status": strconv.Itoa(status),
+			"title":  http.StatusText(status),
+			"detail": detail,
+		}}})
+		return document
+	}
+
+	v := reflect.ValueOf(object)
+	for v.IsValid() && v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	included := &includedResources{known: make(map[string]struct{})}
+	switch {
+	case v.IsValid() && isResource(v.Type()):
+		document.pairs = append(document.pairs, orderedPair{key: "data", value: resourceObject(v, keys, cfg, included)})
+	case v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && isResource(v.Type().Elem()):
+		data := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			data[i] = resourceObject(v.Index(i), keys, cfg, included)
+		}
+		document.pairs = append(document.pairs, orderedPair{key: "data", value: data})
+		meta["count"] = v.Len()
+		document.pairs = append(document.pairs, orderedPair{key: "meta", value: meta})
+	default:
+		document.pairs = append(document.pairs, orderedPair{key: "meta", value: normalizeOutput(v, cfg)})
+	}
+	if len(included.resources) > 0 {
+		document.pairs = append(document.pairs, orderedPair{key: "included", value: included.resources})
+	}
+	document.pairs = append(document.pairs, orderedPair{key: "links", value: links})
+	return document
+}
+
+// includedResources is a deduplicated list of resources referenced by relationships.
+type includedResources struct {
+	resources []any
+	known     map[string]struct{}
+}
+
+// add appends a resource unless it has already been included.
+func (i *includedResources) add(v reflect.Value, cfg JSONConfig) {
+	resource := v.Interface().(JSONAPIResource)
+	key := resource.JSONAPIType() + ":" + resource.JSONAPIID()
+	if _, ok := i.known[key]; ok {
+		return
+	}
+	i.known[key] = struct{}{}
+	i.resources = append(i.resources, resourceObject(v, nil, cfg, i))
+}
+
+// resourceObject renders a single resource, collecting related resources into included.
+func resourceObject(v reflect.Value, keys map[string]struct{}, cfg JSONConfig, included *includedResources) any {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	resource := v.Interface().(JSONAPIResource)
+	attributes := orderedObject{escapeHTML: cfg.EscapeHTML}
+	relationships := orderedObject{escapeHTML: cfg.EscapeHTML}
+	for _, f := range jsonFields(v.Type()) {
+		if _, ok := keys[f.name]; f.name == "id" || (keys != nil && !ok) {
+			continue
+		}
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		t := fv.Type()
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch {
+		case isResource(t):
+			if fv.Kind() == reflect.Pointer && fv.IsNil() {
+				continue
+			}
+			included.add(fv, cfg)
+			relationships.pairs = append(relationships.pairs, orderedPair{key: f.name, value: map[string]any{"data": linkage(fv)}})
+		case t.Kind() == reflect.Slice && isResource(t.Elem()):
+			if fv.IsNil() {
+				continue
+			}
+			data := make([]any, fv.Len())
+			for i := 0; i < fv.Len(); i++ {
+				included.add(fv.Index(i), cfg)
+				data[i] = linkage(fv.Index(i))
+			}
+			relationships.pairs = append(relationships.pairs, orderedPair{key: f.name, value: map[string]any{"data": data}})
+		default:
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+			attributes.pairs = append(attributes.pairs, orderedPair{key: f.name, value: normalizeOutput(fv, cfg)})
+		}
+	}
+
+	object := orderedObject{escapeHTML: cfg.EscapeHTML}
+	object.pairs = append(object.pairs,
+		orderedPair{key: "type", value: resource.JSONAPIType()},
+		orderedPair{key: "id", value: resource.JSONAPIID()},
+		orderedPair{key: "attributes", value: attributes},
+	)
+	if len(relationships.pairs) > 0 {
+		object.pairs = append(object.pairs, orderedPair{key: "relationships", value: relationships})
+	}
+	object.pairs = append(object.pairs, orderedPair{key: "links", value: map[string]string{
+		"self": "/" + resource.JSONAPIType() + "/" + resource.JSONAPIID(),
+	}})
+	return object
+}
+
+// linkage renders a resource identifier object.
+func linkage(v reflect.Value) map[string]string {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	resource := v.Interface().(JSONAPIResource)
+	return map[string]string{"type": resource.JSONAPIType(), "id": resource.JSONAPIID()}
+}
+
+// isResource checks whether t implements JSONAPIResource.
+func isResource(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Implements(resourceType)
+}
+
+// jsonAPIRequest is a JSON:API request document describing a single resource.
+type jsonAPIRequest struct {
+	Data *struct {
+		Type          string                     `json:"type"`
+		ID            string                     `json:"id"`
+		Attributes    map[string]json.RawMessage `json:"attributes"`
+		Relationships map[string]struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// DecodeJSONAPI reads a JSON:API request document and flattens it into target.
+// Resource id becomes "id", and to-one relationship "x" becomes "x_id".
+func DecodeJSONAPI(reader io.Reader, target any, cfg JSONConfig) error {
+	var document jsonAPIRequest
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return err
+	}
+	if document.Data == nil {
+		return ErrJSONAPIMissingData
+	}
+	if t := reflect.TypeOf(target); t != nil && isResource(t) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if expected := reflect.Zero(t).Interface().(JSONAPIResource).JSONAPIType(); document.Data.Type != expected {
+			return ErrJSONAPITypeMismatch
+		}
+	}
+
+	flat := make(map[string]json.RawMessage, len(document.Data.Attributes)+len(document.Data.Relationships)+1)
+	for k, v := range document.Data.Attributes {
+		flat[k] = v
+	}
+	if document.Data.ID != "" {
+		flat["id"] = identifier(document.Data.ID)
+	}
+	for name, relationship := range document.Data.Relationships {
+		var related *struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(relationship.Data, &related); err != nil {
+			// To-many relationships are not flattened.
+			continue
+		}
+		if related == nil {
+			flat[name+"_id"] = json.RawMessage("null")
+			continue
+		}
+		flat[name+"_id"] = identifier(related.ID)
+	}
+	marshalled, err := json.Marshal(flat)
+	if err != nil {
+		return err
+	}
+	return UnmarshalJSON(marshalled, target, cfg)
+}
+
+// identifier renders a JSON:API string id as a json number when possible.
+func identifier(id string) json.RawMessage {
+	if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return json.RawMessage(id)
+	}
+	marshalled, _ := json.Marshal(id)
+	return marshalled
+}
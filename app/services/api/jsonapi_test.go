@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAuthor is a minimal JSON:API resource.
+type testAuthor struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+// JSONAPIType ...
+func (testAuthor) JSONAPIType() string {
+	return "authors"
+}
+
+// JSONAPIID ...
+func (a testAuthor) JSONAPIID() string {
+	return strconv.Itoa(int(a.ID))
+}
+
+// testArticle is a minimal JSON:API resource with a relationship.
+type testArticle struct {
+	ID       uint32      `json:"id"`
+	AuthorID uint32      `json:"author_id"`
+	Title    string      `json:"title"`
+	Author   *testAuthor `json:"author,omitempty"`
+}
+
+// JSONAPIType ...
+func (testArticle) JSONAPIType() string {
+	return "articles"
+}
+
+// JSONAPIID ...
+func (a testArticle) JSONAPIID() string {
+	return strconv.Itoa(int(a.ID))
+}
+
+func TestJSONAPIDocument(t *testing.T) {
+	cfg := JSONConfig{}
+	author := &testAuthor{ID: 7, Name: "author"}
+	cases := []struct {
+		object    any
+		status    int
+		assertion func([]byte, error)
+	}{
+		// Single resource with relationship.
+		{
+			object: testArticle{ID: 1, AuthorID: 7, Title: "title", Author: author},
+			status: http.StatusOK,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{
+						"data": {
+							"type": "articles", "id": "1",
+							"attributes": {"author_id": 7, "title": "title"},
+							"relationships": {"author": {"data": {"type": "authors", "id": "7"}}},
+							"links": {"self": "/articles/1"}
+						},
+						"included": [{"type": "authors", "id": "7", "attributes": {"name": "author"}, "links": {"self": "/authors/7"}}],
+						"links": {"self": "/articles/1"}
+					}`, string(value))
+				}
+			},
+		},
+		// Sparse list, deduplicated includes.
+		{
+			object: Sparse([]testArticle{{ID: 1, Title: "a", Author: author}, {ID: 2, Title: "b", Author: author}}, []string{"title", "author"}),
+			status: http.StatusOK,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, 1, strings.Count(string(value), `"type":"authors","id":"7","attributes"`))
+					assert.NotContains(t, string(value), "author_id")
+					assert.Contains(t, string(value), `"meta":{"count":2}`)
+				}
+			},
+		},
+		// Error message.
+		{
+			object: map[string]string{"message": "oops"},
+			status: http.StatusBadRequest,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{"errors": [{"status": "400", "title": "Bad Request", "detail": "oops"}]}`, string(value))
+				}
+			},
+		},
+		// Plain object.
+		{
+			object: map[string]string{"message": "deleted"},
+			status: http.StatusOK,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{"meta": {"message": "deleted"}, "links": {"self": "/articles/1"}}`, string(value))
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(MarshalJSON(jsonAPIDocument(c.object, c.status, "/articles/1", cfg), cfg))
+	}
+}
+
+func TestDecodeJSONAPI(t *testing.T) {
+	cases := []struct {
+		body      string
+		assertion func(testArticle, error)
+	}{
+		// Valid.
+		{
+			body: `{"data": {"type": "articles", "id": "3", "attributes": {"title": "title"}, "relationships": {"author": {"data": {"type": "authors", "id": "7"}}}}}`,
+			assertion: func(article testArticle, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, testArticle{ID: 3, AuthorID: 7, Title: "title"}, article)
+				}
+			},
+		},
+		// Type mismatch.
+		{
+			body: `{"data": {"type": "authors", "attributes": {"name": "name"}}}`,
+			assertion: func(article testArticle, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrJSONAPITypeMismatch)
+				}
+			},
+		},
+		// Missing data.
+		{
+			body: `{"meta": {}}`,
+			assertion: func(article testArticle, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrJSONAPIMissingData)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var article testArticle
+		err := DecodeJSONAPI(strings.NewReader(c.body), &article, JSONConfig{})
+		c.assertion(article, err)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// paged wraps a list together with links to neighbouring pages and list metadata.
+type paged struct {
+	object any
+	links  map[string]string
+	meta   map[string]any
+}
+
+// ServePage serves a 200 response with a single page of a list.
+// Links to neighbouring pages are built from provided cursors, and are rendered as a Link header (and total as
+// X-Total-Count header) for plain json, or as top-level links and meta for JSON:API.
+func (s *ControllerSuite) ServePage(object any, next, prev string, total *int64) {
+	links := make(map[string]string)
+	if next != "" {
+		links["next"] = s.pageURL("after", next)
+	}
+	if prev != "" {
+		links["prev"] = s.pageURL("before", prev)
+	}
+	meta := make(map[string]any)
+	if total != nil {
+		meta["total"] = *total
+	}
+
+	if s.WantsJSONAPI() {
+		s.ServeJSON(http.StatusOK, paged{object: object, links: links, meta: meta})
+		return
+	}
+	header := make([]string, 0, len(links))
+	for _, rel := range []string{"next", "prev"} {
+		if link, ok := links[rel]; ok {
+			header = append(header, fmt.Sprintf("<%v>; rel=\"%v\"", link, rel))
+		}
+	}
+	if len(header) > 0 {
+		s.writer.Header().Set("Link", strings.Join(header, ", "))
+	}
+	if total != nil {
+		s.writer.Header().Set("X-Total-Count", strconv.FormatInt(*total, 10))
+	}
+	s.ServeOK(object)
+}
+
+// pageURL returns current request url with the cursor param replaced.
+func (s *ControllerSuite) pageURL(param string, cursor string) string {
+	u := *s.request.URL
+	values := u.Query()
+	values.Del("after")
+	values.Del("before")
+	values.Set(param, cursor)
+	u.RawQuery = values.Encode()
+	return u.RequestURI()
+}
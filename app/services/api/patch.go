@@ -0,0 +1,447 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MergePatchContentType is the media type of RFC 7386 JSON Merge Patch documents.
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is the media type of RFC 6902 JSON Patch documents.
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedPatchType is thrown when a patch is sent with a media type that is not supported.
+	ErrUnsupportedPatchType = fmt.Errorf("patch has to be sent as %v or %v", MergePatchContentType, JSONPatchContentType)
+	// ErrInvalidPatch is thrown when a patch document is malformed.
+	ErrInvalidPatch = errors.New("invalid patch document")
+	// ErrPatchPathNotFound is thrown when a patch operation references a missing location.
+	ErrPatchPathNotFound = errors.New("patch path doesn't exist")
+	// ErrPatchTestFailed is thrown when a JSON Patch test operation doesn't match.
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// Patch is a decoded JSON Merge Patch or JSON Patch, read out of a request body by ControllerSuite.ParsePatch.
+type Patch struct {
+	// changes is a decoded JSON Merge Patch, unless operations are set.
+	changes    any
+	operations []patchOperation
+	merge      bool
+	cfg        JSONConfig
+}
+
+// ParsePatch reads and decodes request body, a JSON Merge Patch or a JSON Patch depending on Content-Type, so that it
+// can be applied later on without touching the request.
+func (s *ControllerSuite) ParsePatch() (Patch, error) {
+	mediaType, _, _ := mime.ParseMediaType(s.request.Header.Get("Content-Type"))
+	if mediaType != MergePatchContentType && mediaType != JSONPatchContentType {
+		return Patch{}, ErrUnsupportedPatchType
+	}
+	body, err := io.ReadAll(s.request.Body)
+	if err != nil {
+		return Patch{}, err
+	}
+	patch := Patch{merge: mediaType == MergePatchContentType, cfg: s.JSONSettings()}
+	patch.cfg.Pretty = false
+	if patch.merge {
+		patch.changes, err = decodeMergePatch(body)
+	} else {
+		patch.operations, err = decodeJSONPatch(body)
+	}
+	if err != nil {
+		return Patch{}, err
+	}
+	return patch, nil
+}
+
+// Apply applies the patch to target.
+func (p Patch) Apply(target any) error {
+	document, err := MarshalJSON(target, p.cfg)
+	if err != nil {
+		return err
+	}
+	var root any
+	if err = decodeTree(document, &root); err != nil {
+		return err
+	}
+	if p.merge {
+		root = mergePatch(root, deepCopy(p.changes))
+	} else if root, err = applyOperations(root, p.operations); err != nil {
+		return err
+	}
+	if document, err = json.Marshal(root); err != nil {
+		return err
+	}
+	// Reset the target, so that removed members are zeroed rather than kept.
+	v := reflect.ValueOf(target).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return UnmarshalJSON(document, target, p.cfg)
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to a json document.
+func ApplyMergePatch(document, patch []byte) ([]byte, error) {
+	var target any
+	if err := decodeTree(document, &target); err != nil {
+		return nil, err
+	}
+	changes, err := decodeMergePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, changes))
+}
+
+// decodeMergePatch decodes an RFC 7386 JSON Merge Patch.
+func decodeMergePatch(patch []byte) (any, error) {
+	var changes any
+	if err := decodeTree(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return changes, nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7386.
+func mergePatch(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = make(map[string]any)
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = mergePatch(object[key], value)
+	}
+	return object
+}
+
+// patchOperation is a single RFC 6902 JSON Patch operation.
+type patchOperation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch to a json document.
+func ApplyJSONPatch(document, patch []byte) ([]byte, error) {
+	var root any
+	if err := decodeTree(document, &root); err != nil {
+		return nil, err
+	}
+	operations, err := decodeJSONPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	if root, err = applyOperations(root, operations); err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+// decodeJSONPatch decodes the operations of an RFC 6902 JSON Patch.
+func decodeJSONPatch(patch []byte) ([]patchOperation, error) {
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return operations, nil
+}
+
+// applyOperations applies JSON Patch operations to a decoded json tree in order, returning the new root.
+func applyOperations(root any, operations []patchOperation) (any, error) {
+	for i, operation := range operations {
+		var err error
+		root, err = operation.apply(root)
+		if err != nil {
+			return nil, fmt.Errorf("operation %v: %w", i, err)
+		}
+	}
+	return root, nil
+}
+
+// apply applies a single operation to a decoded json tree, returning the new root.
+func (o patchOperation) apply(root any) (any, error) {
+	if o.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*o.Path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := o.value()
+	switch o.Op {
+	case "add":
+		if err != nil {
+			return nil, err
+		}
+		return addAt(root, path, value)
+	case "remove":
+		root, _, err = removeAt(root, path)
+		return root, err
+	case "replace":
+		if err != nil {
+			return nil, err
+		}
+		if root, _, err = removeAt(root, path); err != nil {
+			return nil, err
+		}
+		return addAt(root, path, value)
+	case "move", "copy":
+		if o.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		from, err := parsePointer(*o.From)
+		if err != nil {
+			return nil, err
+		}
+		if o.Op == "move" {
+			if strings.HasPrefix(*o.Path+"/", *o.From+"/") && *o.Path != *o.From {
+				return nil, fmt.Errorf("%w: can't move a value into itself", ErrInvalidPatch)
+			}
+			root, value, err = removeAt(root, from)
+		} else {
+			value, err = getAt(root, from)
+			value = deepCopy(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return addAt(root, path, value)
+	case "test":
+		if err != nil {
+			return nil, err
+		}
+		current, err := getAt(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalTrees(current, value) {
+			return nil, ErrPatchTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, o.Op)
+}
+
+// value decodes operation value.
+func (o patchOperation) value() (any, error) {
+	if o.Value == nil {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+	}
+	var value any
+	if err := decodeTree(*o.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return value, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: malformed path %q", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// getAt returns a value located at path.
+func getAt(root any, path []string) (any, error) {
+	current := root
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, ErrPatchPathNotFound
+			}
+			current = value
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, ErrPatchPathNotFound
+		}
+	}
+	return current, nil
+}
+
+// addAt adds a value at path, returning the new root.
+func addAt(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := getAt(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[token] = value
+		return root, nil
+	case []any:
+		index := len(node)
+		if token != "-" {
+			if index, err = arrayIndex(token, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return replaceAt(root, path[:len(path)-1], node)
+	}
+	return nil, ErrPatchPathNotFound
+}
+
+// removeAt removes a value at path, returning the new root and the removed value.
+func removeAt(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+	parent, err := getAt(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, ErrPatchPathNotFound
+		}
+		delete(node, token)
+		return root, value, nil
+	case []any:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+		root, err = replaceAt(root, path[:len(path)-1], node)
+		return root, value, err
+	}
+	return nil, nil, ErrPatchPathNotFound
+}
+
+// replaceAt swaps a value at an existing path, used when a slice header changes.
+func replaceAt(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := getAt(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[token] = value
+	case []any:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+	return root, nil
+}
+
+// arrayIndex parses an array reference token, checking it against the maximum allowed index.
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPatchPathNotFound
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max {
+		return 0, ErrPatchPathNotFound
+	}
+	return index, nil
+}
+
+// decodeTree decodes json into a tree of maps, slices and json.Numbers.
+func decodeTree(data []byte, target *any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// deepCopy copies a decoded json tree.
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(node))
+		for k, v := range node {
+			object[k] = deepCopy(v)
+		}
+		return object
+	case []any:
+		array := make([]any, len(node))
+		for i, v := range node {
+			array[i] = deepCopy(v)
+		}
+		return array
+	}
+	return value
+}
+
+// equalTrees compares decoded json trees, treating numbers by value.
+func equalTrees(a, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if other, ok := bv[k]; !ok || !equalTrees(v, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalTrees(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	cases := []struct {
+		document  string
+		patch     string
+		assertion func([]byte, error)
+	}{
+		// RFC 7386 example.
+		{
+			document: `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "This will be unchanged"}`,
+			patch:    `{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{"title": "Hello!", "author": {"givenName": "John"}, "tags": ["example"], "content": "This will be unchanged", "phoneNumber": "+01-123-456-7890"}`, string(value))
+				}
+			},
+		},
+		// Malformed patch.
+		{
+			document: `{}`,
+			patch:    `{`,
+			assertion: func(value []byte, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPatch)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(ApplyMergePatch([]byte(c.document), []byte(c.patch)))
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	cases := []struct {
+		document  string
+		patch     string
+		assertion func([]byte, error)
+	}{
+		// Add, remove, replace.
+		{
+			document: `{"title": "title", "content": "content", "tags": ["a", "c"]}`,
+			patch:    `[{"op": "add", "path": "/tags/1", "value": "b"}, {"op": "remove", "path": "/content"}, {"op": "replace", "path": "/title", "value": "new"}]`,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{"title": "new", "tags": ["a", "b", "c"]}`, string(value))
+				}
+			},
+		},
+		// Move, copy, append, test.
+		{
+			document: `{"a": {"b": 1}, "list": [1]}`,
+			patch:    `[{"op": "test", "path": "/a/b", "value": 1.0}, {"op": "copy", "from": "/a", "path": "/c"}, {"op": "move", "from": "/a/b", "path": "/list/-"}]`,
+			assertion: func(value []byte, err error) {
+				if assert.NoError(t, err) {
+					assert.JSONEq(t, `{"a": {}, "c": {"b": 1}, "list": [1, 1]}`, string(value))
+				}
+			},
+		},
+		// Failed test.
+		{
+			document: `{"title": "title"}`,
+			patch:    `[{"op": "test", "path": "/title", "value": "other"}, {"op": "remove", "path": "/title"}]`,
+			assertion: func(value []byte, err error) {
+				assert.ErrorIs(t, err, ErrPatchTestFailed)
+			},
+		},
+		// Missing path.
+		{
+			document: `{"title": "title"}`,
+			patch:    `[{"op": "remove", "path": "/content"}]`,
+			assertion: func(value []byte, err error) {
+				assert.ErrorIs(t, err, ErrPatchPathNotFound)
+			},
+		},
+		// Unknown operation.
+		{
+			document: `{}`,
+			patch:    `[{"op": "merge", "path": "/title"}]`,
+			assertion: func(value []byte, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPatch)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(ApplyJSONPatch([]byte(c.document), []byte(c.patch)))
+	}
+}
+
+func TestControllerSuite_ParsePatch(t *testing.T) {
+	type document struct {
+		Title string `json:"title"`
+		Note  string `json:"note,omitempty"`
+	}
+	cases := []struct {
+		contentType string
+		body        string
+		assertion   func(Patch, error)
+	}{
+		// Merge patch, applied repeatedly once parsed.
+		{
+			contentType: MergePatchContentType,
+			body:        `{"title": "new", "note": null}`,
+			assertion: func(patch Patch, err error) {
+				if !assert.NoError(t, err) {
+					return
+				}
+				for i := 0; i < 2; i++ {
+					target := document{Title: "old", Note: "note"}
+					if assert.NoError(t, patch.Apply(&target)) {
+						assert.Equal(t, document{Title: "new"}, target)
+					}
+				}
+			},
+		},
+		// JSON Patch.
+		{
+			contentType: JSONPatchContentType,
+			body:        `[{"op": "replace", "path": "/title", "value": "new"}]`,
+			assertion: func(patch Patch, err error) {
+				target := document{Title: "old"}
+				if assert.NoError(t, err) && assert.NoError(t, patch.Apply(&target)) {
+					assert.Equal(t, "new", target.Title)
+				}
+			},
+		},
+		// Malformed patches are refused up front.
+		{
+			contentType: JSONPatchContentType,
+			body:        `{"op": "replace"}`,
+			assertion: func(patch Patch, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPatch)
+			},
+		},
+		// Unsupported media type.
+		{
+			contentType: "application/json",
+			body:        `{}`,
+			assertion: func(patch Patch, err error) {
+				assert.ErrorIs(t, err, ErrUnsupportedPatchType)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodPatch, "/posts/1", strings.NewReader(c.body))
+		request.Header.Set("Content-Type", c.contentType)
+		var s ControllerSuite
+		s.NewRequest(httptest.NewRecorder(), request)
+		c.assertion(s.ParsePatch())
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var (
+	// ErrInvalidRouteParams is thrown when params don't fill in variables of a route pattern.
+	ErrInvalidRouteParams = errors.New("params don't match route variables")
+	// routeVariable matches a variable of a route pattern, optionally restricted by a regular expression.
+	routeVariable = regexp.MustCompile(`\{([^{}:]+)(?::([^{}]+))?\}`)
+)
+
+// ReverseURL builds a path out of a route pattern, filling in its variables with params given as name, value pairs.
+// Values are path-escaped, and have to match regular expressions variables are restricted by.
+func ReverseURL(pattern string, params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: odd number of params", ErrInvalidRouteParams)
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+	var err error
+	path := routeVariable.ReplaceAllStringFunc(pattern, func(variable string) string {
+		groups := routeVariable.FindStringSubmatch(variable)
+		value, ok := values[groups[1]]
+		switch {
+		case !ok:
+			err = fmt.Errorf("%w: %v is missing", ErrInvalidRouteParams, groups[1])
+		case groups[2] != "" && !regexp.MustCompile("^(?:"+groups[2]+")$").MatchString(value):
+			err = fmt.Errorf("%w: %v doesn't match %v", ErrInvalidRouteParams, groups[1], groups[2])
+		}
+		delete(values, groups[1])
+		return url.PathEscape(value)
+	})
+	if err == nil && len(values) > 0 {
+		err = fmt.Errorf("%w: %v unknown params", ErrInvalidRouteParams, len(values))
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
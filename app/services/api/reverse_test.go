@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseURL(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		params    []string
+		assertion func(string, error)
+	}{
+		// Static.
+		{
+			pattern: "/sitemap.xml",
+			assertion: func(path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "/sitemap.xml", path)
+			},
+		},
+		// Restricted and escaped variables.
+		{
+			pattern: "/posts/{id:[0-9]+}/tags/{tag}",
+			params:  []string{"tag", "go news/weekly", "id", "12"},
+			assertion: func(path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "/posts/12/tags/go%20news%2Fweekly", path)
+			},
+		},
+		// Variables inside a segment.
+		{
+			pattern: "/sitemaps/posts-{page:[0-9]+}.xml",
+			params:  []string{"page", "3"},
+			assertion: func(path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "/sitemaps/posts-3.xml", path)
+			},
+		},
+		// Value out of restriction.
+		{
+			pattern: "/posts/{id:[0-9]+}",
+			params:  []string{"id", "12a"},
+			assertion: func(path string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRouteParams)
+			},
+		},
+		// Missing, unknown and odd params.
+		{
+			pattern: "/posts/{id:[0-9]+}",
+			assertion: func(path string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRouteParams)
+			},
+		},
+		{
+			pattern: "/posts",
+			params:  []string{"id", "1"},
+			assertion: func(path string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRouteParams)
+			},
+		},
+		{
+			pattern: "/posts/{id}",
+			params:  []string{"id"},
+			assertion: func(path string, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRouteParams)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(ReverseURL(c.pattern, c.params...))
+	}
+}
@@ -14,11 +14,14 @@ type Server struct {
 	multiplexer http.Handler
 }
 
-// NewServer instantiates a new Server.
-func NewServer(host string, port int, controllers map[string]map[string]Serve) *Server {
+// NewServer instantiates a new Server, middlewares wrap every endpoint, the first one being the outermost.
+func NewServer(host string, port int, controllers map[string]map[string]Serve, middlewares ...Middleware) *Server {
 	multiplexer := mux.NewRouter()
 	for route, methodGroup := range controllers {
 		serve := generalizeHandler(methodGroup)
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			serve = middlewares[i](serve)
+		}
 		multiplexer.Handle(route, handler{serve: serve})
 	}
 	return &Server{
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamWriter writes a 200 response as it goes, headers are sent along with the first bytes of the body.
+type streamWriter struct {
+	writer  http.ResponseWriter
+	started bool
+}
+
+// Write writes to response, flushing it so that clients receive the body while it's being produced.
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.writer.WriteHeader(http.StatusOK)
+	}
+	n, err := w.writer.Write(p)
+	if flusher, ok := w.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// ServeStream serves a 200 response written by write, so that big bodies don't have to fit in memory. A filename
+// makes clients save the body as a file.
+// If write fails before writing anything a 400 response is served instead, later errors cut the body short.
+func (s *ControllerSuite) ServeStream(contentType string, filename string, write func(w io.Writer) error) {
+	header := s.writer.Header()
+	header.Set("Content-Type", contentType)
+	if filename != "" {
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	w := &streamWriter{writer: s.writer}
+	err := write(w)
+	switch {
+	case err == nil:
+		if !w.started {
+			s.writer.WriteHeader(http.StatusOK)
+		}
+	case !w.started:
+		header.Del("Content-Disposition")
+		s.ServeBadRequest(err.Error())
+	default:
+		logrus.WithError(err).Errorf("failed to stream response")
+	}
+}
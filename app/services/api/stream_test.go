@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerSuite_ServeStream(t *testing.T) {
+	SetJSONConfig(JSONConfig{})
+	cases := []struct {
+		write     func(w io.Writer) error
+		assertion func(*httptest.ResponseRecorder)
+	}{
+		// Streamed body.
+		{
+			write: func(w io.Writer) error {
+				_, err := io.WriteString(w, "id\n1\n")
+				return err
+			},
+			assertion: func(r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, "text/csv", r.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="posts.csv"`, r.Header().Get("Content-Disposition"))
+				assert.Equal(t, "id\n1\n", r.Body.String())
+				assert.True(t, r.Flushed)
+			},
+		},
+		// Failure before the body started.
+		{
+			write: func(w io.Writer) error {
+				return errors.New("unknown column")
+			},
+			assertion: func(r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
+				assert.Empty(t, r.Header().Get("Content-Disposition"))
+				assert.Contains(t, r.Body.String(), "unknown column")
+			},
+		},
+		// Failure halfway through cuts the body short.
+		{
+			write: func(w io.Writer) error {
+				_, _ = io.WriteString(w, "id\n")
+				return errors.New("connection lost")
+			},
+			assertion: func(r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, "id\n", r.Body.String())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		var s ControllerSuite
+		s.NewRequest(recorder, httptest.NewRequest(http.MethodGet, "/posts/export", nil))
+		s.ServeStream("text/csv", "posts.csv", c.write)
+		c.assertion(recorder)
+	}
+}
@@ -1,9 +1,13 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -23,8 +27,7 @@ func (s *ControllerSuite) NewRequest(writer http.ResponseWriter, request *http.R
 
 // ServeOK serves a 200 response.
 func (s *ControllerSuite) ServeOK(object interface{}) {
-	s.writer.WriteHeader(http.StatusOK)
-	s.RenderJSON(object)
+	s.ServeJSON(http.StatusOK, object)
 }
 
 // ServeMessageOK serves a 200 response with string message.
@@ -41,49 +44,122 @@ func (s *ControllerSuite) ServeEmptyOK() {
 
 // ServeCreated serves a 201 response with a provided object.
 func (s *ControllerSuite) ServeCreated(object interface{}) {
-	s.writer.WriteHeader(http.StatusCreated)
-	s.RenderJSON(object)
+	s.ServeJSON(http.StatusCreated, object)
+}
+
+// ServeRedirect serves a redirect with a provided status to location.
+func (s *ControllerSuite) ServeRedirect(status int, location string) {
+	s.writer.Header().Set("Location", location)
+	s.ServeMessage(status, http.StatusText(status))
 }
 
 // ServeBadRequest serves a 400 response with a provided message.
 func (s *ControllerSuite) ServeBadRequest(message string) {
-	s.writer.WriteHeader(http.StatusBadRequest)
-	response := make(map[string]string)
-	response["message"] = message
-	s.RenderJSON(response)
+	s.ServeMessage(http.StatusBadRequest, message)
+}
+
+// ServeUnauthorized serves a 401 response with a provided message.
+func (s *ControllerSuite) ServeUnauthorized(message string) {
+	s.ServeMessage(http.StatusUnauthorized, message)
+}
+
+// ServeForbidden serves a 403 response with a provided message.
+func (s *ControllerSuite) ServeForbidden(message string) {
+	s.ServeMessage(http.StatusForbidden, message)
 }
 
 // ServeNotFound serves a standard 404 error.
 func (s *ControllerSuite) ServeNotFound() {
-	s.writer.WriteHeader(http.StatusNotFound)
-	response := make(map[string]string)
-	response["message"] = fmt.Sprintf("no handler registered at route %v for method %v", s.request.Method, s.request.URL.Path)
-	s.RenderJSON(response)
+	s.ServeMessage(http.StatusNotFound, fmt.Sprintf("no handler registered at route %v for method %v", s.request.Method, s.request.URL.Path))
 }
 
 // ServeConflict serves a 409 response with a provided message.
 func (s *ControllerSuite) ServeConflict(message string) {
-	s.writer.WriteHeader(http.StatusConflict)
-	response := make(map[string]string)
-	response["message"] = message
-	s.RenderJSON(response)
+	s.ServeMessage(http.StatusConflict, message)
+}
+
+// ServeUnsupportedMediaType serves a 415 response with a provided message.
+func (s *ControllerSuite) ServeUnsupportedMediaType(message string) {
+	s.ServeMessage(http.StatusUnsupportedMediaType, message)
+}
+
+// ServeUnprocessableEntity serves a 422 response with a provided message.
+func (s *ControllerSuite) ServeUnprocessableEntity(message string) {
+	s.ServeMessage(http.StatusUnprocessableEntity, message)
+}
+
+// ServeTooManyRequests serves a 429 response with a provided message, telling client to retry after a given delay.
+func (s *ControllerSuite) ServeTooManyRequests(message string, retry time.Duration) {
+	s.writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+	s.ServeMessage(http.StatusTooManyRequests, message)
 }
 
 func (s *ControllerSuite) ServeInternalError(message string) {
-	s.writer.WriteHeader(http.StatusInternalServerError)
+	s.ServeMessage(http.StatusInternalServerError, message)
+}
+
+// ServeMessage serves a response with provided status and string message.
+func (s *ControllerSuite) ServeMessage(status int, message string) {
 	response := make(map[string]string)
 	response["message"] = message
-	s.RenderJSON(response)
+	s.ServeJSON(status, response)
+}
+
+// ServeJSON serves a response with provided status, rendering object in the format negotiated with the client.
+func (s *ControllerSuite) ServeJSON(status int, object interface{}) {
+	if s.WantsJSONAPI() {
+		s.renderBytes(status, JSONAPIContentType, s.marshalJSONAPI(status, object))
+		return
+	}
+	s.renderBytes(status, "application/json", s.marshalJSON(object))
+}
+
+// ServeBytes serves a response with provided status and a raw body of provided media type.
+func (s *ControllerSuite) ServeBytes(status int, contentType string, body []byte) {
+	s.renderBytes(status, contentType, body)
 }
 
 // RenderJSON writes a json to response.
 func (s *ControllerSuite) RenderJSON(response interface{}) {
 	s.writer.Header().Set("Content-Type", "application/json")
-	bytesResponse, err := json.Marshal(response)
+	s.writeBytes(s.marshalJSON(response))
+}
+
+// marshalJSON marshals an object according to request json settings.
+func (s *ControllerSuite) marshalJSON(object interface{}) []byte {
+	bytesResponse, err := MarshalJSON(object, s.JSONSettings())
 	if err != nil {
 		logrus.WithError(err).Fatalf("failed to marshal response")
 	}
-	_, err = s.writer.Write(bytesResponse)
+	return bytesResponse
+}
+
+// renderBytes writes headers, status and body, in that order.
+// Successful GET responses get an ETag (unless the controller set one) and are answered with 304 if the client's copy
+// is still fresh, errors are never cached.
+func (s *ControllerSuite) renderBytes(status int, contentType string, body []byte) {
+	header := s.writer.Header()
+	if status >= http.StatusBadRequest {
+		header.Del("Cache-Control")
+	}
+	if s.request != nil && s.cacheable(status) {
+		header.Add("Vary", "Accept")
+		if header.Get("ETag") == "" {
+			s.SetETag(bodyETag(body))
+		}
+		if s.notModified() {
+			s.writer.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	header.Set("Content-Type", contentType)
+	s.writer.WriteHeader(status)
+	s.writeBytes(body)
+}
+
+// writeBytes writes raw body to response.
+func (s *ControllerSuite) writeBytes(body []byte) {
+	_, err := s.writer.Write(body)
 	if err != nil {
 		logrus.WithError(err).Fatalf("failed to write response")
 	}
@@ -94,8 +170,31 @@ func (s *ControllerSuite) ParseURLParams() map[string]string {
 	return mux.Vars(s.request)
 }
 
-// ParseJSONBody parses request body.
+// ParseQueryParams returns parsed url query params.
+func (s *ControllerSuite) ParseQueryParams() url.Values {
+	return s.request.URL.Query()
+}
+
+// Body returns the raw request body, for bodies that are read as a stream.
+func (s *ControllerSuite) Body() io.Reader {
+	return s.request.Body
+}
+
+// ParseJSONBody parses request body, JSON:API documents are flattened into target.
 // This function really shouldn't be here...
 func (s *ControllerSuite) ParseJSONBody(target any) error {
-	return json.NewDecoder(s.request.Body).Decode(&target)
+	if s.IsJSONAPIRequest() {
+		return DecodeJSONAPI(s.request.Body, target, s.JSONSettings())
+	}
+	return DecodeJSON(s.request.Body, target, s.JSONSettings())
+}
+
+// JSONSettings returns json settings for current request, ?pretty query param overrides the configured value.
+func (s *ControllerSuite) JSONSettings() JSONConfig {
+	cfg := JSONSettings()
+	if s.request != nil && s.request.URL.Query().Has("pretty") {
+		pretty := s.request.URL.Query().Get("pretty")
+		cfg.Pretty = pretty == "" || pretty == "true" || pretty == "1"
+	}
+	return cfg
 }
@@ -6,3 +6,6 @@ import (
 
 // Serve is an alias to a http endpoint functional handler.
 type Serve func(http.ResponseWriter, *http.Request)
+
+// Middleware wraps a Serve, adding behaviour shared by all endpoints.
+type Middleware func(Serve) Serve
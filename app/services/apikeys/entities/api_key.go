@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+const (
+	// KeyPrefix starts every API key, so that leaked keys are easy to recognize.
+	KeyPrefix = "rak_"
+	// PrefixLength is the length of the part of a key that identifies it, KeyPrefix included.
+	PrefixLength = len(KeyPrefix) + 12
+)
+
+type (
+	KeyID uint64
+)
+
+// APIKey represents a personal access token of a user. Only a hash of the key is stored, the key itself is shown
+// once when it's created, and its prefix is kept to tell keys apart.
+type APIKey struct {
+	ID         KeyID               `json:"id" gorm:"column:id; primary_key:yes"`
+	UserID     userentities.UserID `json:"user_id" gorm:"column:user_id"`
+	Name       string              `json:"name" gorm:"column:name"`
+	Prefix     string              `json:"prefix" gorm:"column:prefix"`
+	Hash       string              `json:"-" gorm:"column:hash"`
+	Scopes     Scopes              `json:"scopes" gorm:"column:scopes"`
+	ExpiresAt  *time.Time          `json:"expires_at" gorm:"column:expires_at"`
+	LastUsedAt *time.Time          `json:"last_used_at" gorm:"column:last_used_at"`
+	CreatedAt  time.Time           `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName ...
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Validate checks whether a given APIKey object is valid at a given time.
+func (k APIKey) Validate(now time.Time) error {
+	if len(k.Name) == 0 || len(k.Name) > 128 || strings.TrimSpace(k.Name) != k.Name {
+		return ErrInvalidName
+	}
+	if err := k.Scopes.Validate(); err != nil {
+		return err
+	}
+	if k.Expired(now) {
+		return ErrInvalidExpiry
+	}
+	return nil
+}
+
+// Expired checks whether the key has expired at a given time.
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
+}
+
+// CreatedKey is a newly created API key along with the key itself, served only once.
+type CreatedKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// KeyPrefixOf returns the identifying prefix of an API key, false if key isn't shaped like one.
+func KeyPrefixOf(key string) (string, bool) {
+	if !strings.HasPrefix(key, KeyPrefix) || len(key) <= PrefixLength+1 || key[PrefixLength] != '_' {
+		return "", false
+	}
+	return key[:PrefixLength], true
+}
+
+// HashKey derives the stored hash of an API key. Keys are random enough that a fast hash is safe.
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrInvalidKey is thrown when an API key is malformed, unknown or expired.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrKeyNotFound is thrown when a user has no API key with a given id.
+	ErrKeyNotFound = errors.New("no api key found")
+	// ErrInvalidName is thrown when an API key name is empty or too long.
+	ErrInvalidName = errors.New("api key name has to be 1 to 128 characters long")
+	// ErrInvalidScopes is thrown when an API key is given no scopes or an unknown one.
+	ErrInvalidScopes = errors.New("invalid api key scopes")
+	// ErrInvalidExpiry is thrown when an API key is created already expired.
+	ErrInvalidExpiry = errors.New("api key expiry has to be in the future")
+)
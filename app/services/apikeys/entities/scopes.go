@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	helpers "github.com/samber/lo"
+)
+
+// KnownScopes are the scopes API keys can be granted, routes declare which one they need in app/config/routes.
+var KnownScopes = []string{
+	"posts:read", "posts:write",
+	"tags:read", "tags:write",
+	"users:read", "users:write",
+	"newsletter:read", "newsletter:write",
+}
+
+// Scopes is a list of scopes granted to an API key, stored space-separated like OAuth 2.0 scope parameters.
+type Scopes []string
+
+// Validate checks that at least one scope is granted and that all of them are known.
+func (s Scopes) Validate() error {
+	if len(s) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidScopes)
+	}
+	for _, scope := range s {
+		if !helpers.Contains(KnownScopes, scope) {
+			return fmt.Errorf("%w: unknown scope %q", ErrInvalidScopes, scope)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s Scopes) Value() (driver.Value, error) {
+	return strings.Join(s, " "), nil
+}
+
+// Scan implements sql.Scanner.
+func (s *Scopes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*s = strings.Fields(v)
+	case []byte:
+		*s = strings.Fields(string(v))
+	case nil:
+		*s = nil
+	default:
+		return fmt.Errorf("can't scan %T into scopes", value)
+	}
+	return nil
+}
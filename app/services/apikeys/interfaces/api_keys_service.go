@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// APIKeysService is an interface that is used by outside packages to manage and authenticate API keys.
+type APIKeysService interface {
+	CreateKey(ctx context.Context, key *entities.APIKey) (string, error)
+	IndexKeys(ctx context.Context, id userentities.UserID) ([]entities.APIKey, error)
+	RevokeKey(ctx context.Context, id userentities.UserID, key entities.KeyID) error
+	Authenticate(ctx context.Context, key string) (entities.APIKey, error)
+}
@@ -0,0 +1,129 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/interfaces"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// touchInterval is how often last use of a key is recorded, so that every request doesn't write to the db.
+const touchInterval = time.Minute
+
+// Verify that APIKeysService satisfies the interfaces.APIKeysService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.APIKeysService = (*APIKeysService)(nil)
+
+// APIKeysService implements interfaces.APIKeysService.
+type APIKeysService struct {
+	reader *gorm.DB
+	writer *gorm.DB
+}
+
+// NewAPIKeysService instantiates a new APIKeysService.
+func NewAPIKeysService(ctx context.Context, reader *gorm.DB, writer *gorm.DB) (*APIKeysService, error) {
+	if reader == nil || writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	return &APIKeysService{
+		reader: reader,
+		writer: writer,
+	}, nil
+}
+
+// CreateKey generates a new API key for key.UserID with a given name, scopes and optional expiry, filling in the
+// rest of key and returning the key itself, which can't be recovered later.
+func (s *APIKeysService) CreateKey(ctx context.Context, key *entities.APIKey) (string, error) {
+	now := time.Now().UTC()
+	key.Scopes = helpers.Uniq(key.Scopes)
+	if err := key.Validate(now); err != nil {
+		return "", err
+	}
+	prefix, err := random(6)
+	if err != nil {
+		return "", err
+	}
+	secret, err := random(32)
+	if err != nil {
+		return "", err
+	}
+	token := entities.KeyPrefix + prefix + "_" + secret
+	key.ID = 0
+	key.Prefix = token[:entities.PrefixLength]
+	key.Hash = entities.HashKey(token)
+	key.LastUsedAt = nil
+	key.CreatedAt = now
+	if key.ExpiresAt != nil {
+		expires := key.ExpiresAt.UTC()
+		key.ExpiresAt = &expires
+	}
+	err = s.writer.WithContext(ctx).Create(key).Error
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// IndexKeys returns all the API keys of a user, expired ones included, most recently created first.
+func (s *APIKeysService) IndexKeys(ctx context.Context, id userentities.UserID) ([]entities.APIKey, error) {
+	keys := make([]entities.APIKey, 0)
+	err := s.reader.WithContext(ctx).Where("user_id = ?", id).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// RevokeKey deletes an API key of a user, throws entities.ErrKeyNotFound if the user has no such key.
+func (s *APIKeysService) RevokeKey(ctx context.Context, id userentities.UserID, key entities.KeyID) error {
+	result := s.writer.WithContext(ctx).Where("id = ? AND user_id = ?", key, id).Delete(&entities.APIKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate fetches an unexpired API key, recording its use. Throws entities.ErrInvalidKey if key is malformed,
+// unknown or expired.
+func (s *APIKeysService) Authenticate(ctx context.Context, key string) (entities.APIKey, error) {
+	prefix, ok := entities.KeyPrefixOf(key)
+	if !ok {
+		return entities.APIKey{}, entities.ErrInvalidKey
+	}
+	var found entities.APIKey
+	err := s.reader.WithContext(ctx).First(&found, "prefix = ?", prefix).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.APIKey{}, entities.ErrInvalidKey
+		}
+		return entities.APIKey{}, err
+	}
+	now := time.Now().UTC()
+	if subtle.ConstantTimeCompare([]byte(found.Hash), []byte(entities.HashKey(key))) != 1 || found.Expired(now) {
+		return entities.APIKey{}, entities.ErrInvalidKey
+	}
+	if found.LastUsedAt != nil && now.Sub(*found.LastUsedAt) < touchInterval {
+		return found, nil
+	}
+	found.LastUsedAt = &now
+	err = s.writer.WithContext(ctx).Model(&found).Update("last_used_at", now).Error
+	return found, err
+}
+
+// random generates n random bytes, hex encoded.
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,207 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	apiKeysServiceTestInstance interfaces.APIKeysService
+	usersServiceTestInstance   *users.UsersService
+)
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewAPIKeysService(t *testing.T) {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewAPIKeysService(ctx, reader, writer)
+	assert.Nil(t, err)
+	_, err = NewAPIKeysService(ctx, nil, writer)
+	assert.ErrorIs(t, err, entities.ErrNilDB)
+	_, err = NewAPIKeysService(ctx, reader, nil)
+	assert.ErrorIs(t, err, entities.ErrNilDB)
+}
+
+func TestAPIKeysService_CreateKey(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		key       entities.APIKey
+		assertion func(key entities.APIKey, token string, err error)
+	}{
+		// Valid key.
+		{
+			key: entities.APIKey{Name: "test-key", Scopes: entities.Scopes{"posts:read", "posts:write", "posts:read"}, ExpiresAt: &future},
+			assertion: func(key entities.APIKey, token string, err error) {
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NotZero(t, key.ID)
+				assert.Equal(t, token[:entities.PrefixLength], key.Prefix)
+				assert.Equal(t, entities.HashKey(token), key.Hash)
+				assert.Equal(t, entities.Scopes{"posts:read", "posts:write"}, key.Scopes)
+			},
+		},
+		// Empty name.
+		{
+			key: entities.APIKey{Scopes: entities.Scopes{"posts:read"}},
+			assertion: func(key entities.APIKey, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidName)
+			},
+		},
+		// No scopes.
+		{
+			key: entities.APIKey{Name: "test-key"},
+			assertion: func(key entities.APIKey, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidScopes)
+			},
+		},
+		// Unknown scope.
+		{
+			key: entities.APIKey{Name: "test-key", Scopes: entities.Scopes{"posts:delete"}},
+			assertion: func(key entities.APIKey, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidScopes)
+			},
+		},
+		// Already expired.
+		{
+			key: entities.APIKey{Name: "test-key", Scopes: entities.Scopes{"posts:read"}, ExpiresAt: &past},
+			assertion: func(key entities.APIKey, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidExpiry)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		key := c.key
+		key.UserID = user.ID
+		token, err := apiKeysServiceTestInstance.CreateKey(ctx, &key)
+		c.assertion(key, token, err)
+	}
+}
+
+func TestAPIKeysService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	key := entities.APIKey{UserID: user.ID, Name: "test-key", Scopes: entities.Scopes{"posts:read"}}
+	token, err := apiKeysServiceTestInstance.CreateKey(ctx, &key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	found, err := apiKeysServiceTestInstance.Authenticate(ctx, token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, entities.Scopes{"posts:read"}, found.Scopes)
+		assert.NotNil(t, found.LastUsedAt)
+	}
+	for _, invalid := range []string{"", "token", token[:entities.PrefixLength+1] + "0", token + "0"} {
+		_, err = apiKeysServiceTestInstance.Authenticate(ctx, invalid)
+		assert.ErrorIs(t, err, entities.ErrInvalidKey)
+	}
+
+	// Expired keys are rejected.
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = writer.Model(&key).Update("expires_at", time.Now().UTC().Add(-time.Second)).Error
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = apiKeysServiceTestInstance.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, entities.ErrInvalidKey)
+}
+
+func TestAPIKeysService_RevokeKey(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	key := entities.APIKey{UserID: user.ID, Name: "test-key", Scopes: entities.Scopes{"posts:read"}}
+	token, err := apiKeysServiceTestInstance.CreateKey(ctx, &key)
+	if !assert.NoError(t, err) {
+		return
+	}
+	keys, err := apiKeysServiceTestInstance.IndexKeys(ctx, user.ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, keys, 1)
+	}
+
+	assert.ErrorIs(t, apiKeysServiceTestInstance.RevokeKey(ctx, user.ID+1, key.ID), entities.ErrKeyNotFound)
+	assert.NoError(t, apiKeysServiceTestInstance.RevokeKey(ctx, user.ID, key.ID))
+	assert.ErrorIs(t, apiKeysServiceTestInstance.RevokeKey(ctx, user.ID, key.ID), entities.ErrKeyNotFound)
+	_, err = apiKeysServiceTestInstance.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, entities.ErrInvalidKey)
+}
+
+// createUser creates a user keys are created for.
+func createUser(ctx context.Context) (userentities.User, error) {
+	user := userentities.User{Fullname: "test-fullname"}
+	err := usersServiceTestInstance.CreateUser(ctx, &user)
+	return user, err
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewAPIKeysService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	usersService, err := users.NewUsersService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	apiKeysServiceTestInstance = service
+	usersServiceTestInstance = usersService
+	return func(ctx context.Context) {}, nil
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// Credentials represents the login data stored alongside a user, it's kept out of users.User so that it's never
+// served or overwritten by user updates.
+type Credentials struct {
+	ID           userentities.UserID `gorm:"column:id; primary_key:yes"`
+	Email        string              `gorm:"column:email"`
+	PasswordHash string              `gorm:"column:password_hash"`
+	FailedLogins int                 `gorm:"column:failed_logins"`
+	LockedUntil  *time.Time          `gorm:"column:locked_until"`
+}
+
+// TableName ...
+func (Credentials) TableName() string {
+	return "users"
+}
+
+// Locked checks whether logins are locked out at a given time.
+func (c Credentials) Locked(now time.Time) bool {
+	return c.LockedUntil != nil && c.LockedUntil.After(now)
+}
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrInvalidCredentials is thrown when email or password don't match any user.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrAccountLocked is thrown when a user logs in while locked out after too many failed attempts.
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed logins")
+	// ErrSessionNotFound is thrown when a session token or id is invalid or expired.
+	ErrSessionNotFound = errors.New("no session found")
+)
+
+// LockoutError is thrown instead of ErrAccountLocked, so that clients can be told when to retry.
+type LockoutError struct {
+	Until time.Time
+}
+
+// Error ...
+func (e LockoutError) Error() string {
+	return fmt.Sprintf("%v, try again after %v", ErrAccountLocked, e.Until.Format(time.RFC3339))
+}
+
+// Unwrap ...
+func (e LockoutError) Unwrap() error {
+	return ErrAccountLocked
+}
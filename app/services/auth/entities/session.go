@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// Session represents a login of a user. Only a hash of the session token is stored, so it's also safe to serve as id.
+type Session struct {
+	ID         string              `json:"id" gorm:"column:id; primary_key:yes"`
+	UserID     userentities.UserID `json:"user_id" gorm:"column:user_id"`
+	UserAgent  string              `json:"user_agent" gorm:"column:user_agent"`
+	IP         string              `json:"ip" gorm:"column:ip"`
+	LastSeenAt time.Time           `json:"last_seen_at" gorm:"column:last_seen_at"`
+	ExpiresAt  time.Time           `json:"expires_at" gorm:"column:expires_at"`
+	CreatedAt  time.Time           `json:"created_at" gorm:"column:created_at"`
+	// Current marks the session a listing has been requested with.
+	Current bool `json:"current" gorm:"-"`
+}
+
+// TableName ...
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// Client describes where a session has been started from.
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+// HashToken derives a session id from a session token.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
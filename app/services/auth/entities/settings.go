@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/passwords"
+)
+
+// Settings configure authentication.
+type Settings struct {
+	// Params are used to hash new passwords, stored hashes with different params are upgraded on login.
+	Params passwords.Params
+	// MaxFailedLogins is the number of failed logins in a row after which a user is locked out.
+	MaxFailedLogins int
+	// Lockout is how long a user is locked out for.
+	Lockout time.Duration
+	// SessionTTL is how long a session lasts since it has been last used.
+	SessionTTL time.Duration
+}
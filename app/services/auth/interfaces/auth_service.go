@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// AuthService is an interface that is used by outside packages to log users in and manage their sessions.
+type AuthService interface {
+	SetPassword(ctx context.Context, id userentities.UserID, password string) error
+	ChangePassword(ctx context.Context, id userentities.UserID, current string, password string) error
+	Login(ctx context.Context, email string, password string, client entities.Client) (entities.Session, string, error)
+	VerifyCredentials(ctx context.Context, email string, password string) (userentities.UserID, error)
+	Authenticate(ctx context.Context, token string) (entities.Session, error)
+	IndexSessions(ctx context.Context, id userentities.UserID) ([]entities.Session, error)
+	RevokeSession(ctx context.Context, id userentities.UserID, session string) error
+	RevokeSessions(ctx context.Context, id userentities.UserID, except string) (int64, error)
+	CleanupSessions(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,273 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/passwords"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// touchInterval is how often last use of a session is recorded, so that every request doesn't write to the db.
+const touchInterval = time.Minute
+
+// Verify that AuthService satisfies the interfaces.AuthService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.AuthService = (*AuthService)(nil)
+
+// AuthService implements interfaces.AuthService.
+type AuthService struct {
+	reader   *gorm.DB
+	writer   *gorm.DB
+	settings entities.Settings
+}
+
+// NewAuthService instantiates a new AuthService.
+func NewAuthService(ctx context.Context, reader *gorm.DB, writer *gorm.DB, settings entities.Settings) (*AuthService, error) {
+	if reader == nil || writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	return &AuthService{
+		reader:   reader,
+		writer:   writer,
+		settings: settings,
+	}, nil
+}
+
+// SetPassword sets a password of a user, lifting any lockout. Throws passwords.ErrInvalidPassword if password is too
+// short or too long, and userentities.ErrUserNotFound if id is invalid.
+func (s *AuthService) SetPassword(ctx context.Context, id userentities.UserID, password string) error {
+	if err := passwords.Validate(password); err != nil {
+		return err
+	}
+	hash, err := passwords.Hash(password, s.settings.Params)
+	if err != nil {
+		return err
+	}
+	result := s.writer.WithContext(ctx).Model(&entities.Credentials{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password_hash": hash,
+		"failed_logins": 0,
+		"locked_until":  nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return userentities.ErrUserNotFound
+	}
+	return nil
+}
+
+// ChangePassword sets a new password of a user who knows the current one, throws entities.ErrInvalidCredentials if
+// current password is wrong.
+func (s *AuthService) ChangePassword(ctx context.Context, id userentities.UserID, current string, password string) error {
+	var credentials entities.Credentials
+	err := s.reader.WithContext(ctx).First(&credentials, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return userentities.ErrUserNotFound
+		}
+		return err
+	}
+	if credentials.PasswordHash == "" {
+		return entities.ErrInvalidCredentials
+	}
+	ok, _, err := passwords.Verify(current, credentials.PasswordHash, s.settings.Params)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return entities.ErrInvalidCredentials
+	}
+	return s.SetPassword(ctx, id, password)
+}
+
+// Login starts a session for a user with a given email and password, returning the session and its token.
+// Hashes made with outdated params are upgraded. Throws entities.ErrInvalidCredentials if email or password are wrong,
+// and entities.LockoutError once the user has failed to log in settings.MaxFailedLogins times in a row.
+func (s *AuthService) Login(ctx context.Context, email string, password string, client entities.Client) (entities.Session, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return entities.Session{}, "", err
+	}
+	var (
+		session entities.Session
+		failure error
+	)
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			id  userentities.UserID
+			err error
+		)
+		id, failure, err = s.verify(tx, email, password)
+		if err != nil || failure != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		session = entities.Session{
+			ID:         entities.HashToken(token),
+			UserID:     id,
+			UserAgent:  truncate(client.UserAgent, 512),
+			IP:         truncate(client.IP, 64),
+			LastSeenAt: now,
+			ExpiresAt:  now.Add(s.settings.SessionTTL),
+			CreatedAt:  now,
+		}
+		return tx.Create(&session).Error
+	})
+	if err != nil {
+		return entities.Session{}, "", err
+	}
+	if failure != nil {
+		return entities.Session{}, "", failure
+	}
+	return session, token, nil
+}
+
+// VerifyCredentials checks email and password like Login does, without starting a session.
+func (s *AuthService) VerifyCredentials(ctx context.Context, email string, password string) (userentities.UserID, error) {
+	var (
+		id      userentities.UserID
+		failure error
+	)
+	err := s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		id, failure, err = s.verify(tx, email, password)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, failure
+}
+
+// verify checks email and password within a transaction, counting failed attempts and upgrading outdated hashes.
+// Wrong credentials and lockouts are returned as failure, so that the transaction still commits the attempt.
+func (s *AuthService) verify(tx *gorm.DB, email string, password string) (id userentities.UserID, failure error, err error) {
+	email, err = userentities.NormalizeEmail(email)
+	if err != nil {
+		return 0, entities.ErrInvalidCredentials, nil
+	}
+	var credentials entities.Credentials
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&credentials, "email = ?", email).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil, err
+	}
+	if credentials.PasswordHash == "" {
+		// Hash anyway, so that unknown emails can't be told apart by response time.
+		_, err = passwords.Hash(password, s.settings.Params)
+		return 0, entities.ErrInvalidCredentials, err
+	}
+	now := time.Now().UTC()
+	if credentials.Locked(now) {
+		return 0, entities.LockoutError{Until: *credentials.LockedUntil}, nil
+	}
+	ok, rehash, err := passwords.Verify(password, credentials.PasswordHash, s.settings.Params)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		failure = entities.ErrInvalidCredentials
+		updates := map[string]interface{}{"failed_logins": credentials.FailedLogins + 1}
+		if credentials.FailedLogins+1 >= s.settings.MaxFailedLogins {
+			until := now.Add(s.settings.Lockout)
+			failure = entities.LockoutError{Until: until}
+			updates = map[string]interface{}{"failed_logins": 0, "locked_until": until}
+		}
+		return 0, failure, tx.Model(&credentials).Updates(updates).Error
+	}
+
+	updates := map[string]interface{}{"failed_logins": 0, "locked_until": nil}
+	if rehash {
+		hash, err := passwords.Hash(password, s.settings.Params)
+		if err != nil {
+			return 0, nil, err
+		}
+		updates["password_hash"] = hash
+	}
+	return credentials.ID, nil, tx.Model(&credentials).Updates(updates).Error
+}
+
+// Authenticate fetches an unexpired session by its token, extending it. Throws entities.ErrSessionNotFound if token is
+// invalid or the session has expired.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (entities.Session, error) {
+	var session entities.Session
+	now := time.Now().UTC()
+	err := s.reader.WithContext(ctx).First(&session, "id = ? AND expires_at > ?", entities.HashToken(token), now).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Session{}, entities.ErrSessionNotFound
+		}
+		return entities.Session{}, err
+	}
+	if now.Sub(session.LastSeenAt) < touchInterval {
+		return session, nil
+	}
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(s.settings.SessionTTL)
+	err = s.writer.WithContext(ctx).Model(&session).Updates(map[string]interface{}{
+		"last_seen_at": session.LastSeenAt,
+		"expires_at":   session.ExpiresAt,
+	}).Error
+	return session, err
+}
+
+// IndexSessions returns unexpired sessions of a user, most recently used first.
+func (s *AuthService) IndexSessions(ctx context.Context, id userentities.UserID) ([]entities.Session, error) {
+	sessions := make([]entities.Session, 0)
+	err := s.reader.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", id, time.Now().UTC()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession ends a session of a user, throws entities.ErrSessionNotFound if the user has no such session.
+func (s *AuthService) RevokeSession(ctx context.Context, id userentities.UserID, session string) error {
+	result := s.writer.WithContext(ctx).Where("id = ? AND user_id = ?", session, id).Delete(&entities.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeSessions ends all sessions of a user but except, returning the number of sessions ended.
+func (s *AuthService) RevokeSessions(ctx context.Context, id userentities.UserID, except string) (int64, error) {
+	result := s.writer.WithContext(ctx).Where("user_id = ? AND id <> ?", id, except).Delete(&entities.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// CleanupSessions deletes expired sessions, returning the number of sessions deleted.
+func (s *AuthService) CleanupSessions(ctx context.Context) (int64, error) {
+	result := s.writer.WithContext(ctx).Where("expires_at <= ?", time.Now().UTC()).Delete(&entities.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// newToken generates a random session token.
+func newToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// truncate cuts a string down to at most n bytes, so that client supplied values fit their columns.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return strings.ToValidUTF8(s[:n], "")
+	}
+	return s
+}
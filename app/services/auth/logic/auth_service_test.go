@@ -0,0 +1,390 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/passwords"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	authServiceTestInstance  interfaces.AuthService
+	usersServiceTestInstance *users.UsersService
+	testSettings             = entities.Settings{
+		Params:          passwords.Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		MaxFailedLogins: 3,
+		Lockout:         time.Minute,
+		SessionTTL:      time.Hour,
+	}
+)
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewAuthService(t *testing.T) {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewAuthService(ctx, reader, writer, testSettings)
+	assert.Nil(t, err)
+	_, err = NewAuthService(ctx, nil, writer, testSettings)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, entities.ErrNilDB)
+	}
+}
+
+func TestAuthService_SetPassword(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-set-password@example.com", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	cases := []struct {
+		id        userentities.UserID
+		password  string
+		assertion func(err error)
+	}{
+		// Valid.
+		{
+			id:       user.ID,
+			password: "test-password",
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		// Too short.
+		{
+			id:       user.ID,
+			password: "short",
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, passwords.ErrInvalidPassword)
+			},
+		},
+		// Too long.
+		{
+			id:       user.ID,
+			password: strings.Repeat("x", passwords.MaxLength+1),
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, passwords.ErrInvalidPassword)
+			},
+		},
+		// Unknown user.
+		{
+			id:       user.ID + 1000000,
+			password: "test-password",
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, userentities.ErrUserNotFound)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(authServiceTestInstance.SetPassword(ctx, c.id, c.password))
+	}
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-change-password@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	err = authServiceTestInstance.ChangePassword(ctx, user.ID, "wrong-password", "test-new-password")
+	assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+	err = authServiceTestInstance.ChangePassword(ctx, user.ID, "test-password", "test-new-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "test-new-password", entities.Client{})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-login@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	client := entities.Client{UserAgent: "test-agent", IP: "127.0.0.1"}
+
+	cases := []struct {
+		email     string
+		password  string
+		assertion func(session entities.Session, token string, err error)
+	}{
+		// Valid, email is case insensitive.
+		{
+			email:    "Test-Login@Example.com",
+			password: "test-password",
+			assertion: func(session entities.Session, token string, err error) {
+				if assert.NoError(t, err) {
+					assert.NotEmpty(t, token)
+					assert.Equal(t, entities.HashToken(token), session.ID)
+					assert.Equal(t, user.ID, session.UserID)
+					assert.Equal(t, "test-agent", session.UserAgent)
+					assert.True(t, session.ExpiresAt.After(time.Now()))
+				}
+			},
+		},
+		// Wrong password.
+		{
+			email:    "test-login@example.com",
+			password: "wrong-password",
+			assertion: func(session entities.Session, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+			},
+		},
+		// Unknown email.
+		{
+			email:    "test-unknown@example.com",
+			password: "test-password",
+			assertion: func(session entities.Session, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+			},
+		},
+		// Malformed email.
+		{
+			email:    "test-email",
+			password: "test-password",
+			assertion: func(session entities.Session, token string, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		session, token, err := authServiceTestInstance.Login(ctx, c.email, c.password, client)
+		c.assertion(session, token, err)
+	}
+}
+
+func TestAuthService_VerifyCredentials(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-verify@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	id, err := authServiceTestInstance.VerifyCredentials(ctx, user.Email, "test-password")
+	if assert.NoError(t, err) {
+		assert.Equal(t, user.ID, id)
+	}
+	_, err = authServiceTestInstance.VerifyCredentials(ctx, user.Email, "wrong-password")
+	assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+	sessions, err := authServiceTestInstance.IndexSessions(ctx, user.ID)
+	if assert.NoError(t, err) {
+		assert.Empty(t, sessions)
+	}
+}
+
+func TestAuthService_Login_Lockout(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-lockout@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	for i := 1; i < testSettings.MaxFailedLogins; i++ {
+		_, _, err = authServiceTestInstance.Login(ctx, user.Email, "wrong-password", entities.Client{})
+		assert.ErrorIs(t, err, entities.ErrInvalidCredentials)
+	}
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "wrong-password", entities.Client{})
+	var lockout entities.LockoutError
+	if assert.True(t, errors.As(err, &lockout)) {
+		assert.WithinDuration(t, time.Now().Add(testSettings.Lockout), lockout.Until, 5*time.Second)
+	}
+	// Right password doesn't help while locked out.
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	assert.ErrorIs(t, err, entities.ErrAccountLocked)
+	// Resetting the password lifts the lockout.
+	if !assert.NoError(t, authServiceTestInstance.SetPassword(ctx, user.ID, "test-password")) {
+		return
+	}
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_Login_Rehash(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-rehash@example.com", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	outdated := testSettings.Params
+	outdated.Iterations++
+	hash, err := passwords.Hash("test-password", outdated)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = writer.Model(&entities.Credentials{ID: user.ID}).Update("password_hash", hash).Error
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, _, err = authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var credentials entities.Credentials
+	if assert.NoError(t, writer.First(&credentials, "id = ?", user.ID).Error) {
+		assert.NotEqual(t, hash, credentials.PasswordHash)
+		_, rehash, err := passwords.Verify("test-password", credentials.PasswordHash, testSettings.Params)
+		assert.NoError(t, err)
+		assert.False(t, rehash)
+	}
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-sessions@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	first, firstToken, err := authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	second, secondToken, err := authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, thirdToken, err := authServiceTestInstance.Login(ctx, user.Email, "test-password", entities.Client{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	session, err := authServiceTestInstance.Authenticate(ctx, firstToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, first.ID, session.ID)
+	}
+	_, err = authServiceTestInstance.Authenticate(ctx, "test-invalid-token")
+	assert.ErrorIs(t, err, entities.ErrSessionNotFound)
+
+	sessions, err := authServiceTestInstance.IndexSessions(ctx, user.ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, sessions, 3)
+	}
+
+	assert.NoError(t, authServiceTestInstance.RevokeSession(ctx, user.ID, second.ID))
+	assert.ErrorIs(t, authServiceTestInstance.RevokeSession(ctx, user.ID, second.ID), entities.ErrSessionNotFound)
+	_, err = authServiceTestInstance.Authenticate(ctx, secondToken)
+	assert.ErrorIs(t, err, entities.ErrSessionNotFound)
+
+	revoked, err := authServiceTestInstance.RevokeSessions(ctx, user.ID, first.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), revoked)
+	}
+	_, err = authServiceTestInstance.Authenticate(ctx, thirdToken)
+	assert.ErrorIs(t, err, entities.ErrSessionNotFound)
+	_, err = authServiceTestInstance.Authenticate(ctx, firstToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthService_CleanupSessions(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx, "test-cleanup@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	reader, err := database.GetReader(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	settings := testSettings
+	settings.SessionTTL = -time.Second
+	expired, err := NewAuthService(ctx, reader, writer, settings)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, token, err := expired.Login(ctx, user.Email, "test-password", entities.Client{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = authServiceTestInstance.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, entities.ErrSessionNotFound)
+	deleted, err := authServiceTestInstance.CleanupSessions(ctx)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, deleted, int64(1))
+	}
+}
+
+// createUser creates a user with a given email, and a password unless it's empty.
+func createUser(ctx context.Context, email string, password string) (userentities.User, error) {
+	user := userentities.User{Fullname: "test-fullname", Email: email}
+	if err := usersServiceTestInstance.CreateUser(ctx, &user); err != nil {
+		return userentities.User{}, err
+	}
+	if password == "" {
+		return user, nil
+	}
+	return user, authServiceTestInstance.SetPassword(ctx, user.ID, password)
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewAuthService(ctx, reader, writer, testSettings)
+	if err != nil {
+		return nil, err
+	}
+	usersService, err := users.NewUsersService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	authServiceTestInstance = service
+	usersServiceTestInstance = usersService
+	return func(ctx context.Context) {}, nil
+}
@@ -0,0 +1,107 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// atom is the root element of an Atom 1.0 document.
+type atom struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Language string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom renders a feed as an Atom 1.0 document, the feed is identified by its Self URL.
+func Atom(feed Feed) ([]byte, error) {
+	document := atom{
+		Language: feed.Language,
+		ID:       feed.Self,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  atomTime(feed.Updated),
+		Links: []atomLink{
+			{Href: feed.Self, Rel: "self", Type: AtomContentType},
+			{Href: feed.Link, Rel: "alternate"},
+		},
+		Author:  author(feed.Author),
+		Entries: make([]atomEntry, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:         item.ID,
+			Title:      item.Title,
+			Link:       atomLink{Href: item.Link, Rel: "alternate"},
+			Author:     author(item.Author),
+			Categories: make([]atomCategory, 0, len(item.Categories)),
+			Published:  atomTime(item.Published),
+			Updated:    atomTime(item.Updated),
+			Summary:    item.Summary,
+			Content:    atomText{Type: "html", Value: item.ContentHTML},
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+	return marshal(document)
+}
+
+// author returns an Atom author with name, or nil if the name is unknown.
+func author(name string) *atomAuthor {
+	if name == "" {
+		return nil
+	}
+	return &atomAuthor{Name: name}
+}
+
+// atomTime formats a timestamp the way Atom expects, RFC 3339.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// marshal renders an XML document along with its declaration.
+func marshal(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
@@ -0,0 +1,38 @@
+package feeds
+
+import (
+	"time"
+)
+
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Feed is a syndication feed, rendered as either RSS 2.0 or Atom 1.0.
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the page the feed describes, and Self is the URL the feed is served at.
+	Link     string
+	Self     string
+	Author   string
+	Language string
+	// Updated is the last time anything in the feed changed.
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is a single entry of a feed.
+type Item struct {
+	// ID identifies the item for good, unlike Link, which may change.
+	ID          string
+	Title       string
+	Link        string
+	Author      string
+	Summary     string
+	ContentHTML string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
@@ -0,0 +1,98 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFeed = Feed{
+	Title:       "Newsletter",
+	Description: "Latest posts",
+	Link:        "https://example.com",
+	Self:        "https://example.com/feeds/posts.atom",
+	Author:      "Editors",
+	Language:    "en",
+	Updated:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	Items: []Item{
+		{
+			ID:          "https://example.com/posts/1",
+			Title:       "Fish & <chips>",
+			Link:        "https://example.com/posts/by-slug/fish-chips",
+			Author:      "Jane",
+			Summary:     "Fish and chips",
+			ContentHTML: "<p>Fish &amp; chips ]]> done</p>",
+			Categories:  []string{"food"},
+			Published:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	},
+}
+
+func TestRSS(t *testing.T) {
+	body, err := RSS(testFeed)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rendered := string(body)
+	assert.True(t, strings.HasPrefix(rendered, xml.Header))
+	assert.Contains(t, rendered, `<rss version="2.0"`)
+	assert.Contains(t, rendered, `<atom:link href="https://example.com/feeds/posts.atom" rel="self"`)
+	assert.Contains(t, rendered, "<lastBuildDate>Fri, 02 Jan 2026 03:04:05 GMT</lastBuildDate>")
+	assert.Contains(t, rendered, "<title>Fish &amp; &lt;chips&gt;</title>")
+	assert.Contains(t, rendered, `<guid isPermaLink="false">https://example.com/posts/1</guid>`)
+	assert.Contains(t, rendered, "<dc:creator>Jane</dc:creator>")
+	assert.Contains(t, rendered, "<category>food</category>")
+	assert.Contains(t, rendered, "<pubDate>Thu, 01 Jan 2026 00:00:00 GMT</pubDate>")
+
+	// Content survives a round trip, even if it contains a CDATA terminator.
+	var parsed struct {
+		Items []struct {
+			Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		} `xml:"channel>item"`
+	}
+	if assert.NoError(t, xml.Unmarshal(body, &parsed)) && assert.Len(t, parsed.Items, 1) {
+		assert.Equal(t, testFeed.Items[0].ContentHTML, parsed.Items[0].Content)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := Atom(testFeed)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rendered := string(body)
+	assert.Contains(t, rendered, `<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en">`)
+	assert.Contains(t, rendered, "<id>https://example.com/feeds/posts.atom</id>")
+	assert.Contains(t, rendered, "<updated>2026-01-02T03:04:05Z</updated>")
+	assert.Contains(t, rendered, `<link href="https://example.com/feeds/posts.atom" rel="self" type="application/atom+xml; charset=utf-8"></link>`)
+	assert.Contains(t, rendered, "<published>2026-01-01T00:00:00Z</published>")
+	assert.Contains(t, rendered, `<category term="food"></category>`)
+
+	var parsed struct {
+		Entries []struct {
+			ID      string `xml:"id"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+			Author string `xml:"author>name"`
+		} `xml:"entry"`
+	}
+	if assert.NoError(t, xml.Unmarshal(body, &parsed)) && assert.Len(t, parsed.Entries, 1) {
+		assert.Equal(t, "https://example.com/posts/1", parsed.Entries[0].ID)
+		assert.Equal(t, "html", parsed.Entries[0].Content.Type)
+		assert.Equal(t, testFeed.Items[0].ContentHTML, parsed.Entries[0].Content.Value)
+		assert.Equal(t, "Jane", parsed.Entries[0].Author)
+	}
+
+	// Empty feeds are still valid documents.
+	body, err = Atom(Feed{Title: "Empty", Self: "https://example.com/feeds/posts.atom"})
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(body), "<entry>")
+		assert.NotContains(t, string(body), "<author>")
+	}
+}
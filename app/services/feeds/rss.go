@@ -0,0 +1,85 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// rss is the root element of an RSS 2.0 document.
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Self          atomLink  `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Author      string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+	Content     cdata    `xml:"content:encoded"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// RSS renders a feed as an RSS 2.0 document, content is embedded with the content module, and authors with Dublin
+// Core, since RSS authors have to be email addresses.
+func RSS(feed Feed) ([]byte, error) {
+	document := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Self:          atomLink{Href: feed.Self, Rel: "self", Type: RSSContentType},
+			Description:   feed.Description,
+			Language:      feed.Language,
+			LastBuildDate: rssTime(feed.Updated),
+			Items:         make([]rssItem, 0, len(feed.Items)),
+		},
+	}
+	for _, item := range feed.Items {
+		document.Channel.Items = append(document.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			Author:      item.Author,
+			Categories:  item.Categories,
+			PubDate:     rssTime(item.Published),
+			Description: item.Summary,
+			Content:     cdata{Value: item.ContentHTML},
+		})
+	}
+	return marshal(document)
+}
+
+// rssTime formats a timestamp the way RSS expects, RFC 822 with a four digit year.
+func rssTime(t time.Time) string {
+	return t.UTC().Format(http.TimeFormat)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrInvalidKey is thrown when an idempotency key is empty or longer than 255 characters.
+	ErrInvalidKey = errors.New("idempotency key has to be a non-empty string of 255 characters or less")
+	// ErrKeyInProgress is thrown when a request is retried while the original one is still being served.
+	ErrKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrKeyReused is thrown when an idempotency key is reused for a different request.
+	ErrKeyReused = errors.New("idempotency key has already been used for a different request")
+)
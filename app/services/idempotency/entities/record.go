@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Record represents a request sent with an idempotency key, and the response it was first served with.
+// Keys are scoped to their owner, so that requests of different users never share a response.
+type Record struct {
+	Owner          string    `gorm:"column:owner; primary_key:yes"`
+	Key            string    `gorm:"column:key; primary_key:yes"`
+	Fingerprint    string    `gorm:"column:fingerprint"`
+	ResponseStatus int       `gorm:"column:response_status"`
+	ResponseHeader string    `gorm:"column:response_header"`
+	ResponseBody   []byte    `gorm:"column:response_body"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	ExpiresAt      time.Time `gorm:"column:expires_at"`
+}
+
+// TableName ...
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed checks whether the original request has already been served.
+func (r Record) Completed() bool {
+	return r.ResponseStatus != 0
+}
+
+// Header decodes stored response headers.
+func (r Record) Header() http.Header {
+	header := make(http.Header)
+	_ = json.Unmarshal([]byte(r.ResponseHeader), &header)
+	return header
+}
+
+// SetHeader encodes response headers to be stored.
+func (r *Record) SetHeader(header http.Header) {
+	marshalled, _ := json.Marshal(header)
+	r.ResponseHeader = string(marshalled)
+}
+
+// ValidateKey checks whether a given idempotency key is valid.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > 255 {
+		return ErrInvalidKey
+	}
+	return nil
+}
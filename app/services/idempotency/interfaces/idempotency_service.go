@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/entities"
+)
+
+// IdempotencyService is an interface that is used by outside packages to deduplicate retried requests.
+type IdempotencyService interface {
+	Begin(ctx context.Context, owner string, key string, fingerprint string) (entities.Record, error)
+	Complete(ctx context.Context, record entities.Record) error
+	Release(ctx context.Context, owner string, key string) error
+	Cleanup(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/interfaces"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verify that IdempotencyService satisfies the interfaces.IdempotencyService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.IdempotencyService = (*IdempotencyService)(nil)
+
+// IdempotencyService implements interfaces.IdempotencyService.
+type IdempotencyService struct {
+	writer *gorm.DB
+	ttl    time.Duration
+}
+
+// NewIdempotencyService instantiates a new IdempotencyService, keys are kept for ttl after first use.
+func NewIdempotencyService(ctx context.Context, writer *gorm.DB, ttl time.Duration) (*IdempotencyService, error) {
+	if writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	return &IdempotencyService{
+		writer: writer,
+		ttl:    ttl,
+	}, nil
+}
+
+// Begin claims an idempotency key of owner for a request with a given fingerprint, owners don't share keys.
+// Returns an empty record if the key has been claimed, and the stored record if the request has already been served.
+// Throws entities.ErrKeyInProgress if the original request is still being served, and entities.ErrKeyReused if the
+// key has been used for a request with a different fingerprint.
+func (s *IdempotencyService) Begin(ctx context.Context, owner string, key string, fingerprint string) (entities.Record, error) {
+	if err := entities.ValidateKey(key); err != nil {
+		return entities.Record{}, err
+	}
+	db := s.writer.WithContext(ctx)
+	now := time.Now().UTC()
+	// Expired keys are free to be claimed again.
+	err := db.Where("owner = ? AND key = ? AND expires_at <= ?", owner, key, now).Delete(&entities.Record{}).Error
+	if err != nil {
+		return entities.Record{}, err
+	}
+	claimed := entities.Record{Owner: owner, Key: key, Fingerprint: fingerprint, CreatedAt: now, ExpiresAt: now.Add(s.ttl)}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&claimed)
+	if result.Error != nil {
+		return entities.Record{}, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return entities.Record{}, nil
+	}
+
+	var stored entities.Record
+	if err = db.First(&stored, "owner = ? AND key = ?", owner, key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The key has expired and been cleaned up in the meantime.
+			return s.Begin(ctx, owner, key, fingerprint)
+		}
+		return entities.Record{}, err
+	}
+	if stored.Fingerprint != fingerprint {
+		return entities.Record{}, entities.ErrKeyReused
+	}
+	if !stored.Completed() {
+		return entities.Record{}, entities.ErrKeyInProgress
+	}
+	return stored, nil
+}
+
+// Complete stores the response a claimed key has been served with.
+func (s *IdempotencyService) Complete(ctx context.Context, record entities.Record) error {
+	// The owner of anonymous requests is empty, which gorm wouldn't match as a primary key of the model.
+	db := s.writer.WithContext(ctx).Model(&entities.Record{}).Where("owner = ? AND key = ?", record.Owner, record.Key)
+	return db.Updates(map[string]interface{}{
+		"response_status": record.ResponseStatus,
+		"response_header": record.ResponseHeader,
+		"response_body":   record.ResponseBody,
+	}).Error
+}
+
+// Release frees a claimed key of owner whose request hasn't been served, so that it can be retried.
+func (s *IdempotencyService) Release(ctx context.Context, owner string, key string) error {
+	return s.writer.WithContext(ctx).Where("owner = ? AND key = ? AND response_status = 0", owner, key).Delete(&entities.Record{}).Error
+}
+
+// Cleanup deletes expired keys, returning the number of deleted keys.
+func (s *IdempotencyService) Cleanup(ctx context.Context) (int64, error) {
+	result := s.writer.WithContext(ctx).Where("expires_at <= ?", time.Now().UTC()).Delete(&entities.Record{})
+	return result.RowsAffected, result.Error
+}
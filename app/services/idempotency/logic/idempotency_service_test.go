@@ -0,0 +1,219 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/idempotency/interfaces"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	idempotencyServiceTestInstance interfaces.IdempotencyService
+)
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewIdempotencyService(t *testing.T) {
+	ctx := context.Background()
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewIdempotencyService(ctx, writer, time.Hour)
+	assert.Nil(t, err)
+	_, err = NewIdempotencyService(ctx, nil, time.Hour)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, entities.ErrNilDB)
+	}
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	ctx := context.Background()
+	key := "test-key"
+
+	cases := []struct {
+		setup     func() error
+		base      func() (entities.Record, error)
+		assertion func(record entities.Record, err error)
+	}{
+		// New key.
+		{
+			setup: func() error { return nil },
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				if assert.NoError(t, err) {
+					assert.False(t, record.Completed())
+				}
+			},
+		},
+		// In progress.
+		{
+			setup: func() error {
+				_, err := idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+				return err
+			},
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				assert.ErrorIs(t, err, entities.ErrKeyInProgress)
+			},
+		},
+		// Completed.
+		{
+			setup: func() error {
+				_, err := idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+				if err != nil {
+					return err
+				}
+				return idempotencyServiceTestInstance.Complete(ctx, entities.Record{
+					Key:            key,
+					ResponseStatus: 201,
+					ResponseHeader: `{"Content-Type":["application/json"]}`,
+					ResponseBody:   []byte(`{"id":1}`),
+				})
+			},
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				if assert.NoError(t, err) {
+					assert.True(t, record.Completed())
+					assert.Equal(t, 201, record.ResponseStatus)
+					assert.Equal(t, "application/json", record.Header().Get("Content-Type"))
+					assert.Equal(t, `{"id":1}`, string(record.ResponseBody))
+				}
+			},
+		},
+		// Reused for a different request.
+		{
+			setup: func() error {
+				_, err := idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+				return err
+			},
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", key, "other-fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				assert.ErrorIs(t, err, entities.ErrKeyReused)
+			},
+		},
+		// Released.
+		{
+			setup: func() error {
+				_, err := idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+				if err != nil {
+					return err
+				}
+				return idempotencyServiceTestInstance.Release(ctx, "", key)
+			},
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", key, "other-fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				if assert.NoError(t, err) {
+					assert.False(t, record.Completed())
+				}
+			},
+		},
+		// Keys of other owners.
+		{
+			setup: func() error {
+				_, err := idempotencyServiceTestInstance.Begin(ctx, "", key, "fingerprint")
+				return err
+			},
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "user:1", key, "fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				if assert.NoError(t, err) {
+					assert.False(t, record.Completed())
+				}
+			},
+		},
+		// Invalid key.
+		{
+			setup: func() error { return nil },
+			base: func() (entities.Record, error) {
+				return idempotencyServiceTestInstance.Begin(ctx, "", "", "fingerprint")
+			},
+			assertion: func(record entities.Record, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidKey)
+			},
+		},
+	}
+	for _, c := range cases {
+		err := c.setup()
+		assert.Nil(t, err)
+		c.assertion(c.base())
+		err = cleanup(ctx, key)
+		assert.Nil(t, err)
+	}
+}
+
+func TestIdempotencyService_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expired, err := NewIdempotencyService(ctx, writer, -time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = expired.Begin(ctx, "", "test-expired-key", "fingerprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	deleted, err := idempotencyServiceTestInstance.Cleanup(ctx)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, deleted, int64(1))
+	}
+	// Expired keys can be claimed again even before they are cleaned up.
+	_, err = expired.Begin(ctx, "", "test-expired-key", "fingerprint")
+	assert.NoError(t, err)
+	_, err = expired.Begin(ctx, "", "test-expired-key", "other-fingerprint")
+	assert.NoError(t, err)
+	assert.Nil(t, cleanup(ctx, "test-expired-key"))
+}
+
+// cleanup deletes a key regardless of its state.
+func cleanup(ctx context.Context, key string) error {
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return err
+	}
+	return writer.Where("key = ?", key).Delete(&entities.Record{}).Error
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewIdempotencyService(ctx, writer, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyServiceTestInstance = service
+	return func(ctx context.Context) {}, nil
+}
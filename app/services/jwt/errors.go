@@ -0,0 +1,26 @@
+package jwt
+
+import (
+	"errors"
+)
+
+var (
+	// ErrMalformedToken is thrown when a token isn't a well-formed compact JWS.
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrUnsupportedAlgorithm is thrown when a token or key uses an algorithm other than HS256, RS256 or EdDSA.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	// ErrUnknownKey is thrown when no key matches the kid and algorithm of a token.
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrInvalidSignature is thrown when a token signature doesn't match its key.
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrTokenExpired is thrown when a token is used after its exp, or has none.
+	ErrTokenExpired = errors.New("token has expired")
+	// ErrTokenNotYetValid is thrown when a token is used before its nbf.
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	// ErrInvalidIssuer is thrown when a token is issued by an untrusted issuer.
+	ErrInvalidIssuer = errors.New("token issuer is not trusted")
+	// ErrInvalidAudience is thrown when a token is not meant for this audience.
+	ErrInvalidAudience = errors.New("token is not meant for this audience")
+	// ErrMalformedKey is thrown when a key or a JWKS document can't be parsed.
+	ErrMalformedKey = errors.New("malformed key")
+)
@@ -0,0 +1,254 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// Key is a key tokens are signed or verified with. HS256 keys carry Secret, RS256 and EdDSA keys carry Public, and
+// Private if they can sign.
+type Key struct {
+	ID        string
+	Algorithm string
+	Secret    []byte
+	Public    crypto.PublicKey
+	Private   crypto.Signer
+}
+
+// CanSign checks whether tokens can be signed with the key.
+func (k Key) CanSign() bool {
+	if k.Algorithm == HS256 {
+		return len(k.Secret) > 0
+	}
+	return k.Private != nil
+}
+
+// KeySet is a set of keys, looked up by kid so that keys can be rotated.
+type KeySet []Key
+
+// Find looks up a key by kid and algorithm. Tokens without kid are only accepted when the set has a single key of
+// their algorithm, so that the key they're meant for is unambiguous.
+func (s KeySet) Find(id string, algorithm string) (Key, error) {
+	var (
+		found Key
+		count int
+	)
+	for _, key := range s {
+		if key.Algorithm != algorithm {
+			continue
+		}
+		if id != "" && key.ID == id {
+			return key, nil
+		}
+		found = key
+		count++
+	}
+	if id == "" && count == 1 {
+		return found, nil
+	}
+	return Key{}, ErrUnknownKey
+}
+
+// jwk is a single JSON Web Key, see RFC 7517 and RFC 8037.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv"`
+	K         string `json:"k"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	D         string `json:"d"`
+	P         string `json:"p"`
+	Q         string `json:"q"`
+	X         string `json:"x"`
+}
+
+// ParseJWKS parses a JWKS document, supporting oct (HS256), RSA (RS256) and OKP Ed25519 (EdDSA) keys. Private key
+// parameters are kept, so that the set can sign as well. Encryption keys are skipped.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	keys := make(KeySet, 0, len(document.Keys))
+	for _, raw := range document.Keys {
+		if raw.Use == "enc" {
+			continue
+		}
+		key, err := raw.key()
+		if err != nil {
+			return nil, fmt.Errorf("%w: kid %q: %v", ErrMalformedKey, raw.ID, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// LoadJWKS reads and parses a JWKS file.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// key converts a JWK to a Key.
+func (j jwk) key() (Key, error) {
+	key := Key{ID: j.ID, Algorithm: j.Algorithm}
+	switch j.KeyType {
+	case "oct":
+		if key.Algorithm == "" {
+			key.Algorithm = HS256
+		}
+		secret, err := decodeSegment(j.K)
+		if err != nil || len(secret) == 0 {
+			return Key{}, fmt.Errorf("invalid k")
+		}
+		key.Secret = secret
+	case "RSA":
+		if key.Algorithm == "" {
+			key.Algorithm = RS256
+		}
+		n, err := decodeInt(j.N)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid n")
+		}
+		e, err := decodeInt(j.E)
+		if err != nil || !e.IsInt64() {
+			return Key{}, fmt.Errorf("invalid e")
+		}
+		public := &rsa.PublicKey{N: n, E: int(e.Int64())}
+		key.Public = public
+		if j.D == "" {
+			break
+		}
+		private := &rsa.PrivateKey{PublicKey: *public}
+		if private.D, err = decodeInt(j.D); err != nil {
+			return Key{}, fmt.Errorf("invalid d")
+		}
+		p, err := decodeInt(j.P)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid p")
+		}
+		q, err := decodeInt(j.Q)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid q")
+		}
+		private.Primes = []*big.Int{p, q}
+		if err = private.Validate(); err != nil {
+			return Key{}, err
+		}
+		private.Precompute()
+		key.Private = private
+	case "OKP":
+		if key.Algorithm == "" {
+			key.Algorithm = EdDSA
+		}
+		if j.Curve != "Ed25519" {
+			return Key{}, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := decodeSegment(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return Key{}, fmt.Errorf("invalid x")
+		}
+		key.Public = ed25519.PublicKey(x)
+		if j.D == "" {
+			break
+		}
+		d, err := decodeSegment(j.D)
+		if err != nil || len(d) != ed25519.SeedSize {
+			return Key{}, fmt.Errorf("invalid d")
+		}
+		key.Private = ed25519.NewKeyFromSeed(d)
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %q", j.KeyType)
+	}
+	if !compatible(key) {
+		return Key{}, fmt.Errorf("%v: %q for key type %q", ErrUnsupportedAlgorithm, key.Algorithm, j.KeyType)
+	}
+	return key, nil
+}
+
+// compatible checks that a key algorithm matches its key material.
+func compatible(key Key) bool {
+	switch key.Algorithm {
+	case HS256:
+		return len(key.Secret) > 0
+	case RS256:
+		_, ok := key.Public.(*rsa.PublicKey)
+		return ok
+	case EdDSA:
+		_, ok := key.Public.(ed25519.PublicKey)
+		return ok
+	}
+	return false
+}
+
+// decodeInt decodes a base64url encoded big-endian integer.
+func decodeInt(s string) (*big.Int, error) {
+	data, err := decodeSegment(s)
+	if err != nil || len(data) == 0 {
+		return nil, ErrMalformedKey
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// decodeSegment decodes a base64url segment without padding.
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// KeyStore holds keys that can be replaced while in use, so that rotated keys are picked up without a restart.
+type KeyStore struct {
+	mu      sync.RWMutex
+	keys    KeySet
+	signing string
+}
+
+// NewKeyStore instantiates a KeyStore, tokens are signed with the key with signing kid.
+func NewKeyStore(keys KeySet, signing string) *KeyStore {
+	return &KeyStore{keys: keys, signing: signing}
+}
+
+// Keys returns current keys.
+func (s *KeyStore) Keys() KeySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys
+}
+
+// SetKeys replaces current keys.
+func (s *KeyStore) SetKeys(keys KeySet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+// SigningKey returns the key tokens are signed with, throws ErrUnknownKey if it's missing or can't sign.
+func (s *KeyStore) SigningKey() (Key, error) {
+	for _, key := range s.Keys() {
+		if key.ID == s.signing && key.CanSign() {
+			return key, nil
+		}
+	}
+	return Key{}, ErrUnknownKey
+}
@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJWKS(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if !assert.NoError(t, err) {
+		return
+	}
+	public, signer, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	encodeInt := func(i *big.Int) string { return encodeSegment(i.Bytes()) }
+	document := fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hs", "k": %q},
+		{"kty": "RSA", "kid": "rs", "alg": "RS256", "n": %q, "e": %q, "d": %q, "p": %q, "q": %q},
+		{"kty": "RSA", "kid": "rs-public", "n": %q, "e": %q},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": %q, "d": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "", "e": ""}
+	]}`,
+		encodeSegment([]byte("test-secret")),
+		encodeInt(private.N), encodeInt(big.NewInt(int64(private.E))), encodeInt(private.D),
+		encodeInt(private.Primes[0]), encodeInt(private.Primes[1]),
+		encodeInt(private.N), encodeInt(big.NewInt(int64(private.E))),
+		encodeSegment(public), encodeSegment(signer.Seed()),
+	)
+	keys, err := ParseJWKS([]byte(document))
+	if !assert.NoError(t, err) || !assert.Len(t, keys, 4) {
+		return
+	}
+	assert.Equal(t, []string{HS256, RS256, RS256, EdDSA}, []string{keys[0].Algorithm, keys[1].Algorithm, keys[2].Algorithm, keys[3].Algorithm})
+	assert.True(t, keys[1].CanSign())
+	assert.False(t, keys[2].CanSign())
+
+	// Keys parsed from a JWKS verify each other's tokens.
+	now := time.Now()
+	claims := Claims{ExpiresAt: now.Add(time.Minute).Unix()}
+	for _, key := range []Key{keys[0], keys[1], keys[3]} {
+		token, err := Sign(claims, key)
+		if assert.NoError(t, err, key.ID) {
+			_, err = Parse(token, keys, Validation{}, now)
+			assert.NoError(t, err, key.ID)
+		}
+	}
+	// Public keys verify tokens of their private counterparts.
+	token, err := Sign(claims, keys[1])
+	if assert.NoError(t, err) {
+		_, err = Parse(token, KeySet{{ID: "rs", Algorithm: RS256, Public: keys[2].Public}}, Validation{}, now)
+		assert.NoError(t, err)
+	}
+
+	for _, malformed := range []string{
+		`not json`,
+		`{"keys": [{"kty": "EC", "kid": "ec"}]}`,
+		`{"keys": [{"kty": "oct", "kid": "hs", "k": ""}]}`,
+		`{"keys": [{"kty": "oct", "kid": "hs", "alg": "RS256", "k": "c2VjcmV0"}]}`,
+		`{"keys": [{"kty": "OKP", "kid": "ed", "crv": "X25519", "x": "c2VjcmV0"}]}`,
+	} {
+		_, err = ParseJWKS([]byte(malformed))
+		assert.ErrorIs(t, err, ErrMalformedKey, malformed)
+	}
+}
+
+func TestKeyStore(t *testing.T) {
+	store := NewKeyStore(KeySet{{ID: "old", Algorithm: HS256, Secret: []byte("old")}}, "new")
+	_, err := store.SigningKey()
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	store.SetKeys(KeySet{{ID: "old", Algorithm: HS256, Secret: []byte("old")}, {ID: "new", Algorithm: HS256, Secret: []byte("new")}})
+	key, err := store.SigningKey()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new", key.ID)
+	}
+	assert.Len(t, store.Keys(), 2)
+}
@@ -0,0 +1,212 @@
+// Package jwt signs and verifies JSON Web Tokens with HS256, RS256 and EdDSA keys, see RFC 7519.
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	helpers "github.com/samber/lo"
+)
+
+// Audience is the aud claim, serialized as a string when it has a single value.
+type Audience []string
+
+// MarshalJSON ...
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON ...
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains checks whether a given audience is among the values.
+func (a Audience) Contains(audience string) bool {
+	return helpers.Contains(a, audience)
+}
+
+// Claims are the registered claims of a token, along with the scope and token_use claims used by this API.
+// Parsed tokens also carry all of their claims in Raw.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+	Use       string   `json:"token_use,omitempty"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+// Validation configures which tokens are accepted.
+type Validation struct {
+	// Issuers are trusted issuers, any issuer is accepted if it's empty.
+	Issuers []string
+	// Audience has to be among the token audiences, unless it's empty.
+	Audience string
+	// Leeway makes up for clock skew between issuer and this API.
+	Leeway time.Duration
+}
+
+// header is a JOSE header of a token.
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Sign serializes claims into a token signed with key.
+func Sign(claims Claims, key Key) (string, error) {
+	if !key.CanSign() {
+		return "", ErrUnknownKey
+	}
+	head, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := encodeSegment(head) + "." + encodeSegment(payload)
+	signature, err := sign([]byte(input), key)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encodeSegment(signature), nil
+}
+
+// Parse verifies a token against keys and validation at a given time, returning its claims.
+func Parse(token string, keys KeySet, validation Validation, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformedToken
+	}
+	var head header
+	if err := decodeJSON(parts[0], &head); err != nil {
+		return Claims{}, err
+	}
+	switch head.Algorithm {
+	case HS256, RS256, EdDSA:
+	default:
+		return Claims{}, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, head.Algorithm)
+	}
+	// The key has to be meant for the token algorithm, so that e.g. a public RSA key is never used as an HMAC secret.
+	key, err := keys.Find(head.KeyID, head.Algorithm)
+	if err != nil {
+		return Claims{}, err
+	}
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	if !verify([]byte(parts[0]+"."+parts[1]), signature, key) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	var claims Claims
+	if err = decodeJSON(parts[1], &claims); err != nil {
+		return Claims{}, err
+	}
+	if err = decodeJSON(parts[1], &claims.Raw); err != nil {
+		return Claims{}, err
+	}
+	return claims, validation.check(claims, now)
+}
+
+// check validates time, issuer and audience claims.
+func (v Validation) check(claims Claims, now time.Time) error {
+	if claims.ExpiresAt == 0 || !now.Before(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if len(v.Issuers) > 0 && !helpers.Contains(v.Issuers, claims.Issuer) {
+		return ErrInvalidIssuer
+	}
+	if v.Audience != "" && !claims.Audience.Contains(v.Audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// sign signs input with key.
+func sign(input []byte, key Key) ([]byte, error) {
+	switch key.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case RS256:
+		digest := sha256.Sum256(input)
+		return key.Private.Sign(rand.Reader, digest[:], crypto.SHA256)
+	case EdDSA:
+		return key.Private.Sign(rand.Reader, input, crypto.Hash(0))
+	}
+	return nil, ErrUnsupportedAlgorithm
+}
+
+// verify checks signature of input with key.
+func verify(input []byte, signature []byte, key Key) bool {
+	switch key.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(input)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case RS256:
+		public, ok := key.Public.(*rsa.PublicKey)
+		digest := sha256.Sum256(input)
+		return ok && rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature) == nil
+	case EdDSA:
+		public, ok := key.Public.(ed25519.PublicKey)
+		return ok && ed25519.Verify(public, input, signature)
+	}
+	return false
+}
+
+// encodeSegment encodes a base64url segment without padding.
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeJSON decodes a base64url encoded JSON segment, numbers are kept as json.Number.
+func decodeJSON(segment string, target interface{}) error {
+	data, err := decodeSegment(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedToken, err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testKeys returns a signing key of every supported algorithm.
+func testKeys(t *testing.T) KeySet {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, signer, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return KeySet{
+		{ID: "hs", Algorithm: HS256, Secret: []byte("test-secret")},
+		{ID: "rs", Algorithm: RS256, Public: &private.PublicKey, Private: private},
+		{ID: "ed", Algorithm: EdDSA, Public: public, Private: signer},
+	}
+}
+
+func TestSignParse(t *testing.T) {
+	keys := testKeys(t)
+	now := time.Unix(1700000000, 0)
+	claims := Claims{Issuer: "test-issuer", Subject: "1", Audience: Audience{"test-audience"}, ExpiresAt: now.Add(time.Minute).Unix()}
+	validation := Validation{Issuers: []string{"test-issuer"}, Audience: "test-audience"}
+
+	for _, key := range keys {
+		token, err := Sign(claims, key)
+		if !assert.NoError(t, err, key.Algorithm) {
+			continue
+		}
+		parsed, err := Parse(token, keys, validation, now)
+		if assert.NoError(t, err, key.Algorithm) {
+			assert.Equal(t, "1", parsed.Subject)
+			assert.Equal(t, "test-issuer", parsed.Raw["iss"])
+			assert.Equal(t, json.Number("1700000060"), parsed.Raw["exp"])
+		}
+		// Tampered payload.
+		parts := strings.Split(token, ".")
+		forged, _ := json.Marshal(Claims{Subject: "2", ExpiresAt: claims.ExpiresAt})
+		_, err = Parse(parts[0]+"."+encodeSegment(forged)+"."+parts[2], keys, validation, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature, key.Algorithm)
+	}
+}
+
+func TestParse(t *testing.T) {
+	keys := testKeys(t)
+	now := time.Unix(1700000000, 0)
+	valid := Claims{Issuer: "test-issuer", Audience: Audience{"other", "test-audience"}, ExpiresAt: now.Add(time.Minute).Unix()}
+	validation := Validation{Issuers: []string{"test-issuer"}, Audience: "test-audience", Leeway: 10 * time.Second}
+
+	cases := []struct {
+		token     func() string
+		assertion func(err error)
+	}{
+		// Valid.
+		{
+			token: func() string { return mustSign(t, valid, keys[0]) },
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		// Expired, beyond leeway.
+		{
+			token: func() string {
+				claims := valid
+				claims.ExpiresAt = now.Add(-11 * time.Second).Unix()
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrTokenExpired)
+			},
+		},
+		// Expired, within leeway.
+		{
+			token: func() string {
+				claims := valid
+				claims.ExpiresAt = now.Add(-9 * time.Second).Unix()
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		// No expiry.
+		{
+			token: func() string {
+				claims := valid
+				claims.ExpiresAt = 0
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrTokenExpired)
+			},
+		},
+		// Not valid yet.
+		{
+			token: func() string {
+				claims := valid
+				claims.NotBefore = now.Add(time.Minute).Unix()
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrTokenNotYetValid)
+			},
+		},
+		// Untrusted issuer.
+		{
+			token: func() string {
+				claims := valid
+				claims.Issuer = "other-issuer"
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrInvalidIssuer)
+			},
+		},
+		// Other audience.
+		{
+			token: func() string {
+				claims := valid
+				claims.Audience = Audience{"other"}
+				return mustSign(t, claims, keys[0])
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrInvalidAudience)
+			},
+		},
+		// Unknown kid.
+		{
+			token: func() string {
+				key := keys[0]
+				key.ID = "unknown"
+				return mustSign(t, valid, key)
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrUnknownKey)
+			},
+		},
+		// Unsigned.
+		{
+			token: func() string {
+				head, _ := json.Marshal(header{Algorithm: "none"})
+				payload, _ := json.Marshal(valid)
+				return encodeSegment(head) + "." + encodeSegment(payload) + "."
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+			},
+		},
+		// HMAC signed with the kid of an RSA key.
+		{
+			token: func() string {
+				return mustSign(t, valid, Key{ID: "rs", Algorithm: HS256, Secret: []byte("test-secret")})
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrUnknownKey)
+			},
+		},
+		// Malformed.
+		{
+			token: func() string { return "test-token" },
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, ErrMalformedToken)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.token(), keys, validation, now)
+		c.assertion(err)
+	}
+}
+
+func TestKeySet_Find(t *testing.T) {
+	keys := KeySet{
+		{ID: "old", Algorithm: HS256, Secret: []byte("old")},
+		{ID: "new", Algorithm: HS256, Secret: []byte("new")},
+		{ID: "ed", Algorithm: EdDSA},
+	}
+	key, err := keys.Find("old", HS256)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("old"), key.Secret)
+	}
+	_, err = keys.Find("old", EdDSA)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	// Without kid, only unambiguous keys are found.
+	_, err = keys.Find("", HS256)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	key, err = keys.Find("", EdDSA)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ed", key.ID)
+	}
+}
+
+// mustSign signs claims, failing the test on error.
+func mustSign(t *testing.T, claims Claims, key Key) string {
+	token, err := Sign(claims, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
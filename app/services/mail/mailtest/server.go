@@ -0,0 +1,172 @@
+// Package mailtest provides a fake SMTP server, recording messages instead of delivering them.
+package mailtest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Message is a message received by Server.
+type Message struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Server is a fake SMTP server speaking just enough of the protocol for net/smtp clients.
+type Server struct {
+	// Addr is the address the server listens on.
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+	messages []Message
+	rejected map[string]int
+}
+
+// NewServer starts a server listening on addr, use "127.0.0.1:0" to pick a free port.
+func NewServer(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{Addr: listener.Addr().String(), listener: listener, rejected: make(map[string]int)}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Reject makes the server reject address as a recipient with code, like 550 for an unknown mailbox.
+func (s *Server) Reject(address string, code int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rejected[strings.ToLower(address)] = code
+}
+
+// Messages returns messages received so far.
+func (s *Server) Messages() []Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Reset forgets received messages and rejected recipients.
+func (s *Server) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.messages = nil
+	s.rejected = make(map[string]int)
+}
+
+// Close stops the server, waiting for open connections to finish.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle serves a single connection until the client quits.
+func (s *Server) handle(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	var current Message
+	reply("220 mailtest ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			reply("250-mailtest")
+			reply("250 8BITMIME")
+		case "HELO":
+			reply("250 mailtest")
+		case "MAIL":
+			current = Message{From: address(arg)}
+			reply("250 OK")
+		case "RCPT":
+			to := address(arg)
+			s.mutex.Lock()
+			code, rejected := s.rejected[strings.ToLower(to)]
+			s.mutex.Unlock()
+			if rejected {
+				reply("%d mailbox unavailable", code)
+				continue
+			}
+			current.To = append(current.To, to)
+			reply("250 OK")
+		case "DATA":
+			if len(current.To) == 0 {
+				reply("503 no valid recipients")
+				continue
+			}
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := readData(reader)
+			if err != nil {
+				return
+			}
+			current.Data = data
+			s.mutex.Lock()
+			s.messages = append(s.messages, current)
+			s.mutex.Unlock()
+			current = Message{}
+			reply("250 OK")
+		case "RSET":
+			current = Message{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+// address extracts an address out of a FROM:<address> or TO:<address> argument.
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " ")
+	return strings.Trim(value, "<>")
+}
+
+// readData reads a message up to the terminating dot line, removing dot-stuffing.
+func readData(reader *bufio.Reader) (string, error) {
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return data.String(), nil
+		}
+		data.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// messageTimeout bounds the time a single message takes to be sent.
+const messageTimeout = 30 * time.Second
+
+// Sender sends emails.
+type Sender interface {
+	// Send sends a batch of messages, returning an error per message in the same order.
+	Send(ctx context.Context, messages []Message) []error
+}
+
+// Verify that SMTPSender satisfies the Sender interface.
+// This should throw a compilation error otherwise.
+var _ Sender = (*SMTPSender)(nil)
+
+// SMTPSender sends emails through an SMTP server, a batch of messages shares a single connection.
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+}
+
+// NewSMTPSender instantiates a new SMTPSender for a server at addr, credentials are optional.
+// The connection is upgraded with STARTTLS whenever the server supports it.
+func NewSMTPSender(addr string, username string, password string) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	sender := &SMTPSender{addr: addr, host: host}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender, nil
+}
+
+// Send implements Sender, if the connection can't be established all messages fail with the same error.
+func (s *SMTPSender) Send(ctx context.Context, messages []Message) []error {
+	errs := make([]error, len(messages))
+	client, conn, err := s.dial(ctx)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer client.Close()
+	for i, message := range messages {
+		_ = conn.SetDeadline(time.Now().Add(messageTimeout))
+		if errs[i] = s.send(client, message); errs[i] != nil {
+			// Clear the failed transaction, so that the next message starts fresh.
+			_ = client.Reset()
+		}
+	}
+	_ = client.Quit()
+	return errs
+}
+
+// dial connects and authenticates to the server.
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(messageTimeout))
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && s.auth != nil {
+		if err = client.Auth(s.auth); err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+	}
+	return client, conn, nil
+}
+
+// send sends a single message over an established connection.
+func (s *SMTPSender) send(client *smtp.Client, message Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+	body, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// IsPermanent checks whether a message was rejected for good, like for an unknown recipient, retrying it won't help.
+func IsPermanent(err error) bool {
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail/mailtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPSender_Send(t *testing.T) {
+	server, err := mailtest.NewServer("127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer server.Close()
+	server.Reject("bounced@example.com", 550)
+	server.Reject("busy@example.com", 451)
+
+	sender, err := NewSMTPSender(server.Addr, "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	message := func(to string) Message {
+		return Message{
+			From:    "Newsletter <newsletter@example.com>",
+			To:      to,
+			Subject: "Hello, wörld",
+			Text:    "Hello\n.\nbye",
+			HTML:    "<p>Hello</p>",
+			Header:  map[string]string{"list-unsubscribe": "<https://example.com/unsubscribe>"},
+		}
+	}
+	errs := sender.Send(context.Background(), []Message{
+		message("first@example.com"),
+		message("bounced@example.com"),
+		message("busy@example.com"),
+		message("not an address"),
+		message("Second <second@example.com>"),
+	})
+	if !assert.Len(t, errs, 5) {
+		return
+	}
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+	assert.True(t, IsPermanent(errs[1]))
+	assert.Error(t, errs[2])
+	assert.False(t, IsPermanent(errs[2]))
+	assert.Error(t, errs[3])
+	assert.NoError(t, errs[4])
+
+	messages := server.Messages()
+	if !assert.Len(t, messages, 2) {
+		return
+	}
+	assert.Equal(t, "newsletter@example.com", messages[0].From)
+	assert.Equal(t, []string{"first@example.com"}, messages[0].To)
+	assert.Equal(t, []string{"second@example.com"}, messages[1].To)
+	for _, part := range []string{
+		"Subject: =?utf-8?q?Hello,_w=C3=B6rld?=\r\n",
+		"List-Unsubscribe: <https://example.com/unsubscribe>\r\n",
+		"Content-Type: multipart/alternative;",
+		"Content-Type: text/plain; charset=utf-8",
+		"Hello\r\n.\r\nbye",
+		"<p>Hello</p>",
+	} {
+		assert.True(t, strings.Contains(messages[0].Data, part), part)
+	}
+}
+
+func TestSMTPSender_Unreachable(t *testing.T) {
+	server, err := mailtest.NewServer("127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, server.Close())
+
+	sender, err := NewSMTPSender(server.Addr, "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	errs := sender.Send(context.Background(), []Message{{To: "first@example.com"}, {To: "second@example.com"}})
+	if assert.Len(t, errs, 2) {
+		assert.Error(t, errs[0])
+		assert.False(t, IsPermanent(errs[0]))
+		assert.Equal(t, errs[0], errs[1])
+	}
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	message := Message{
+		From:    "newsletter@example.com",
+		To:      "reader@example.com",
+		Subject: "Injected\r\nBcc: victim@example.com",
+	}
+	data, err := message.Bytes()
+	if assert.NoError(t, err) {
+		assert.False(t, strings.Contains(string(data), "\r\nBcc:"))
+		assert.True(t, strings.Contains(string(data), "Message-ID: <"))
+		assert.True(t, strings.Contains(string(data), "@example.com>\r\n"))
+	}
+}
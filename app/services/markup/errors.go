@@ -0,0 +1,10 @@
+package markup
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnknownFormat is thrown when content is rendered from a format that is not supported.
+	ErrUnknownFormat = errors.New("content format has to be one of markdown, plain or html")
+)
@@ -0,0 +1,94 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Format is a markup language content is written in.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatPlain    Format = "plain"
+	FormatHTML     Format = "html"
+)
+
+const (
+	// maxExcerptLength bounds excerpts, in characters.
+	maxExcerptLength = 280
+	// wordsPerMinute is the reading speed reading time is estimated with.
+	wordsPerMinute = 200
+)
+
+var (
+	// paragraphBreak splits plain text into paragraphs.
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+	// blockTag matches a tag of sanitized HTML that separates words, attribute values can't contain angle brackets.
+	blockTag = regexp.MustCompile(`</?(p|br|hr|h[1-6]|blockquote|pre|ul|ol|li|table|thead|tbody|tr|th|td)\b[^>]*>`)
+	// inlineTag matches any other tag of sanitized HTML.
+	inlineTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Valid checks whether content can be rendered from f.
+func (f Format) Valid() bool {
+	return f == FormatMarkdown || f == FormatPlain || f == FormatHTML
+}
+
+// Render renders source written in format to sanitized HTML, throws ErrUnknownFormat if format is not supported.
+func Render(format Format, source string) (string, error) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	switch format {
+	case FormatMarkdown:
+		return Sanitize(renderMarkdown(source)), nil
+	case FormatPlain:
+		return Sanitize(renderPlain(source)), nil
+	case FormatHTML:
+		return Sanitize(source), nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Excerpt returns the beginning of text content of rendered HTML, cut at a word boundary.
+func Excerpt(rendered string) string {
+	text := []rune(plainText(rendered))
+	if len(text) <= maxExcerptLength {
+		return string(text)
+	}
+	cut := maxExcerptLength
+	for i := cut; i > 0; i-- {
+		if text[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(text[:cut]), " ,.;:") + "…"
+}
+
+// ReadingTime estimates how many minutes it takes to read rendered HTML, rounding up.
+func ReadingTime(rendered string) int {
+	words := len(strings.Fields(plainText(rendered)))
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// renderPlain renders plain text, blank lines separate paragraphs and line breaks are kept.
+func renderPlain(source string) string {
+	var builder strings.Builder
+	for _, paragraph := range paragraphBreak.Split(source, -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		builder.WriteString("<p>")
+		builder.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		builder.WriteString("</p>\n")
+	}
+	return builder.String()
+}
+
+// plainText extracts text content of sanitized HTML, collapsing whitespace.
+func plainText(rendered string) string {
+	text := inlineTag.ReplaceAllString(blockTag.ReplaceAllString(rendered, " "), "")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
@@ -0,0 +1,92 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		format    Format
+		source    string
+		assertion func(string, error)
+	}{
+		// Markdown.
+		{
+			format: FormatMarkdown,
+			source: "# Title\r\n\r\n[x](javascript:alert(1))",
+			assertion: func(rendered string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "<h1>Title</h1>\n<p><a rel=\"nofollow noopener\">x</a></p>\n", rendered)
+			},
+		},
+		// Plain text.
+		{
+			format: FormatPlain,
+			source: "first <b>\nsecond\n\n\nthird",
+			assertion: func(rendered string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "<p>first &lt;b&gt;<br>\nsecond</p>\n<p>third</p>\n", rendered)
+			},
+		},
+		// HTML.
+		{
+			format: FormatHTML,
+			source: "<p>text<script>alert(1)</script></p>",
+			assertion: func(rendered string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "<p>text</p>", rendered)
+			},
+		},
+		// Unknown format.
+		{
+			format: "rst",
+			source: "text",
+			assertion: func(_ string, err error) {
+				assert.ErrorIs(t, err, ErrUnknownFormat)
+			},
+		},
+	}
+	for _, c := range cases {
+		c.assertion(Render(c.format, c.source))
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, "Title First paragraph with bold text.", Excerpt("<h1>Title</h1>\n<p>First paragraph with <strong>bo</strong>ld text.</p>"))
+	assert.Equal(t, "Tom & Jerry", Excerpt("<p>Tom &amp; Jerry</p>"))
+	long := Excerpt("<p>" + strings.Repeat("word, ", 100) + "</p>")
+	assert.True(t, strings.HasSuffix(long, "word…"))
+	assert.LessOrEqual(t, len([]rune(long)), maxExcerptLength+1)
+}
+
+func TestReadingTime(t *testing.T) {
+	assert.Equal(t, 0, ReadingTime(""))
+	assert.Equal(t, 1, ReadingTime("<p>a few words</p>"))
+	assert.Equal(t, 2, ReadingTime("<p>"+strings.Repeat("word ", wordsPerMinute+1)+"</p>"))
+}
+
+func FuzzRender(f *testing.F) {
+	seeds := []string{
+		"# Title\r\n\r\n[x](javascript:alert(1))",
+		"first <b>\nsecond\n\n\nthird",
+		"> quote\n> - item\n>   1. nested\n\n```go\ncode\n```\n",
+		"*em* **strong** ***both*** _a_ `code` ~~del~~ ![img](/a.png \"title\") <a href=x>raw</a>",
+		"- a\n- b\n\n  c\n\n---\n\tindented\n[link](</url with space> 'title')",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		for _, format := range []Format{FormatMarkdown, FormatPlain, FormatHTML} {
+			rendered, err := Render(format, source)
+			if assert.NoError(t, err) {
+				assertSanitized(t, rendered)
+				Excerpt(rendered)
+				ReadingTime(rendered)
+			}
+		}
+	})
+}
@@ -0,0 +1,562 @@
+package markup
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxNesting bounds how deep block quotes and lists nest, deeper markers are text, so that rendering stays linear.
+const maxNesting = 16
+
+var (
+	// autolink matches an absolute URI between angle brackets.
+	autolink = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*$`)
+	// email matches an email address between angle brackets.
+	email = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+	// entity matches a character reference, which is kept as is.
+	entity = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// listItem describes a list item marker.
+type listItem struct {
+	ordered bool
+	start   int
+	// width is the indentation of item content, continuation lines indented by at least as much belong to the item.
+	width int
+}
+
+// renderMarkdown renders a subset of CommonMark to HTML: ATX headings, paragraphs with hard line breaks, fenced code
+// blocks, block quotes, nested lists and thematic breaks, along with code spans, emphasis, strikethrough, links, images
+// and autolinks. Raw HTML isn't passed through, it's escaped like any other text.
+func renderMarkdown(source string) string {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	var builder strings.Builder
+	renderBlocks(&builder, lines, false, 0)
+	return builder.String()
+}
+
+// renderBlocks renders a sequence of block elements nested in depth block quotes and lists, paragraphs aren't wrapped
+// in <p> in tight list items.
+func renderBlocks(builder *strings.Builder, lines []string, tight bool, depth int) {
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			i++
+			continue
+		}
+		if fence := fenceOf(trimmed); fence != "" {
+			i = renderFence(builder, lines, i, fence)
+			continue
+		}
+		if level, text := headingOf(trimmed); level > 0 {
+			builder.WriteString(fmt.Sprintf("<h%v>%v</h%v>\n", level, renderInline(text), level))
+			i++
+			continue
+		}
+		if isThematicBreak(trimmed) {
+			builder.WriteString("<hr>\n")
+			i++
+			continue
+		}
+		if depth >= maxNesting {
+			i = renderParagraph(builder, lines, i, tight)
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			i = renderQuote(builder, lines, i, depth)
+			continue
+		}
+		if item, ok := listItemOf(lines[i]); ok {
+			i = renderList(builder, lines, i, item, depth)
+			continue
+		}
+		i = renderParagraph(builder, lines, i, tight)
+	}
+}
+
+// renderFence renders a fenced code block starting at lines[start], returns the index of the first line after it.
+func renderFence(builder *strings.Builder, lines []string, start int, fence string) int {
+	indent := indentOf(lines[start])
+	info := strings.Fields(strings.TrimLeft(strings.TrimSpace(lines[start]), fence[:1]))
+	code := make([]string, 0)
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		strip := indentOf(lines[i])
+		if strip > indent {
+			strip = indent
+		}
+		code = append(code, lines[i][strip:])
+	}
+	builder.WriteString("<pre><code")
+	if len(info) > 0 {
+		builder.WriteString(fmt.Sprintf(" class=\"language-%v\"", html.EscapeString(info[0])))
+	}
+	builder.WriteString(">")
+	for _, line := range code {
+		builder.WriteString(html.EscapeString(line) + "\n")
+	}
+	builder.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderQuote renders a block quote starting at lines[start], returns the index of the first line after it.
+func renderQuote(builder *strings.Builder, lines []string, start int, depth int) int {
+	quoted := make([]string, 0)
+	i := start
+	for ; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(line, ">") {
+			break
+		}
+		quoted = append(quoted, strings.TrimPrefix(line[1:], " "))
+	}
+	builder.WriteString("<blockquote>\n")
+	renderBlocks(builder, quoted, false, depth+1)
+	builder.WriteString("</blockquote>\n")
+	return i
+}
+
+// renderList renders a list starting at lines[start], returns the index of the first line after it.
+// Lists with blank lines between or inside their items are loose, and their paragraphs are wrapped in <p>.
+func renderList(builder *strings.Builder, lines []string, start int, first listItem, depth int) int {
+	items := make([][]string, 0)
+	loose := false
+	i := start
+	for i < len(lines) {
+		item, ok := listItemOf(lines[i])
+		if !ok || item.ordered != first.ordered || isThematicBreak(strings.TrimSpace(lines[i])) {
+			break
+		}
+		content := []string{lines[i][item.width:]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				next := nextNonBlank(lines, i)
+				if next == len(lines) || indentOf(lines[next]) < item.width {
+					break
+				}
+				loose = true
+				content = append(content, "")
+				continue
+			}
+			if indentOf(line) >= item.width {
+				content = append(content, line[item.width:])
+				continue
+			}
+			if _, ok := listItemOf(line); ok || interrupts(line) || strings.TrimSpace(content[len(content)-1]) == "" {
+				break
+			}
+			// Lazy continuation of the item's paragraph.
+			content = append(content, strings.TrimSpace(line))
+		}
+		items = append(items, content)
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			next := nextNonBlank(lines, i)
+			if sibling, ok := listItemOf(safeLine(lines, next)); !ok || sibling.ordered != first.ordered {
+				break
+			}
+			loose = true
+			i = next
+		}
+	}
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	if first.ordered && first.start != 1 {
+		builder.WriteString(fmt.Sprintf("<ol start=\"%v\">\n", first.start))
+	} else {
+		builder.WriteString(fmt.Sprintf("<%v>\n", tag))
+	}
+	for _, content := range items {
+		builder.WriteString("<li>")
+		renderBlocks(builder, content, !loose, depth+1)
+		builder.WriteString("</li>\n")
+	}
+	builder.WriteString(fmt.Sprintf("</%v>\n", tag))
+	return i
+}
+
+// renderParagraph renders a paragraph starting at lines[start], returns the index of the first line after it.
+func renderParagraph(builder *strings.Builder, lines []string, start int, tight bool) int {
+	text := make([]string, 0)
+	i := start
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || (i > start && interrupts(lines[i])) {
+			break
+		}
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+	content := renderInline(strings.TrimRight(strings.Join(text, "\n"), " "))
+	if tight {
+		builder.WriteString(content)
+	} else {
+		builder.WriteString("<p>" + content + "</p>\n")
+	}
+	return i
+}
+
+// renderInline renders inline elements of a paragraph or a heading.
+func renderInline(text string) string {
+	var builder strings.Builder
+	unclosed := make(map[string]bool)
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			builder.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(text) && isPunctuation(text[i+1]):
+			builder.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			n := runOf(text, i)
+			end := closingRun(text, i+n, n)
+			if end < 0 {
+				builder.WriteString(text[i : i+n])
+				i += n
+				continue
+			}
+			code := strings.ReplaceAll(text[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			builder.WriteString("<code>" + html.EscapeString(code) + "</code>")
+			i = end + n
+		case c == '!' && i+1 < len(text) && text[i+1] == '[':
+			label, destination, title, end, ok := parseLink(text, i+1)
+			if !ok {
+				builder.WriteString("!")
+				i++
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("<img src=\"%v\" alt=\"%v\"%v>",
+				html.EscapeString(destination), html.EscapeString(plainText(renderInline(label))), titleAttribute(title)))
+			i = end
+		case c == '[':
+			label, destination, title, end, ok := parseLink(text, i)
+			if !ok {
+				builder.WriteString("[")
+				i++
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("<a href=\"%v\"%v>%v</a>",
+				html.EscapeString(destination), titleAttribute(title), renderInline(label)))
+			i = end
+		case c == '<':
+			end := strings.IndexByte(text[i:], '>')
+			target := ""
+			if end > 0 {
+				target = text[i+1 : i+end]
+			}
+			switch {
+			case autolink.MatchString(target):
+				builder.WriteString(fmt.Sprintf("<a href=\"%v\">%v</a>", html.EscapeString(target), html.EscapeString(target)))
+				i += end + 1
+			case email.MatchString(target):
+				builder.WriteString(fmt.Sprintf("<a href=\"mailto:%v\">%v</a>", html.EscapeString(target), html.EscapeString(target)))
+				i += end + 1
+			default:
+				builder.WriteString("&lt;")
+				i++
+			}
+		case c == '*' || c == '_' || c == '~':
+			rendered, end, ok := renderEmphasis(text, i, unclosed)
+			if !ok {
+				n := runOf(text, i)
+				builder.WriteString(text[i : i+n])
+				i += n
+				continue
+			}
+			builder.WriteString(rendered)
+			i = end
+		case c == '&':
+			if match := entity.FindString(text[i:]); match != "" {
+				builder.WriteString(match)
+				i += len(match)
+				continue
+			}
+			builder.WriteString("&amp;")
+			i++
+		case c == '\n':
+			// Two trailing spaces make a hard line break.
+			if strings.HasSuffix(text[:i], "  ") {
+				builder.WriteString("<br>")
+			}
+			builder.WriteString("\n")
+			i++
+		default:
+			end := i + 1
+			for end < len(text) && !strings.ContainsRune("\\`![<*_~&\n", rune(text[end])) {
+				end++
+			}
+			builder.WriteString(html.EscapeString(text[i:end]))
+			i = end
+		}
+	}
+	return builder.String()
+}
+
+// renderEmphasis renders emphasis, strong emphasis or strikethrough opened by the delimiter run at text[start], returns
+// the position right after the closing delimiter, or false if the run doesn't open one. Delimiters without a closing
+// one further in text are added to unclosed, so that they aren't looked for again.
+func renderEmphasis(text string, start int, unclosed map[string]bool) (string, int, bool) {
+	c := text[start]
+	n := runOf(text, start)
+	var open, close string
+	switch {
+	case c == '~' && n == 2:
+		open, close = "<del>", "</del>"
+	case c != '~' && n == 1:
+		open, close = "<em>", "</em>"
+	case c != '~' && n == 2:
+		open, close = "<strong>", "</strong>"
+	case c != '~' && n == 3:
+		open, close = "<em><strong>", "</strong></em>"
+	default:
+		return "", start, false
+	}
+	inner := start + n
+	if inner >= len(text) || isWhitespace(text[inner]) || (c == '_' && start > 0 && isAlphanumeric(text[start-1])) {
+		return "", start, false
+	}
+	delimiter := text[start:inner]
+	if unclosed[delimiter] {
+		return "", start, false
+	}
+	for j := inner + 1; j+n <= len(text); j++ {
+		if text[j:j+n] != delimiter || isWhitespace(text[j-1]) || text[j-1] == c || text[j-1] == '\\' {
+			continue
+		}
+		if j+n < len(text) && (text[j+n] == c || (c == '_' && isAlphanumeric(text[j+n]))) {
+			continue
+		}
+		return open + renderInline(text[inner:j]) + close, j + n, true
+	}
+	unclosed[delimiter] = true
+	return "", start, false
+}
+
+// parseLink parses a [label](destination "title") link starting at text[open], returns the position right after it.
+func parseLink(text string, open int) (string, string, string, int, bool) {
+	depth := 0
+	closing := -1
+	for j := open; j < len(text) && closing < 0; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = j
+			}
+		}
+	}
+	if closing < 0 || closing+1 >= len(text) || text[closing+1] != '(' {
+		return "", "", "", open, false
+	}
+	// Destinations can contain balanced parentheses.
+	end := -1
+	depth = 1
+	for j := closing + 2; j < len(text) && end < 0; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = j - closing - 2
+			}
+		}
+	}
+	if end < 0 {
+		return "", "", "", open, false
+	}
+	inner := strings.TrimSpace(text[closing+2 : closing+2+end])
+	destination, title := inner, ""
+	if k := strings.IndexAny(inner, " \n"); k >= 0 {
+		destination, title = inner[:k], strings.TrimSpace(inner[k:])
+		if len(title) < 2 || (title[0] != '"' && title[0] != '\'') || title[len(title)-1] != title[0] {
+			return "", "", "", open, false
+		}
+		title = title[1 : len(title)-1]
+	}
+	destination = strings.TrimSuffix(strings.TrimPrefix(destination, "<"), ">")
+	return text[open+1 : closing], destination, title, closing + 2 + end + 1, true
+}
+
+// titleAttribute renders an optional title attribute of a link or an image.
+func titleAttribute(title string) string {
+	if title == "" {
+		return ""
+	}
+	return fmt.Sprintf(" title=\"%v\"", html.EscapeString(title))
+}
+
+// fenceOf returns the fence opening a fenced code block, or an empty string if trimmed line doesn't open one.
+func fenceOf(trimmed string) string {
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == c {
+			n++
+		}
+		if n < 3 {
+			continue
+		}
+		// Backtick fences can't have backticks in their info string.
+		if c == '`' && strings.Contains(trimmed[n:], "`") {
+			return ""
+		}
+		return trimmed[:n]
+	}
+	return ""
+}
+
+// headingOf returns the level and text of an ATX heading, level is zero if trimmed line isn't a heading.
+func headingOf(trimmed string) (int, string) {
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ') {
+		return 0, ""
+	}
+	text := strings.TrimSpace(trimmed[level:])
+	// The optional closing sequence has to be separated by a space.
+	if closed := strings.TrimRight(text, "#"); closed == "" || strings.HasSuffix(closed, " ") {
+		text = strings.TrimSpace(closed)
+	}
+	return level, text
+}
+
+// isThematicBreak checks whether trimmed line is a thematic break.
+func isThematicBreak(trimmed string) bool {
+	if trimmed == "" || !strings.ContainsRune("-*_", rune(trimmed[0])) {
+		return false
+	}
+	compact := strings.ReplaceAll(trimmed, " ", "")
+	return len(compact) >= 3 && strings.Trim(compact, trimmed[:1]) == ""
+}
+
+// listItemOf parses a list item marker at the beginning of a line.
+func listItemOf(line string) (listItem, bool) {
+	indent := indentOf(line)
+	rest := line[indent:]
+	var item listItem
+	marker := 0
+	if len(rest) > 0 && strings.ContainsRune("-*+", rune(rest[0])) {
+		marker = 1
+	} else {
+		for marker < len(rest) && marker < 9 && isDigit(rest[marker]) {
+			marker++
+		}
+		if marker == 0 || marker == len(rest) || (rest[marker] != '.' && rest[marker] != ')') {
+			return listItem{}, false
+		}
+		item.ordered = true
+		item.start, _ = strconv.Atoi(rest[:marker])
+		marker++
+	}
+	spaces := indentOf(rest[marker:])
+	if spaces == 0 || spaces == len(rest[marker:]) {
+		return listItem{}, false
+	}
+	if spaces > 4 {
+		spaces = 1
+	}
+	item.width = indent + marker + spaces
+	return item, true
+}
+
+// interrupts checks whether a line starts a block that interrupts a paragraph.
+func interrupts(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if level, _ := headingOf(trimmed); level > 0 {
+		return true
+	}
+	if fenceOf(trimmed) != "" || isThematicBreak(trimmed) || strings.HasPrefix(trimmed, ">") {
+		return true
+	}
+	// Only ordered lists starting at one interrupt paragraphs, so that numbers can start a line of text.
+	item, ok := listItemOf(line)
+	return ok && (!item.ordered || item.start == 1)
+}
+
+// expandTabs replaces tabs in the indentation of a line with four spaces.
+func expandTabs(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	return strings.ReplaceAll(line[:indent], "\t", "    ") + line[indent:]
+}
+
+// indentOf counts leading spaces of a line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// nextNonBlank returns the index of the first non-blank line at or after start, or len(lines).
+func nextNonBlank(lines []string, start int) int {
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	return start
+}
+
+// safeLine returns lines[i], or an empty string if i is out of range.
+func safeLine(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// runOf counts repetitions of text[start] starting at start.
+func runOf(text string, start int) int {
+	n := 1
+	for start+n < len(text) && text[start+n] == text[start] {
+		n++
+	}
+	return n
+}
+
+// closingRun returns the position of the first run of exactly n backticks at or after start, or -1.
+func closingRun(text string, start int, n int) int {
+	for i := start; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := runOf(text, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+func isPunctuation(c byte) bool {
+	return c < 0x80 && strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", rune(c))
+}
+
+func isAlphanumeric(c byte) bool {
+	return isLetter(c) || isDigit(c)
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
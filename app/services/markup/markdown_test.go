@@ -0,0 +1,69 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		// Headings and paragraphs.
+		{
+			source:   "# Hello *world* #\n\nfirst line\nsecond line  \nthird line",
+			expected: "<h1>Hello <em>world</em></h1>\n<p>first line\nsecond line  <br>\nthird line</p>\n",
+		},
+		// Inline elements.
+		{
+			source:   "**strong**, _em_, ***both***, ~~gone~~, `a <b>`, snake_case_name, 2 * 3 * 4",
+			expected: "<p><strong>strong</strong>, <em>em</em>, <em><strong>both</strong></em>, <del>gone</del>, <code>a &lt;b&gt;</code>, snake_case_name, 2 * 3 * 4</p>\n",
+		},
+		// Links, images and autolinks.
+		{
+			source:   "[site](https://example.com/a_(b) \"Title\") ![alt *text*](/a.png) <https://example.com> <me@example.com>",
+			expected: "<p><a href=\"https://example.com/a_(b)\" title=\"Title\">site</a> <img src=\"/a.png\" alt=\"alt text\"> <a href=\"https://example.com\">https://example.com</a> <a href=\"mailto:me@example.com\">me@example.com</a></p>\n",
+		},
+		// Raw HTML and escapes.
+		{
+			source:   "<script>alert(1)</script> \\*not em\\* &copy; & 1 < 2",
+			expected: "<p>&lt;script&gt;alert(1)&lt;/script&gt; *not em* &copy; &amp; 1 &lt; 2</p>\n",
+		},
+		// Tight and nested lists.
+		{
+			source:   "- a\n- b\n  - c\n- d",
+			expected: "<ul>\n<li>a</li>\n<li>b<ul>\n<li>c</li>\n</ul>\n</li>\n<li>d</li>\n</ul>\n",
+		},
+		// Loose ordered lists.
+		{
+			source:   "3. three\n\n4. four",
+			expected: "<ol start=\"3\">\n<li><p>three</p>\n</li>\n<li><p>four</p>\n</li>\n</ol>\n",
+		},
+		// Numbers don't interrupt paragraphs.
+		{
+			source:   "It was\n2019. A good year.",
+			expected: "<p>It was\n2019. A good year.</p>\n",
+		},
+		// Block quotes and thematic breaks.
+		{
+			source:   "> quoted\n> **text**\n\n* * *",
+			expected: "<blockquote>\n<p>quoted\n<strong>text</strong></p>\n</blockquote>\n<hr>\n",
+		},
+		// Fenced code blocks.
+		{
+			source:   "```go\nif a < b {\n}\n```\nafter",
+			expected: "<pre><code class=\"language-go\">if a &lt; b {\n}\n</code></pre>\n<p>after</p>\n",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, renderMarkdown(c.source), c.source)
+	}
+
+	// Block quotes and lists nest up to maxNesting levels, deeper markers are text.
+	nested := renderMarkdown(strings.Repeat("> ", maxNesting) + "- a")
+	assert.Equal(t, maxNesting, strings.Count(nested, "<blockquote>"))
+	assert.Contains(t, nested, "<p>- a</p>")
+}
@@ -0,0 +1,279 @@
+package markup
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	helpers "github.com/samber/lo"
+)
+
+var (
+	// allowedElements maps elements that survive sanitization to attributes they can keep.
+	allowedElements = map[string][]string{
+		"a":          {"href", "title"},
+		"b":          nil,
+		"blockquote": nil,
+		"br":         nil,
+		"code":       {"class"},
+		"del":        nil,
+		"em":         nil,
+		"h1":         nil,
+		"h2":         nil,
+		"h3":         nil,
+		"h4":         nil,
+		"h5":         nil,
+		"h6":         nil,
+		"hr":         nil,
+		"i":          nil,
+		"img":        {"src", "alt", "title"},
+		"li":         nil,
+		"ol":         {"start"},
+		"p":          nil,
+		"pre":        nil,
+		"s":          nil,
+		"strong":     nil,
+		"sub":        nil,
+		"sup":        nil,
+		"table":      nil,
+		"tbody":      nil,
+		"td":         nil,
+		"th":         nil,
+		"thead":      nil,
+		"tr":         nil,
+		"ul":         nil,
+	}
+	// discardedElements are removed along with their content, anything else that is not allowed only loses its tags.
+	discardedElements = []string{
+		"script", "style", "iframe", "object", "embed", "noscript", "template", "textarea", "select", "svg", "math",
+		"title", "head",
+	}
+	// voidElements have no content and no end tag.
+	voidElements = []string{"br", "hr", "img"}
+	// allowedSchemes are URL schemes links and images can use, relative URLs are allowed as well.
+	allowedSchemes = []string{"http", "https", "mailto"}
+	// attributeValues restricts values of attributes that are not URLs.
+	attributeValues = map[string]*regexp.Regexp{
+		"class": regexp.MustCompile(`^language-[a-zA-Z0-9_+#-]+$`),
+		"start": regexp.MustCompile(`^[0-9]{1,9}$`),
+	}
+	// scheme matches a URL scheme.
+	scheme = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+)
+
+// token is a tag found in HTML source.
+type token struct {
+	name       string
+	closing    bool
+	selfClosed bool
+	attributes [][2]string
+}
+
+// Sanitize rebuilds HTML from an allow-list of elements and attributes. Comments and disallowed attributes are
+// dropped, so are scripts and other active content, along with whatever they contain. URLs are limited to
+// allowedSchemes, and unbalanced tags are closed.
+func Sanitize(source string) string {
+	var builder strings.Builder
+	open := make([]string, 0)
+	for i := 0; i < len(source); {
+		if source[i] != '<' {
+			end := strings.IndexByte(source[i:], '<')
+			if end < 0 {
+				end = len(source) - i
+			}
+			builder.WriteString(html.EscapeString(html.UnescapeString(source[i : i+end])))
+			i += end
+			continue
+		}
+		if strings.HasPrefix(source[i:], "<!--") {
+			i = skipPast(source, i, "-->")
+			continue
+		}
+		if strings.HasPrefix(source[i:], "<!") || strings.HasPrefix(source[i:], "<?") {
+			i = skipPast(source, i, ">")
+			continue
+		}
+		t, end, ok := parseTag(source, i)
+		if !ok {
+			// Either not a tag, or a tag left unterminated, which is text up to the end of source.
+			if end == i {
+				end++
+			}
+			builder.WriteString(html.EscapeString(html.UnescapeString(source[i:end])))
+			i = end
+			continue
+		}
+		i = end
+		_, allowed := allowedElements[t.name]
+		switch {
+		case helpers.Contains(discardedElements, t.name):
+			if !t.closing && !t.selfClosed {
+				i = skipPast(source, skipPastFold(source, i, "</"+t.name), ">")
+			}
+		case !allowed:
+		case t.closing:
+			index := helpers.LastIndexOf(open, t.name)
+			if index < 0 {
+				continue
+			}
+			for len(open) > index {
+				builder.WriteString(fmt.Sprintf("</%v>", open[len(open)-1]))
+				open = open[:len(open)-1]
+			}
+		default:
+			builder.WriteString(renderTag(t))
+			if !helpers.Contains(voidElements, t.name) {
+				open = append(open, t.name)
+			}
+		}
+	}
+	for len(open) > 0 {
+		builder.WriteString(fmt.Sprintf("</%v>", open[len(open)-1]))
+		open = open[:len(open)-1]
+	}
+	return builder.String()
+}
+
+// renderTag renders a start tag keeping only allowed attributes with safe values.
+func renderTag(t token) string {
+	var builder strings.Builder
+	builder.WriteString("<" + t.name)
+	for _, attribute := range t.attributes {
+		name, value := attribute[0], html.UnescapeString(attribute[1])
+		if !helpers.Contains(allowedElements[t.name], name) {
+			continue
+		}
+		if pattern, ok := attributeValues[name]; ok && !pattern.MatchString(value) {
+			continue
+		}
+		if (name == "href" || name == "src") && !safeURL(value) {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf(" %v=\"%v\"", name, html.EscapeString(value)))
+	}
+	if t.name == "a" {
+		builder.WriteString(" rel=\"nofollow noopener\"")
+	}
+	builder.WriteString(">")
+	return builder.String()
+}
+
+// safeURL checks whether a URL is relative or uses one of allowedSchemes.
+func safeURL(value string) bool {
+	// Browsers ignore control characters and whitespace in schemes.
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	match := scheme.FindStringSubmatch(cleaned)
+	if match == nil {
+		return !strings.Contains(strings.SplitN(cleaned, "/", 2)[0], ":")
+	}
+	return helpers.Contains(allowedSchemes, strings.ToLower(match[1]))
+}
+
+// parseTag parses a start or end tag at source[start], returns the position right after it. If there's no tag at
+// source[start], or the tag isn't terminated, returns start or the end of source respectively, and false.
+func parseTag(source string, start int) (token, int, bool) {
+	var t token
+	i := start + 1
+	if i < len(source) && source[i] == '/' {
+		t.closing = true
+		i++
+	}
+	nameStart := i
+	for i < len(source) && (isLetter(source[i]) || (i > nameStart && isDigit(source[i]))) {
+		i++
+	}
+	if i == nameStart {
+		return token{}, start, false
+	}
+	t.name = strings.ToLower(source[nameStart:i])
+	for {
+		for i < len(source) && isSpace(source[i]) {
+			i++
+		}
+		if i >= len(source) {
+			return token{}, len(source), false
+		}
+		switch {
+		case source[i] == '>':
+			return t, i + 1, true
+		case strings.HasPrefix(source[i:], "/>"):
+			t.selfClosed = true
+			return t, i + 2, true
+		case source[i] == '/':
+			i++
+			continue
+		}
+		nameStart = i
+		for i < len(source) && !isSpace(source[i]) && !strings.ContainsRune("\"'>/=", rune(source[i])) {
+			i++
+		}
+		if i == nameStart {
+			// Stray quote or equals sign, skip it.
+			i++
+			continue
+		}
+		name := strings.ToLower(source[nameStart:i])
+		for i < len(source) && isSpace(source[i]) {
+			i++
+		}
+		value := ""
+		if i < len(source) && source[i] == '=' {
+			i++
+			for i < len(source) && isSpace(source[i]) {
+				i++
+			}
+			if i < len(source) && (source[i] == '"' || source[i] == '\'') {
+				end := strings.IndexByte(source[i+1:], source[i])
+				if end < 0 {
+					return token{}, len(source), false
+				}
+				value = source[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(source) && !isSpace(source[i]) && source[i] != '>' {
+					i++
+				}
+				value = source[valueStart:i]
+			}
+		}
+		t.attributes = append(t.attributes, [2]string{name, value})
+	}
+}
+
+// skipPast returns the position right after the first occurrence of marker at or after start, or the end of source.
+func skipPast(source string, start int, marker string) int {
+	index := strings.Index(source[start:], marker)
+	if index < 0 {
+		return len(source)
+	}
+	return start + index + len(marker)
+}
+
+// skipPastFold is skipPast matching an ASCII marker case-insensitively.
+func skipPastFold(source string, start int, marker string) int {
+	for i := start; i+len(marker) <= len(source); i++ {
+		if strings.EqualFold(source[i:i+len(marker)], marker) {
+			return i + len(marker)
+		}
+	}
+	return len(source)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,133 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"testing"
+
+	helpers "github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// sanitizedToken matches a tag or a run of escaped text at the beginning of sanitized HTML.
+	sanitizedToken = regexp.MustCompile(`^(?:<(/?)([a-z][a-z0-9]*)((?: [a-z]+="[^"<>]*")*)>|[^<>"']+)`)
+	// sanitizedAttribute matches an attribute of a tag of sanitized HTML.
+	sanitizedAttribute = regexp.MustCompile(` ([a-z]+)="([^"]*)"`)
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		// Allowed markup is kept.
+		{
+			source:   "<h2>Title</h2><p>Some <strong>bold</strong> text<br/></p>",
+			expected: "<h2>Title</h2><p>Some <strong>bold</strong> text<br></p>",
+		},
+		// Scripts and other active content are dropped with their content.
+		{
+			source:   "<p>a<script>alert(1)</script>b<STYLE>p{}</style>c<iframe src=\"x\"></iframe></p>",
+			expected: "<p>abc</p>",
+		},
+		// Unknown elements lose their tags, comments are dropped.
+		{
+			source:   "<div class=\"x\"><span>text</span><!-- comment --></div>",
+			expected: "text",
+		},
+		// Event handlers and unknown attributes are dropped.
+		{
+			source:   "<p onclick=\"x()\" style=\"color: red\">a</p><img src=\"/a.png\" onerror=\"x()\" alt=\"A\">",
+			expected: "<p>a</p><img src=\"/a.png\" alt=\"A\">",
+		},
+		// Dangerous URLs are dropped, links get rel.
+		{
+			source:   "<a href=\"JaVa&#115;cript:alert(1)\">a</a><a href=\" java\tscript:x\">b</a><a href=\"/path:x\" target=\"_blank\">c</a>",
+			expected: "<a rel=\"nofollow noopener\">a</a><a rel=\"nofollow noopener\">b</a><a href=\"/path:x\" rel=\"nofollow noopener\">c</a>",
+		},
+		// Attribute values are restricted.
+		{
+			source:   "<pre><code class=\"language-go\">x</code><code class=\"evil\">y</code></pre><ol start=\"x\"></ol>",
+			expected: "<pre><code class=\"language-go\">x</code><code>y</code></pre><ol></ol>",
+		},
+		// Unbalanced tags are closed, stray end tags dropped, text is escaped.
+		{
+			source:   "</em><p><b>bold<i>both</p> 1 < 2 &amp; \"quoted\"",
+			expected: "<p><b>bold<i>both</i></b></p> 1 &lt; 2 &amp; &#34;quoted&#34;",
+		},
+		// Unterminated tags are text up to the end.
+		{
+			source:   "<p>a <a href=\"x>b</a> <b>c</b>",
+			expected: "<p>a &lt;a href=&#34;x&gt;b&lt;/a&gt; &lt;b&gt;c&lt;/b&gt;</p>",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, Sanitize(c.source), c.source)
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	seeds := []string{
+		"<h2>Title</h2><p>Some <strong>bold</strong> text<br/></p>",
+		"<p>a<script>alert(1)</script>b<STYLE>p{}</style>c<iframe src=\"x\"></iframe></p>",
+		"<div class=\"x\"><span>text</span><!-- comment --></div>",
+		"<p onclick=\"x()\" style=\"color: red\">a</p><img src=\"/a.png\" onerror=\"x()\" alt=\"A\">",
+		"<a href=\"JaVa&#115;cript:alert(1)\">a</a><a href=\" java\tscript:x\">b</a>",
+		"<pre><code class=\"language-go\">x</code></pre><ol start=\"x\"></ol>",
+		"</em><p><b>bold<i>both</p> 1 < 2 &amp; \"quoted\"",
+		"<a href=javascript:x title='a\"b'>x</a><img src=\"data:x\"/><svg><a href=x>",
+		"<a <a <a href='<b>x",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		sanitized := Sanitize(source)
+		assertSanitized(t, sanitized)
+		assert.Equal(t, sanitized, Sanitize(sanitized))
+	})
+}
+
+// assertSanitized checks that HTML is made of balanced allowed elements with safe attributes, and escaped text.
+func assertSanitized(t *testing.T, sanitized string) {
+	open := make([]string, 0)
+	for rest := sanitized; rest != ""; {
+		match := sanitizedToken.FindStringSubmatch(rest)
+		if !assert.NotNil(t, match, "unexpected markup at %q", rest) {
+			return
+		}
+		rest = rest[len(match[0]):]
+		closing, name, attributes := match[1] == "/", match[2], match[3]
+		switch {
+		case name == "":
+		case closing:
+			if assert.NotEmpty(t, open, "stray end tag %v", name) {
+				assert.Equal(t, open[len(open)-1], name, "unbalanced end tag")
+				open = open[:len(open)-1]
+			}
+		default:
+			allowed, ok := allowedElements[name]
+			if !assert.True(t, ok, "disallowed element %v", name) {
+				return
+			}
+			for _, attribute := range sanitizedAttribute.FindAllStringSubmatch(attributes, -1) {
+				key, value := attribute[1], html.UnescapeString(attribute[2])
+				if name == "a" && key == "rel" {
+					continue
+				}
+				assert.Contains(t, allowed, key, "disallowed attribute of %v", name)
+				if pattern, ok := attributeValues[key]; ok {
+					assert.Regexp(t, pattern, value)
+				}
+				if key == "href" || key == "src" {
+					assert.True(t, safeURL(value), "unsafe URL %q", value)
+				}
+			}
+			if !helpers.Contains(voidElements, name) {
+				open = append(open, name)
+			}
+		}
+	}
+	assert.Empty(t, open, "unclosed elements")
+}
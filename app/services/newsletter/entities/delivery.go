@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+)
+
+// DeliveryStatus is a stage of a post delivery to a subscriber.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are waiting for their next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySending deliveries have been handed to the mail server, their outcome isn't known yet.
+	DeliverySending DeliveryStatus = "sending"
+	// DeliverySent deliveries have been accepted by the mail server.
+	DeliverySent DeliveryStatus = "sent"
+	// DeliveryFailed deliveries have run out of attempts.
+	DeliveryFailed DeliveryStatus = "failed"
+	// DeliveryBounced deliveries have been permanently rejected by the mail server.
+	DeliveryBounced DeliveryStatus = "bounced"
+	// DeliverySkipped deliveries were dropped, because the subscriber opted out or the post was unpublished.
+	DeliverySkipped DeliveryStatus = "skipped"
+)
+
+// Delivery represents a post to be sent to a subscriber.
+type Delivery struct {
+	ID            DeliveryID     `json:"id" gorm:"column:id; primary_key:yes"`
+	PostID        posts.PostID   `json:"post_id" gorm:"column:post_id"`
+	SubscriberID  SubscriberID   `json:"subscriber_id" gorm:"column:subscriber_id"`
+	Status        DeliveryStatus `json:"status" gorm:"column:status; default:pending"`
+	Attempts      int            `json:"attempts" gorm:"column:attempts"`
+	LastError     string         `json:"last_error,omitempty" gorm:"column:last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" gorm:"column:next_attempt_at"`
+	SentAt        *time.Time     `json:"sent_at" gorm:"column:sent_at"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"column:created_at"`
+
+	Subscriber *Subscriber `json:"-" gorm:"foreignKey:SubscriberID"`
+}
+
+// TableName ...
+func (Delivery) TableName() string {
+	return "deliveries"
+}
+
+// DeliveryReport sums up a delivery run.
+type DeliveryReport struct {
+	Sent    int `json:"sent"`
+	Retried int `json:"retried"`
+	Failed  int `json:"failed"`
+	Bounced int `json:"bounced"`
+	Skipped int `json:"skipped"`
+}
+
+// DeliveryStats counts deliveries of a post by status.
+type DeliveryStats struct {
+	Pending int64 `json:"pending"`
+	Sending int64 `json:"sending"`
+	Sent    int64 `json:"sent"`
+	Failed  int64 `json:"failed"`
+	Bounced int64 `json:"bounced"`
+	Skipped int64 `json:"skipped"`
+}
+
+// Settings configure deliveries.
+type Settings struct {
+	// From is the sender address of every message.
+	From string
+	// MaxAttempts is the number of times a delivery is tried before it fails.
+	MaxAttempts int
+	// RetryDelay is the delay before the first retry, it doubles on every following one.
+	RetryDelay time.Duration
+	// SendTimeout is the time after which deliveries still sending are given up as failed, without being sent again.
+	SendTimeout time.Duration
+	// MaxBounces is the number of permanently rejected messages after which a subscriber is no longer delivered to.
+	MaxBounces int
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrNilSender is thrown when an unexpected nil mail sender is encountered.
+	ErrNilSender = errors.New("mail sender is nil")
+	// ErrInvalidEmail is thrown when an email address is malformed or longer than 255 characters.
+	ErrInvalidEmail = errors.New("email has to be a valid address of 255 characters or less")
+	// ErrSubscriberNotFound is thrown when a subscriber can't be found.
+	ErrSubscriberNotFound = errors.New("subscriber not found")
+	// ErrInvalidToken is thrown when a confirmation or an unsubscribe token doesn't match any subscriber.
+	ErrInvalidToken = errors.New("token is invalid or has already been used")
+	// ErrPostNotPublished is thrown when delivering a post that isn't published.
+	ErrPostNotPublished = errors.New("only published posts can be delivered")
+)
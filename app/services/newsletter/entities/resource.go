@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+)
+
+var (
+	// SubscriberResource whitelists fields clients can request for subscribers.
+	SubscriberResource = query.Resource{
+		Fields: map[string]string{
+			"id":              "id",
+			"email":           "email",
+			"status":          "status",
+			"bounces":         "bounces",
+			"confirmed_at":    "confirmed_at",
+			"unsubscribed_at": "unsubscribed_at",
+			"updated_at":      "updated_at",
+			"created_at":      "created_at",
+		},
+		Filterable: map[string]query.Filter{
+			"email":      {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorContains}},
+			"status":     {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorNe, query.OperatorIn}},
+			"created_at": {Type: query.TypeTime, Operators: []query.Operator{query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte}},
+		},
+		Sortable:    []string{"id", "email", "created_at"},
+		DefaultSort: []query.Sort{{Field: "created_at", Desc: true}},
+	}
+)
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SubscriberStatus is a stage of a subscription, only active subscribers receive posts.
+type SubscriberStatus string
+
+const (
+	// SubscriberPending subscribers haven't confirmed their address yet.
+	SubscriberPending SubscriberStatus = "pending"
+	// SubscriberActive subscribers have confirmed their address.
+	SubscriberActive SubscriberStatus = "active"
+	// SubscriberUnsubscribed subscribers have opted out.
+	SubscriberUnsubscribed SubscriberStatus = "unsubscribed"
+	// SubscriberBounced subscribers have had too many messages rejected by their mail server.
+	SubscriberBounced SubscriberStatus = "bounced"
+)
+
+// Subscriber represents an email address newsletter posts are delivered to.
+type Subscriber struct {
+	ID     SubscriberID     `json:"id" gorm:"column:id; primary_key:yes"`
+	Email  string           `json:"email" gorm:"column:email"`
+	Status SubscriberStatus `json:"status" gorm:"column:status; default:pending"`
+	// Bounces counts messages permanently rejected since the address was confirmed.
+	Bounces        int        `json:"bounces" gorm:"column:bounces"`
+	ConfirmedAt    *time.Time `json:"confirmed_at" gorm:"column:confirmed_at"`
+	UnsubscribedAt *time.Time `json:"unsubscribed_at,omitempty" gorm:"column:unsubscribed_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+
+	// Tokens are only ever sent to the address itself, and never rendered.
+	ConfirmationToken string `json:"-" gorm:"column:confirmation_token"`
+	UnsubscribeToken  string `json:"-" gorm:"column:unsubscribe_token"`
+}
+
+// JSONAPIType ...
+func (Subscriber) JSONAPIType() string {
+	return "subscribers"
+}
+
+// JSONAPIID ...
+func (s Subscriber) JSONAPIID() string {
+	return strconv.FormatUint(uint64(s.ID), 10)
+}
+
+// TableName ...
+func (Subscriber) TableName() string {
+	return "subscribers"
+}
+
+// NormalizeEmail validates an email address and reduces it to a bare lower-cased address, so that different
+// spellings of the same address are subscribed once.
+func NormalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || len(address.Address) > 255 {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(address.Address), nil
+}
@@ -0,0 +1,6 @@
+package entities
+
+type (
+	SubscriberID uint32
+	DeliveryID   uint32
+)
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+)
+
+// NewsletterService is an interface that is used by outside packages to manage subscribers and deliver posts to them.
+type NewsletterService interface {
+	Subscribe(ctx context.Context, email string) (entities.Subscriber, error)
+	Confirm(ctx context.Context, token string) (entities.Subscriber, error)
+	Unsubscribe(ctx context.Context, token string) (entities.Subscriber, error)
+	IndexSubscribers(ctx context.Context, opts query.Options) ([]entities.Subscriber, query.PageInfo, error)
+	RecordBounce(ctx context.Context, email string) (entities.Subscriber, error)
+	QueueDelivery(ctx context.Context, id eposts.PostID) (int64, error)
+	Deliver(ctx context.Context, batchSize int) (entities.DeliveryReport, error)
+	DeliveryStats(ctx context.Context, id eposts.PostID) (entities.DeliveryStats, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+)
+
+// URLs builds absolute URLs of pages emails link to, they are served by controllers.
+type URLs interface {
+	Post(post eposts.Post) (string, error)
+	Confirm(token string) (string, error)
+	Unsubscribe(token string) (string, error)
+}
@@ -0,0 +1,286 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	htmltemplate "html/template"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queueDeliverySQL queues a post for every active subscriber, subscribers it has already been queued for are left
+// alone, so that a post is never sent twice.
+const queueDeliverySQL = `
+INSERT INTO deliveries (post_id, subscriber_id, status, next_attempt_at, updated_at, created_at)
+SELECT ?, id, ?, ?, ?, ? FROM subscribers WHERE status = ?
+ON CONFLICT (post_id, subscriber_id) DO NOTHING`
+
+// QueueDelivery queues a published post to be delivered to active subscribers, returns the number of deliveries
+// queued. Throws eposts.ErrPostNotFound if id is invalid, and entities.ErrPostNotPublished if the post isn't
+// published.
+func (s *NewsletterService) QueueDelivery(ctx context.Context, id eposts.PostID) (int64, error) {
+	post, err := s.posts.FindPost(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if post.Status != eposts.StatusPublished {
+		return 0, entities.ErrPostNotPublished
+	}
+	now := time.Now().UTC()
+	result := s.writer.WithContext(ctx).Exec(queueDeliverySQL,
+		post.ID, entities.DeliveryPending, now, now, now, entities.SubscriberActive)
+	return result.RowsAffected, result.Error
+}
+
+// Deliver sends a batch of due deliveries over a single connection to the mail server.
+// Deliveries are claimed as sending in a short transaction before any message goes out, so that concurrent runs pick
+// different ones and no lock is held while talking to the mail server, their outcomes are stored in a second one.
+// Messages rejected for good count as bounces of their subscribers, other failures are retried with an exponential
+// backoff until entities.Settings.MaxAttempts is reached. Deliveries to subscribers who opted out, and of posts that
+// are no longer published, are skipped. Deliveries still sending after entities.Settings.SendTimeout, because a run
+// died before storing their outcome, fail rather than risk sending a post twice.
+func (s *NewsletterService) Deliver(ctx context.Context, batchSize int) (entities.DeliveryReport, error) {
+	var report entities.DeliveryReport
+	abandoned, err := s.abandon(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Failed += abandoned
+
+	var (
+		subscribers map[entities.SubscriberID]*entities.Subscriber
+		sending     []entities.Delivery
+		messages    []mail.Message
+	)
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		report.Skipped, sending, messages = 0, nil, nil
+		var deliveries []entities.Delivery
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entities.DeliveryPending, time.Now().UTC()).
+			Order("next_attempt_at, id").
+			Limit(batchSize).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		if subscribers, err = s.subscribers(tx, deliveries); err != nil {
+			return err
+		}
+
+		posts := make(map[eposts.PostID]*eposts.Post)
+		for _, delivery := range deliveries {
+			post, ok := posts[delivery.PostID]
+			if !ok {
+				found, err := s.posts.FindPost(ctx, delivery.PostID)
+				if err != nil && !errors.Is(err, eposts.ErrPostNotFound) {
+					return err
+				}
+				if err == nil && found.Status == eposts.StatusPublished {
+					post = &found
+				}
+				posts[delivery.PostID] = post
+			}
+			subscriber := subscribers[delivery.SubscriberID]
+			if post == nil || subscriber == nil || subscriber.Status != entities.SubscriberActive {
+				report.Skipped++
+				if err := s.finish(tx, delivery, entities.DeliverySkipped, ""); err != nil {
+					return err
+				}
+				continue
+			}
+			message, err := s.postMessage(*post, *subscriber)
+			if err != nil {
+				return err
+			}
+			sending = append(sending, delivery)
+			messages = append(messages, message)
+		}
+		return s.claim(tx, sending)
+	})
+	if err != nil || len(messages) == 0 {
+		return report, err
+	}
+
+	results := s.sender.Send(ctx, messages)
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		outcome := report
+		for i, sendErr := range results {
+			delivery := sending[i]
+			delivery.Attempts++
+			var err error
+			switch {
+			case sendErr == nil:
+				outcome.Sent++
+				err = s.finish(tx, delivery, entities.DeliverySent, "")
+			case mail.IsPermanent(sendErr):
+				outcome.Bounced++
+				err = s.finish(tx, delivery, entities.DeliveryBounced, sendErr.Error())
+				if err == nil {
+					err = s.bounce(tx, subscribers[delivery.SubscriberID])
+				}
+			case delivery.Attempts >= s.settings.MaxAttempts:
+				outcome.Failed++
+				err = s.finish(tx, delivery, entities.DeliveryFailed, sendErr.Error())
+			default:
+				outcome.Retried++
+				err = s.retry(tx, delivery, sendErr.Error())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		report = outcome
+		return nil
+	})
+	return report, err
+}
+
+// claim marks deliveries as sending until entities.Settings.SendTimeout has passed.
+func (s *NewsletterService) claim(tx *gorm.DB, deliveries []entities.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	ids := make([]entities.DeliveryID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+	now := time.Now().UTC()
+	return tx.Model(&entities.Delivery{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":          entities.DeliverySending,
+		"next_attempt_at": now.Add(s.settings.SendTimeout),
+		"updated_at":      now,
+	}).Error
+}
+
+// abandon fails deliveries that have been sending for longer than entities.Settings.SendTimeout, returns their number.
+// Whether their messages went out is unknown, so they aren't sent again.
+func (s *NewsletterService) abandon(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	result := s.writer.WithContext(ctx).Model(&entities.Delivery{}).
+		Where("status = ? AND next_attempt_at <= ?", entities.DeliverySending, now).
+		Updates(map[string]interface{}{
+			"status":     entities.DeliveryFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "abandoned while sending",
+			"updated_at": now,
+		})
+	return int(result.RowsAffected), result.Error
+}
+
+// subscribers fetches subscribers of deliveries by id.
+func (s *NewsletterService) subscribers(tx *gorm.DB, deliveries []entities.Delivery) (map[entities.SubscriberID]*entities.Subscriber, error) {
+	ids := make([]entities.SubscriberID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.SubscriberID)
+	}
+	var found []entities.Subscriber
+	if err := tx.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	subscribers := make(map[entities.SubscriberID]*entities.Subscriber, len(found))
+	for i := range found {
+		subscribers[found[i].ID] = &found[i]
+	}
+	return subscribers, nil
+}
+
+// postMessage renders the email delivering post to subscriber.
+func (s *NewsletterService) postMessage(post eposts.Post, subscriber entities.Subscriber) (mail.Message, error) {
+	link, err := s.urls.Post(post)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	unsubscribeLink, err := s.urls.Unsubscribe(subscriber.UnsubscribeToken)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	text := post.Content
+	if post.Format == markup.FormatHTML {
+		// HTML sources make for unreadable text, the excerpt and the link do better.
+		text = post.Excerpt
+	}
+	message, err := render("post", post.Title, postEmail{
+		Title:           post.Title,
+		HTML:            htmltemplate.HTML(post.ContentHTML),
+		Text:            text,
+		Link:            link,
+		UnsubscribeLink: unsubscribeLink,
+	})
+	if err != nil {
+		return mail.Message{}, err
+	}
+	message.From, message.To = s.settings.From, subscriber.Email
+	// Mail clients show an unsubscribe button posting to the link, see RFC 8058.
+	message.Header = map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeLink + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return message, nil
+}
+
+// finish stores the final outcome of a delivery.
+func (s *NewsletterService) finish(tx *gorm.DB, delivery entities.Delivery, status entities.DeliveryStatus, lastError string) error {
+	now := time.Now().UTC()
+	updates := map[string]interface{}{
+		"status":     status,
+		"attempts":   delivery.Attempts,
+		"last_error": lastError,
+		"updated_at": now,
+	}
+	if status == entities.DeliverySent {
+		updates["sent_at"] = now
+	}
+	return tx.Model(&delivery).Updates(updates).Error
+}
+
+// retry schedules the next attempt of a delivery, the delay doubles with every attempt.
+func (s *NewsletterService) retry(tx *gorm.DB, delivery entities.Delivery, lastError string) error {
+	now := time.Now().UTC()
+	return tx.Model(&delivery).Updates(map[string]interface{}{
+		"attempts":        delivery.Attempts,
+		"last_error":      lastError,
+		"next_attempt_at": now.Add(s.settings.RetryDelay << (delivery.Attempts - 1)),
+		"updated_at":      now,
+	}).Error
+}
+
+// DeliveryStats counts deliveries of a post by status.
+func (s *NewsletterService) DeliveryStats(ctx context.Context, id eposts.PostID) (entities.DeliveryStats, error) {
+	var rows []struct {
+		Status entities.DeliveryStatus
+		Count  int64
+	}
+	err := s.reader.WithContext(ctx).Model(&entities.Delivery{}).
+		Select("status, COUNT(*) AS count").
+		Where("post_id = ?", id).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return entities.DeliveryStats{}, err
+	}
+	var stats entities.DeliveryStats
+	for _, row := range rows {
+		switch row.Status {
+		case entities.DeliveryPending:
+			stats.Pending = row.Count
+		case entities.DeliverySending:
+			stats.Sending = row.Count
+		case entities.DeliverySent:
+			stats.Sent = row.Count
+		case entities.DeliveryFailed:
+			stats.Failed = row.Count
+		case entities.DeliveryBounced:
+			stats.Bounced = row.Count
+		case entities.DeliverySkipped:
+			stats.Skipped = row.Count
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,213 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/interfaces"
+	iposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Verify that NewsletterService satisfies the interfaces.NewsletterService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.NewsletterService = (*NewsletterService)(nil)
+
+// NewsletterService implements interfaces.NewsletterService.
+type NewsletterService struct {
+	reader   *gorm.DB
+	writer   *gorm.DB
+	posts    iposts.PostsService
+	sender   mail.Sender
+	urls     interfaces.URLs
+	settings entities.Settings
+}
+
+// NewNewsletterService instantiates a new NewsletterService, posts are fetched from posts and sent with sender,
+// links in emails are built by urls.
+func NewNewsletterService(ctx context.Context, reader *gorm.DB, writer *gorm.DB, posts iposts.PostsService, sender mail.Sender, urls interfaces.URLs, settings entities.Settings) (*NewsletterService, error) {
+	if reader == nil || writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	if sender == nil {
+		return nil, entities.ErrNilSender
+	}
+	return &NewsletterService{
+		reader:   reader,
+		writer:   writer,
+		posts:    posts,
+		sender:   sender,
+		urls:     urls,
+		settings: settings,
+	}, nil
+}
+
+// Subscribe subscribes an email address and sends it a confirmation link, it only receives posts once confirmed.
+// Subscribing an active address changes nothing, subscribing it again once it opted out or bounced sends a new
+// confirmation link. Throws entities.ErrInvalidEmail if email is malformed.
+func (s *NewsletterService) Subscribe(ctx context.Context, email string) (entities.Subscriber, error) {
+	email, err := entities.NormalizeEmail(email)
+	if err != nil {
+		return entities.Subscriber{}, err
+	}
+	var subscriber entities.Subscriber
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscriber, "email = ?", email).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			subscriber = entities.Subscriber{
+				Email:             email,
+				Status:            entities.SubscriberPending,
+				ConfirmationToken: newToken(),
+				UnsubscribeToken:  newToken(),
+			}
+			return tx.Create(&subscriber).Error
+		case err != nil:
+			return err
+		case subscriber.Status == entities.SubscriberActive:
+			return nil
+		}
+		subscriber.Status = entities.SubscriberPending
+		subscriber.ConfirmationToken = newToken()
+		return tx.Model(&subscriber).Updates(map[string]interface{}{
+			"status":             subscriber.Status,
+			"confirmation_token": subscriber.ConfirmationToken,
+			"updated_at":         time.Now().UTC(),
+		}).Error
+	})
+	if err != nil || subscriber.Status == entities.SubscriberActive {
+		return subscriber, err
+	}
+	return subscriber, s.sendConfirmation(ctx, subscriber)
+}
+
+// sendConfirmation sends the confirmation link to a pending subscriber.
+func (s *NewsletterService) sendConfirmation(ctx context.Context, subscriber entities.Subscriber) error {
+	link, err := s.urls.Confirm(subscriber.ConfirmationToken)
+	if err != nil {
+		return err
+	}
+	message, err := render("confirmation", "Confirm your subscription", confirmationEmail{Link: link})
+	if err != nil {
+		return err
+	}
+	message.From, message.To = s.settings.From, subscriber.Email
+	return s.sender.Send(ctx, []mail.Message{message})[0]
+}
+
+// Confirm activates the subscriber a confirmation token was sent to, throws entities.ErrInvalidToken if the token
+// doesn't match a pending subscriber.
+func (s *NewsletterService) Confirm(ctx context.Context, token string) (entities.Subscriber, error) {
+	if token == "" {
+		return entities.Subscriber{}, entities.ErrInvalidToken
+	}
+	var subscriber entities.Subscriber
+	now := time.Now().UTC()
+	result := s.writer.WithContext(ctx).Model(&subscriber).
+		Clauses(clause.Returning{}).
+		Where("confirmation_token = ? AND status = ?", token, entities.SubscriberPending).
+		Updates(map[string]interface{}{
+			"status":             entities.SubscriberActive,
+			"confirmation_token": "",
+			"bounces":            0,
+			"confirmed_at":       now,
+			"unsubscribed_at":    nil,
+			"updated_at":         now,
+		})
+	if result.Error != nil {
+		return entities.Subscriber{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.Subscriber{}, entities.ErrInvalidToken
+	}
+	return subscriber, nil
+}
+
+// Unsubscribe opts out the subscriber an unsubscribe token was sent to, throws entities.ErrInvalidToken if the
+// token doesn't match any subscriber. Unsubscribing twice changes nothing, pending deliveries are skipped.
+func (s *NewsletterService) Unsubscribe(ctx context.Context, token string) (entities.Subscriber, error) {
+	if token == "" {
+		return entities.Subscriber{}, entities.ErrInvalidToken
+	}
+	var subscriber entities.Subscriber
+	db := s.writer.WithContext(ctx)
+	if err := db.First(&subscriber, "unsubscribe_token = ?", token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Subscriber{}, entities.ErrInvalidToken
+		}
+		return entities.Subscriber{}, err
+	}
+	if subscriber.Status == entities.SubscriberUnsubscribed {
+		return subscriber, nil
+	}
+	now := time.Now().UTC()
+	subscriber.Status, subscriber.UnsubscribedAt, subscriber.ConfirmationToken = entities.SubscriberUnsubscribed, &now, ""
+	err := db.Model(&subscriber).Updates(map[string]interface{}{
+		"status":             subscriber.Status,
+		"confirmation_token": "",
+		"unsubscribed_at":    now,
+		"updated_at":         now,
+	}).Error
+	return subscriber, err
+}
+
+// IndexSubscribers returns a page of subscribers, throws entities.ErrSubscriberNotFound if table is empty.
+func (s *NewsletterService) IndexSubscribers(ctx context.Context, opts query.Options) ([]entities.Subscriber, query.PageInfo, error) {
+	subscribers, page, err := query.Paginate[entities.Subscriber](s.reader.WithContext(ctx), entities.SubscriberResource, opts)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, query.PageInfo{}, entities.ErrSubscriberNotFound
+		}
+		return nil, query.PageInfo{}, err
+	}
+	return subscribers, page, nil
+}
+
+// RecordBounce records a message permanently rejected for an email address, reported by the mail server after
+// accepting it, throws entities.ErrSubscriberNotFound if the address isn't subscribed.
+func (s *NewsletterService) RecordBounce(ctx context.Context, email string) (entities.Subscriber, error) {
+	email, err := entities.NormalizeEmail(email)
+	if err != nil {
+		return entities.Subscriber{}, err
+	}
+	var subscriber entities.Subscriber
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscriber, "email = ?", email).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrSubscriberNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return s.bounce(tx, &subscriber)
+	})
+	return subscriber, err
+}
+
+// bounce counts a bounce of a subscriber, who is no longer delivered to once entities.Settings.MaxBounces is reached.
+func (s *NewsletterService) bounce(tx *gorm.DB, subscriber *entities.Subscriber) error {
+	subscriber.Bounces++
+	if subscriber.Status == entities.SubscriberActive && subscriber.Bounces >= s.settings.MaxBounces {
+		subscriber.Status = entities.SubscriberBounced
+	}
+	return tx.Model(subscriber).Updates(map[string]interface{}{
+		"bounces":    subscriber.Bounces,
+		"status":     subscriber.Status,
+		"updated_at": time.Now().UTC(),
+	}).Error
+}
+
+// newToken generates a random token, links carrying it prove access to the subscribed mailbox.
+func newToken() string {
+	random := make([]byte, 32)
+	_, _ = rand.Read(random)
+	return hex.EncodeToString(random)
+}
@@ -0,0 +1,275 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail/mailtest"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/interfaces"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	newsletterServiceTestInstance interfaces.NewsletterService
+	postsServiceTestInstance      *posts.PostsService
+	mailServerTestInstance        *mailtest.Server
+	writerTestInstance            *gorm.DB
+)
+
+// testURLs builds links to example.com.
+type testURLs struct{}
+
+func (testURLs) Post(post eposts.Post) (string, error) {
+	return "https://example.com/posts/by-slug/" + post.Slug, nil
+}
+
+func (testURLs) Confirm(token string) (string, error) {
+	return "https://example.com/subscribers/confirm/" + token, nil
+}
+
+func (testURLs) Unsubscribe(token string) (string, error) {
+	return "https://example.com/subscribers/unsubscribe/" + token, nil
+}
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewNewsletterService(t *testing.T) {
+	ctx := context.Background()
+	sender, err := mail.NewSMTPSender(mailServerTestInstance.Addr, "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewNewsletterService(ctx, writerTestInstance, writerTestInstance, postsServiceTestInstance, sender, testURLs{}, entities.Settings{})
+	assert.Nil(t, err)
+	_, err = NewNewsletterService(ctx, nil, writerTestInstance, postsServiceTestInstance, sender, testURLs{}, entities.Settings{})
+	assert.ErrorIs(t, err, entities.ErrNilDB)
+	_, err = NewNewsletterService(ctx, writerTestInstance, writerTestInstance, postsServiceTestInstance, nil, testURLs{}, entities.Settings{})
+	assert.ErrorIs(t, err, entities.ErrNilSender)
+}
+
+func TestNewsletterService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	mailServerTestInstance.Reset()
+	defer deleteSubscribers("test-subscribe@example.com")
+
+	_, err := newsletterServiceTestInstance.Subscribe(ctx, "not an address")
+	assert.ErrorIs(t, err, entities.ErrInvalidEmail)
+
+	// New subscribers are pending until they confirm.
+	subscriber, err := newsletterServiceTestInstance.Subscribe(ctx, "Tester <Test-Subscribe@example.com>")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "test-subscribe@example.com", subscriber.Email)
+	assert.Equal(t, entities.SubscriberPending, subscriber.Status)
+	messages := mailServerTestInstance.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, []string{"test-subscribe@example.com"}, messages[0].To)
+		assert.True(t, strings.Contains(messages[0].Data, "Confirm your subscription"))
+	}
+
+	_, err = newsletterServiceTestInstance.Confirm(ctx, "invalid")
+	assert.ErrorIs(t, err, entities.ErrInvalidToken)
+	confirmed, err := newsletterServiceTestInstance.Confirm(ctx, subscriber.ConfirmationToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, subscriber.ID, confirmed.ID)
+		assert.Equal(t, entities.SubscriberActive, confirmed.Status)
+		assert.NotNil(t, confirmed.ConfirmedAt)
+	}
+	// Confirmation tokens can't be reused.
+	_, err = newsletterServiceTestInstance.Confirm(ctx, subscriber.ConfirmationToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidToken)
+
+	// Subscribing an active address sends nothing.
+	_, err = newsletterServiceTestInstance.Subscribe(ctx, "test-subscribe@example.com")
+	assert.NoError(t, err)
+	assert.Len(t, mailServerTestInstance.Messages(), 1)
+
+	// Unsubscribing is idempotent.
+	for i := 0; i < 2; i++ {
+		unsubscribed, err := newsletterServiceTestInstance.Unsubscribe(ctx, subscriber.UnsubscribeToken)
+		if assert.NoError(t, err) {
+			assert.Equal(t, entities.SubscriberUnsubscribed, unsubscribed.Status)
+			assert.NotNil(t, unsubscribed.UnsubscribedAt)
+		}
+	}
+	_, err = newsletterServiceTestInstance.Unsubscribe(ctx, "invalid")
+	assert.ErrorIs(t, err, entities.ErrInvalidToken)
+
+	// Subscribing again needs a new confirmation.
+	resubscribed, err := newsletterServiceTestInstance.Subscribe(ctx, "test-subscribe@example.com")
+	if assert.NoError(t, err) {
+		assert.Equal(t, subscriber.ID, resubscribed.ID)
+		assert.Equal(t, entities.SubscriberPending, resubscribed.Status)
+		assert.NotEqual(t, subscriber.ConfirmationToken, resubscribed.ConfirmationToken)
+	}
+	assert.Len(t, mailServerTestInstance.Messages(), 2)
+}
+
+func TestNewsletterService_Deliver(t *testing.T) {
+	ctx := context.Background()
+	mailServerTestInstance.Reset()
+	emails := []string{"test-deliver-1@example.com", "test-deliver-2@example.com", "test-deliver-3@example.com", "test-deliver-4@example.com"}
+	defer deleteSubscribers(emails...)
+	subscribers := make([]entities.Subscriber, 0, len(emails))
+	for _, email := range emails {
+		subscriber, err := newsletterServiceTestInstance.Subscribe(ctx, email)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if subscriber, err = newsletterServiceTestInstance.Confirm(ctx, subscriber.ConfirmationToken); !assert.NoError(t, err) {
+			return
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	// The last one opts out after the post is queued.
+	mailServerTestInstance.Reset()
+	mailServerTestInstance.Reject(emails[1], 550)
+	mailServerTestInstance.Reject(emails[2], 451)
+
+	post := eposts.Post{Title: "test-deliver-title", Content: "test-content"}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, post.ID, eposts.AnyVersion)
+	_, err := newsletterServiceTestInstance.QueueDelivery(ctx, post.ID)
+	assert.ErrorIs(t, err, entities.ErrPostNotPublished)
+	if _, err = postsServiceTestInstance.PublishPost(ctx, post.ID, post.CreatedAt); !assert.NoError(t, err) {
+		return
+	}
+	queued, err := newsletterServiceTestInstance.QueueDelivery(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, queued, int64(len(emails)))
+	}
+	// Queueing twice doesn't send twice.
+	queued, err = newsletterServiceTestInstance.QueueDelivery(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Zero(t, queued)
+	}
+	_, err = newsletterServiceTestInstance.Unsubscribe(ctx, subscribers[3].UnsubscribeToken)
+	assert.NoError(t, err)
+
+	report, err := newsletterServiceTestInstance.Deliver(ctx, 1000)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, report.Sent, 1)
+		assert.Equal(t, 1, report.Bounced)
+		assert.Equal(t, 1, report.Retried)
+		assert.GreaterOrEqual(t, report.Skipped, 1)
+	}
+	var delivered []mailtest.Message
+	for _, message := range mailServerTestInstance.Messages() {
+		if message.To[0] == emails[0] {
+			delivered = append(delivered, message)
+		}
+	}
+	if assert.Len(t, delivered, 1) {
+		assert.True(t, strings.Contains(delivered[0].Data, "test-deliver-title"))
+		assert.True(t, strings.Contains(delivered[0].Data, "List-Unsubscribe: <https://example.com/subscribers/unsubscribe/"))
+	}
+
+	// Retries run out of attempts, the bounced subscriber is no longer active.
+	report, err = newsletterServiceTestInstance.Deliver(ctx, 1000)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, report.Failed)
+	}
+	stats, err := newsletterServiceTestInstance.DeliveryStats(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Zero(t, stats.Pending)
+		assert.Equal(t, int64(1), stats.Bounced)
+		assert.Equal(t, int64(1), stats.Failed)
+		assert.GreaterOrEqual(t, stats.Skipped, int64(1))
+	}
+	bounced, err := newsletterServiceTestInstance.RecordBounce(ctx, emails[1])
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, bounced.Bounces)
+		assert.Equal(t, entities.SubscriberBounced, bounced.Status)
+	}
+	_, err = newsletterServiceTestInstance.RecordBounce(ctx, "test-deliver-missing@example.com")
+	assert.ErrorIs(t, err, entities.ErrSubscriberNotFound)
+
+	// Deliveries left sending by a run that died fail, rather than being sent twice.
+	mailServerTestInstance.Reset()
+	err = writerTestInstance.Model(&entities.Delivery{}).
+		Where("post_id = ? AND status = ?", post.ID, entities.DeliverySent).
+		Updates(map[string]interface{}{
+			"status":          entities.DeliverySending,
+			"next_attempt_at": time.Now().UTC().Add(-time.Minute),
+		}).Error
+	if !assert.NoError(t, err) {
+		return
+	}
+	report, err = newsletterServiceTestInstance.Deliver(ctx, 1000)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, report.Failed, 1)
+		assert.Zero(t, report.Sent)
+	}
+	assert.Empty(t, mailServerTestInstance.Messages())
+	stats, err = newsletterServiceTestInstance.DeliveryStats(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Zero(t, stats.Sending)
+		assert.Zero(t, stats.Sent)
+	}
+}
+
+// deleteSubscribers cleans up subscribers created by a test.
+func deleteSubscribers(emails ...string) {
+	writerTestInstance.Where("email IN ?", emails).Delete(&entities.Subscriber{})
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	postsService, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	server, err := mailtest.NewServer("127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	sender, err := mail.NewSMTPSender(server.Addr, "", "")
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewNewsletterService(ctx, reader, writer, postsService, sender, testURLs{}, entities.Settings{
+		From:        "Newsletter <newsletter@example.com>",
+		MaxAttempts: 2,
+		MaxBounces:  2,
+		SendTimeout: time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+	newsletterServiceTestInstance = service
+	postsServiceTestInstance = postsService
+	mailServerTestInstance = server
+	writerTestInstance = writer
+	return func(ctx context.Context) { server.Close() }, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail"
+)
+
+var (
+	//go:embed templates
+	templateFiles embed.FS
+	// htmlTemplates and textTemplates render both versions of every email, named after their files.
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/*.html.tmpl"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt.tmpl"))
+)
+
+// postEmail is rendered by post templates.
+type postEmail struct {
+	Title string
+	// HTML is the sanitized rendering of the post, Text is its source.
+	HTML            htmltemplate.HTML
+	Text            string
+	Link            string
+	UnsubscribeLink string
+}
+
+// confirmationEmail is rendered by confirmation templates.
+type confirmationEmail struct {
+	Link string
+}
+
+// render renders both versions of the email called name with data.
+func render(name string, subject string, data interface{}) (mail.Message, error) {
+	var html, text bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return mail.Message{}, err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{Subject: subject, HTML: html.String(), Text: text.String()}, nil
+}
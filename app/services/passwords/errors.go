@@ -0,0 +1,14 @@
+package passwords
+
+import (
+	"errors"
+)
+
+var (
+	// ErrMalformedHash is thrown when a stored hash can't be parsed.
+	ErrMalformedHash = errors.New("malformed password hash")
+	// ErrUnsupportedHash is thrown when a stored hash uses an unknown algorithm or version.
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+	// ErrInvalidPassword is thrown when a password is shorter than MinLength or longer than MaxLength.
+	ErrInvalidPassword = errors.New("password has to be between 8 and 256 characters long")
+)
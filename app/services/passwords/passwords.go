@@ -0,0 +1,113 @@
+// Package passwords hashes passwords with argon2id, and verifies argon2id as well as legacy bcrypt hashes.
+package passwords
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinLength and MaxLength bound the number of characters in a password.
+	MinLength = 8
+	MaxLength = 256
+)
+
+// Params are argon2id cost parameters, see RFC 9106 for recommendations.
+type Params struct {
+	// Memory is in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follow the second recommended option of RFC 9106.
+var DefaultParams = Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 16, KeyLength: 32}
+
+// Validate checks whether a password is acceptable.
+func Validate(password string) error {
+	if n := utf8.RuneCountInString(password); n < MinLength || n > MaxLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// Hash hashes a password with params, into the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>.
+func Hash(password string, params Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.Memory, params.Iterations,
+		params.Parallelism, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// Verify checks a password against a stored hash. It also reports whether the hash should be replaced with a fresh
+// one of params, because it is a legacy bcrypt hash or was computed with different parameters, which is only
+// possible right after a successful verification, while the password is known.
+func Verify(password string, hash string, params Params) (ok bool, rehash bool, err error) {
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, false, nil
+		}
+		return err == nil, err == nil, err
+	}
+	stored, salt, key, err := decode(hash)
+	if err != nil {
+		return false, false, err
+	}
+	computed := argon2.IDKey([]byte(password), salt, stored.Iterations, stored.Memory, stored.Parallelism, stored.KeyLength)
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, false, nil
+	}
+	return true, stored != params, nil
+}
+
+// encoding is the unpadded base64 of the PHC string format.
+var encoding = base64.RawStdEncoding
+
+// decode parses an argon2id hash in the PHC string format.
+func decode(hash string) (Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) < 2 || parts[0] != "" {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	if parts[1] != "argon2id" {
+		return Params{}, nil, nil, ErrUnsupportedHash
+	}
+	if len(parts) != 6 {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	if version != argon2.Version {
+		return Params{}, nil, nil, ErrUnsupportedHash
+	}
+	var params Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	salt, err := encoding.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	key, err := encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+	params.SaltLength, params.KeyLength = uint32(len(salt)), uint32(len(key))
+	return params, salt, key, nil
+}
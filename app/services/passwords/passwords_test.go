@@ -0,0 +1,77 @@
+package passwords
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keep tests fast.
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("password"))
+	assert.NoError(t, Validate(strings.Repeat("ü", MaxLength)))
+	assert.ErrorIs(t, Validate("short"), ErrInvalidPassword)
+	assert.ErrorIs(t, Validate(strings.Repeat("x", MaxLength+1)), ErrInvalidPassword)
+}
+
+func TestHash(t *testing.T) {
+	first, err := Hash("password", testParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(first, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	// Salts differ.
+	second, err := Hash("password", testParams)
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, first, second)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	hash, err := Hash("password", testParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if !assert.NoError(t, err) {
+		return
+	}
+	upgraded := testParams
+	upgraded.Iterations = 2
+
+	cases := []struct {
+		password string
+		hash     string
+		params   Params
+		ok       bool
+		rehash   bool
+		err      error
+	}{
+		{password: "password", hash: hash, params: testParams, ok: true},
+		{password: "wrong-password", hash: hash, params: testParams},
+		// Parameters changed since the hash was computed.
+		{password: "password", hash: hash, params: upgraded, ok: true, rehash: true},
+		{password: "wrong-password", hash: hash, params: upgraded},
+		// Legacy hashes are always upgraded.
+		{password: "password", hash: string(legacy), params: testParams, ok: true, rehash: true},
+		{password: "wrong-password", hash: string(legacy), params: testParams},
+		{password: "password", hash: "", params: testParams, err: ErrMalformedHash},
+		{password: "password", hash: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$", params: testParams, err: ErrMalformedHash},
+		{password: "password", hash: "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5", params: testParams, err: ErrUnsupportedHash},
+		{password: "password", hash: "$scrypt$ln=16,r=8,p=1$c2FsdA$a2V5", params: testParams, err: ErrUnsupportedHash},
+	}
+	for i, c := range cases {
+		ok, rehash, err := Verify(c.password, c.hash, c.params)
+		assert.Equal(t, c.ok, ok, i)
+		assert.Equal(t, c.rehash, rehash, i)
+		if c.err != nil {
+			assert.ErrorIs(t, err, c.err, i)
+		} else {
+			assert.NoError(t, err, i)
+		}
+	}
+}
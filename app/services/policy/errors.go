@@ -0,0 +1,12 @@
+package policy
+
+import (
+	"errors"
+)
+
+var (
+	// ErrForbidden is thrown when a user isn't allowed to perform an action.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidRole is thrown when a role isn't one of Roles.
+	ErrInvalidRole = errors.New("role has to be one of admin, editor, author or reader")
+)
@@ -0,0 +1,127 @@
+// Package policy decides what users are allowed to do, based on their role and on who owns the resources they act on.
+package policy
+
+import (
+	"fmt"
+
+	helpers "github.com/samber/lo"
+)
+
+// Role is a set of permissions granted to a user.
+type Role string
+
+// Roles, from the most to the least privileged.
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleReader Role = "reader"
+)
+
+// Roles lists all the roles.
+var Roles = []Role{RoleAdmin, RoleEditor, RoleAuthor, RoleReader}
+
+// Validate checks whether a role is one of Roles.
+func (r Role) Validate() error {
+	if !helpers.Contains(Roles, r) {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+// Grant describes which resources a permission applies to.
+type Grant int
+
+const (
+	// Own grants a permission on resources owned by the user only.
+	Own Grant = iota + 1
+	// Any grants a permission on every resource.
+	Any
+)
+
+// Actions users can be granted.
+const (
+	PostsCreate      = "posts.create"
+	PostsUpdate      = "posts.update"
+	PostsDelete      = "posts.delete"
+	PostsPublish     = "posts.publish"
+	PostsExport      = "posts.export"
+	PostsImport      = "posts.import"
+	TagsManage       = "tags.manage"
+	NewsletterManage = "newsletter.manage"
+	UsersUpdate      = "users.update"
+	UsersDelete      = "users.delete"
+	UsersRoles       = "users.roles"
+)
+
+// Permissions maps roles to the actions they're granted. Every role is granted what the less privileged ones are,
+// admins are granted every action.
+var Permissions = func() map[Role]map[string]Grant {
+	reader := map[string]Grant{
+		UsersUpdate: Own,
+		UsersDelete: Own,
+	}
+	author := helpers.Assign(reader, map[string]Grant{
+		PostsCreate:  Own,
+		PostsUpdate:  Own,
+		PostsDelete:  Own,
+		PostsPublish: Own,
+		PostsExport:  Own,
+	})
+	editor := helpers.Assign(author, map[string]Grant{
+		PostsCreate:      Any,
+		PostsUpdate:      Any,
+		PostsDelete:      Any,
+		PostsPublish:     Any,
+		PostsExport:      Any,
+		PostsImport:      Any,
+		TagsManage:       Any,
+		NewsletterManage: Any,
+	})
+	admin := helpers.Assign(editor, map[string]Grant{
+		UsersUpdate: Any,
+		UsersDelete: Any,
+		UsersRoles:  Any,
+	})
+	return map[Role]map[string]Grant{
+		RoleAdmin:  admin,
+		RoleEditor: editor,
+		RoleAuthor: author,
+		RoleReader: reader,
+	}
+}()
+
+// User is who asks for a permission, the zero User is anonymous and isn't granted anything.
+type User struct {
+	ID   uint64
+	Role Role
+}
+
+// Owned is implemented by resources that belong to a user, so that Own grants can apply to them.
+type Owned interface {
+	OwnerID() uint64
+}
+
+// Can checks whether user is allowed to perform action on resource. Resources that aren't Owned are only covered by
+// Any grants, resource may be nil for actions that don't apply to one.
+func Can(user User, action string, resource interface{}) bool {
+	if user.ID == 0 {
+		return false
+	}
+	switch Permissions[user.Role][action] {
+	case Any:
+		return true
+	case Own:
+		owned, ok := resource.(Owned)
+		return ok && owned.OwnerID() == user.ID
+	}
+	return false
+}
+
+// Authorize is like Can, but throws ErrForbidden wrapped with the action that isn't allowed.
+func Authorize(user User, action string, resource interface{}) error {
+	if !Can(user, action, resource) {
+		return fmt.Errorf("%w: %v isn't allowed", ErrForbidden, action)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// post is a resource owned by a user.
+type post struct {
+	userID uint64
+}
+
+// OwnerID ...
+func (p post) OwnerID() uint64 {
+	return p.userID
+}
+
+func TestCan(t *testing.T) {
+	own, other := post{userID: 1}, post{userID: 2}
+	cases := []struct {
+		user     User
+		action   string
+		resource interface{}
+		allowed  bool
+	}{
+		// Anonymous.
+		{user: User{}, action: PostsCreate, resource: own, allowed: false},
+		// Unknown role.
+		{user: User{ID: 1, Role: "owner"}, action: PostsUpdate, resource: own, allowed: false},
+		// Unknown action.
+		{user: User{ID: 1, Role: RoleAdmin}, action: "posts.destroy", resource: own, allowed: false},
+		// Readers can't write posts.
+		{user: User{ID: 1, Role: RoleReader}, action: PostsUpdate, resource: own, allowed: false},
+		{user: User{ID: 1, Role: RoleReader}, action: UsersUpdate, resource: own, allowed: true},
+		// Authors only write their own posts.
+		{user: User{ID: 1, Role: RoleAuthor}, action: PostsUpdate, resource: own, allowed: true},
+		{user: User{ID: 1, Role: RoleAuthor}, action: PostsUpdate, resource: other, allowed: false},
+		{user: User{ID: 1, Role: RoleAuthor}, action: PostsDelete, resource: nil, allowed: false},
+		{user: User{ID: 1, Role: RoleAuthor}, action: TagsManage, resource: nil, allowed: false},
+		{user: User{ID: 1, Role: RoleAuthor}, action: PostsExport, resource: nil, allowed: false},
+		{user: User{ID: 1, Role: RoleReader}, action: PostsExport, resource: own, allowed: false},
+		// Editors write every post, but don't manage users.
+		{user: User{ID: 1, Role: RoleEditor}, action: PostsUpdate, resource: other, allowed: true},
+		{user: User{ID: 1, Role: RoleEditor}, action: TagsManage, resource: nil, allowed: true},
+		{user: User{ID: 1, Role: RoleEditor}, action: PostsExport, resource: nil, allowed: true},
+		{user: User{ID: 1, Role: RoleEditor}, action: UsersDelete, resource: other, allowed: false},
+		// Admins do everything.
+		{user: User{ID: 1, Role: RoleAdmin}, action: PostsDelete, resource: other, allowed: true},
+		{user: User{ID: 1, Role: RoleAdmin}, action: UsersRoles, resource: other, allowed: true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.allowed, Can(c.user, c.action, c.resource), "%v %v %v", c.user.Role, c.action, c.resource)
+		err := Authorize(c.user, c.action, c.resource)
+		if c.allowed {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, ErrForbidden)
+		}
+	}
+}
+
+func TestRole_Validate(t *testing.T) {
+	for _, role := range Roles {
+		assert.NoError(t, role.Validate())
+	}
+	assert.ErrorIs(t, Role("").Validate(), ErrInvalidRole)
+	assert.ErrorIs(t, Role("Admin").Validate(), ErrInvalidRole)
+}
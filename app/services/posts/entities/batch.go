@@ -0,0 +1,13 @@
+package entities
+
+// MaxBatchSize is the biggest number of posts a single batch write can contain.
+const MaxBatchSize = 1000
+
+// BatchResult is an outcome of a single item of a batch write, results are in the order items were provided.
+type BatchResult struct {
+	// Post is the written post, or the provided one if the write failed.
+	Post Post
+	// Err is an error the write failed with, same as the one thrown by a single item write.
+	// Items of a failed all-or-nothing batch that were fine on their own fail with ErrBatchAborted.
+	Err error
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// Comment represents a comment left under a newsletter post.
+type Comment struct {
+	ID        CommentID `json:"id" gorm:"column:id; primary_key:yes"`
+	PostID    PostID    `json:"post_id" gorm:"column:post_id"`
+	Content   string    `json:"content" gorm:"column:content"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// JSONAPIType ...
+func (Comment) JSONAPIType() string {
+	return "comments"
+}
+
+// JSONAPIID ...
+func (c Comment) JSONAPIID() string {
+	return strconv.FormatUint(uint64(c.ID), 10)
+}
+
+// TableName ...
+func (Comment) TableName() string {
+	return "comments"
+}
@@ -9,8 +9,40 @@ var (
 	ErrNilDB = errors.New("db connection is nil")
 	// ErrPostNotFound is thrown when a post is fetched for an invalid id.
 	ErrPostNotFound = errors.New("no post found with provided id")
+	// ErrSlugNotFound is thrown when a post is fetched for a slug that is neither current nor former slug of any post.
+	ErrSlugNotFound = errors.New("no post found with provided slug")
 	// ErrDuplicatePost is thrown when a post with conflicting id is created.
 	ErrDuplicatePost = errors.New("post id already exists")
 	// ErrInvalidTitle is thrown when title is empty or longer than 255 characters.
 	ErrInvalidTitle = errors.New("title has to be a non-empty string of 255 characters or less")
+	// ErrVersionMismatch is thrown when a post was modified since the version a write was based on.
+	ErrVersionMismatch = errors.New("post has been modified since it was fetched")
+	// ErrInvalidTransition is thrown when a post can't move from its current status to the requested one.
+	ErrInvalidTransition = errors.New("post can't be moved to requested status")
+	// ErrRevisionNotFound is thrown when a revision is fetched for an invalid id, or for a different post.
+	ErrRevisionNotFound = errors.New("no revision found with provided id")
+	// ErrTagNotFound is thrown when a tag is fetched, or assigned to a post, for an invalid id or name.
+	ErrTagNotFound = errors.New("no tag found with provided id or name")
+	// ErrDuplicateTag is thrown when a tag with conflicting name is created.
+	ErrDuplicateTag = errors.New("tag name already exists")
+	// ErrInvalidTagName is thrown when tag name is empty, padded with spaces or longer than 64 characters.
+	ErrInvalidTagName = errors.New("tag name has to be a non-empty string of 64 characters or less")
+	// ErrInvalidBatchSize is thrown when a batch write is empty or bigger than MaxBatchSize.
+	ErrInvalidBatchSize = errors.New("batch has to contain between 1 and 1000 posts")
+	// ErrBatchAborted is thrown for items of an all-or-nothing batch write that was rolled back because of other items.
+	ErrBatchAborted = errors.New("batch was rolled back because another item failed")
+	// ErrUnknownTransferFormat is thrown when posts are exported to or imported from an unsupported file format.
+	ErrUnknownTransferFormat = errors.New("format has to be one of csv, json or ndjson")
+	// ErrUnknownImportMode is thrown when imported posts are matched in an unsupported way.
+	ErrUnknownImportMode = errors.New("mode has to be one of id or slug")
+	// ErrInvalidSlug is thrown when an imported slug isn't one a title could produce.
+	ErrInvalidSlug = errors.New("slug has to be lowercase letters and digits separated by single hyphens")
+	// ErrUnknownColumn is thrown when a CSV column doesn't map to any of TransferColumns.
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrMalformedRecord is thrown when an imported record can't be read into a post.
+	ErrMalformedRecord = errors.New("malformed record")
+	// ErrInvalidStatus is thrown when an imported post has an unknown status.
+	ErrInvalidStatus = errors.New("status has to be one of draft, scheduled, published or archived")
+	// ErrEmptySearchQuery is thrown when a search query has no searchable terms.
+	ErrEmptySearchQuery = errors.New("search query has to contain at least one word")
 )
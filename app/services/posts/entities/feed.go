@@ -0,0 +1,11 @@
+package entities
+
+import (
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// FeedFilter narrows a feed down to posts of an author, or posts with a tag, zero values don't filter.
+type FeedFilter struct {
+	UserID userentities.UserID
+	Tag    string
+}
@@ -1,8 +1,12 @@
 package entities
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
 	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
 
 	"github.com/pkg/errors"
@@ -16,6 +20,38 @@ type Post struct {
 	Content   string              `json:"content" gorm:"column:content"`
 	UpdatedAt time.Time           `json:"updated_at" gorm:"column:updated_at"`
 	CreatedAt time.Time           `json:"created_at" gorm:"column:created_at"`
+	Version   Version             `json:"-" gorm:"column:version; default:1"`
+
+	// Slug is derived from the title by the server, former slugs keep resolving to the post.
+	Slug string `json:"slug" gorm:"column:slug"`
+
+	// Format is the markup language of Content, the rest is rendered from it by the server.
+	Format      markup.Format `json:"format" gorm:"column:format; default:plain"`
+	ContentHTML string        `json:"content_html,omitempty" gorm:"column:content_html"`
+	Excerpt     string        `json:"excerpt,omitempty" gorm:"column:excerpt"`
+	ReadingTime int           `json:"reading_time,omitempty" gorm:"column:reading_time"`
+
+	// Lifecycle, managed by the server.
+	Status      Status     `json:"status" gorm:"column:status; default:draft"`
+	PublishedAt *time.Time `json:"published_at" gorm:"column:published_at"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty" gorm:"column:scheduled_at"`
+
+	// Related resources, only populated when explicitly included.
+	User     *userentities.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Comments []Comment          `json:"comments,omitempty" gorm:"foreignKey:PostID"`
+
+	// Tags are assigned by clients, nil keeps current tags on update.
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:post_tags"`
+}
+
+// JSONAPIType ...
+func (Post) JSONAPIType() string {
+	return "posts"
+}
+
+// JSONAPIID ...
+func (p Post) JSONAPIID() string {
+	return strconv.FormatUint(uint64(p.ID), 10)
 }
 
 // TableName ...
@@ -23,6 +59,30 @@ func (Post) TableName() string {
 	return "posts"
 }
 
+// OwnerID implements policy.Owned.
+func (p Post) OwnerID() uint64 {
+	return uint64(p.UserID)
+}
+
+// ETag returns a strong entity tag of the current post version.
+func (p Post) ETag() string {
+	return fmt.Sprintf("\"%v-%v\"", p.ID, p.Version)
+}
+
+// ParseETag extracts a version out of an entity tag produced by Post.ETag, returns false if tag is malformed.
+// Tags qualified with a representation describe the same version.
+func ParseETag(id PostID, tag string) (Version, bool) {
+	if i := strings.IndexByte(tag, ';'); i >= 0 && strings.HasSuffix(tag, "\"") {
+		tag = tag[:i] + "\""
+	}
+	var tagID PostID
+	var version Version
+	if _, err := fmt.Sscanf(tag, "\"%d-%d\"", &tagID, &version); err != nil || tagID != id || version == AnyVersion {
+		return AnyVersion, false
+	}
+	return version, true
+}
+
 // Validate checks whether a given Post object is valid.
 func (p Post) Validate() error {
 	errs := make([]error, 0)
@@ -31,6 +91,10 @@ func (p Post) Validate() error {
 		errs = append(errs, ErrInvalidTitle)
 	}
 
+	if p.Format != "" && !p.Format.Valid() {
+		errs = append(errs, markup.ErrUnknownFormat)
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
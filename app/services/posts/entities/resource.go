@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+)
+
+var (
+	// timeOperators are comparisons allowed for timestamps.
+	timeOperators = []query.Operator{
+		query.OperatorEq, query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte,
+	}
+	// PostResource whitelists fields and related resources clients can request for posts.
+	PostResource = query.Resource{
+		Fields: map[string]string{
+			"id":           "id",
+			"user_id":      "user_id",
+			"title":        "title",
+			"slug":         "slug",
+			"content":      "content",
+			"format":       "format",
+			"content_html": "content_html",
+			"excerpt":      "excerpt",
+			"reading_time": "reading_time",
+			"updated_at":   "updated_at",
+			"created_at":   "created_at",
+			"status":       "status",
+			"published_at": "published_at",
+			"scheduled_at": "scheduled_at",
+		},
+		Includes: map[string]query.Include{
+			"comments": {Association: "Comments", Requires: []string{"id"}},
+			"user":     {Association: "User", Requires: []string{"user_id"}},
+			"tags":     {Association: "Tags", Requires: []string{"id"}},
+		},
+		Filterable: map[string]query.Filter{
+			"id":           {Type: query.TypeInteger, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"user_id":      {Type: query.TypeInteger, Operators: []query.Operator{query.OperatorEq, query.OperatorNe, query.OperatorIn}},
+			"title":        {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorContains}},
+			"slug":         {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"created_at":   {Type: query.TypeTime, Operators: timeOperators},
+			"updated_at":   {Type: query.TypeTime, Operators: timeOperators},
+			"status":       {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorNe, query.OperatorIn}},
+			"format":       {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"published_at": {Type: query.TypeTime, Operators: timeOperators},
+			"tag": {
+				Type:      query.TypeString,
+				Operators: []query.Operator{query.OperatorEq, query.OperatorIn},
+				Column:    "tags.name",
+				Wrap:      "id IN (SELECT post_tags.post_id FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE %v)",
+			},
+		},
+		Sortable:    []string{"id", "title", "created_at", "updated_at"},
+		DefaultSort: []query.Sort{{Field: "created_at", Desc: true}},
+	}
+	// SearchResultResource whitelists fields clients can request for search results.
+	SearchResultResource = query.Resource{
+		Fields: map[string]string{
+			"id":              "id",
+			"user_id":         "user_id",
+			"title":           "title",
+			"content":         "content",
+			"updated_at":      "updated_at",
+			"created_at":      "created_at",
+			"status":          "status",
+			"published_at":    "published_at",
+			"rank":            "rank",
+			"title_highlight": "title_highlight",
+			"snippet":         "snippet",
+		},
+		Filterable: map[string]query.Filter{
+			"user_id":    {Type: query.TypeInteger, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"created_at": {Type: query.TypeTime, Operators: timeOperators},
+		},
+		DefaultSort: []query.Sort{{Field: "rank", Desc: true}},
+	}
+)
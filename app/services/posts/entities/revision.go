@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// Revision is a snapshot of a post taken after every edit.
+type Revision struct {
+	ID       RevisionID           `json:"id" gorm:"column:id; primary_key:yes"`
+	PostID   PostID               `json:"post_id" gorm:"column:post_id"`
+	Version  Version              `json:"version" gorm:"column:version"`
+	AuthorID *userentities.UserID `json:"author_id" gorm:"column:author_id"`
+	// Snapshot of the post.
+	UserID    userentities.UserID `json:"user_id" gorm:"column:user_id"`
+	Title     string              `json:"title" gorm:"column:title"`
+	Content   string              `json:"content" gorm:"column:content"`
+	Format    markup.Format       `json:"format" gorm:"column:format"`
+	Tags      []Tag               `json:"tags,omitempty" gorm:"many2many:post_revision_tags"`
+	CreatedAt time.Time           `json:"created_at" gorm:"column:created_at"`
+}
+
+// JSONAPIType ...
+func (Revision) JSONAPIType() string {
+	return "revisions"
+}
+
+// JSONAPIID ...
+func (r Revision) JSONAPIID() string {
+	return strconv.FormatUint(uint64(r.ID), 10)
+}
+
+// TableName ...
+func (Revision) TableName() string {
+	return "post_revisions"
+}
+
+// NewRevision takes a snapshot of a post edited by author, 0 author stands for an anonymous edit.
+// Tags of the post have to be loaded.
+func NewRevision(post Post, author userentities.UserID) Revision {
+	revision := Revision{
+		PostID:  post.ID,
+		Version: post.Version,
+		UserID:  post.UserID,
+		Title:   post.Title,
+		Content: post.Content,
+		Format:  post.Format,
+		Tags:    post.Tags,
+	}
+	if author != 0 {
+		revision.AuthorID = &author
+	}
+	return revision
+}
+
+// Text renders the snapshot as a text document, used to diff revisions.
+func (r Revision) Text() string {
+	tags := make([]string, 0, len(r.Tags))
+	for _, tag := range r.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return fmt.Sprintf("title: %v\nuser_id: %v\nformat: %v\ntags: %v\n\n%v",
+		r.Title, r.UserID, r.Format, strings.Join(tags, ", "), r.Content)
+}
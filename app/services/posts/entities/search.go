@@ -0,0 +1,12 @@
+package entities
+
+// SearchResult is a post matching a full-text search query.
+type SearchResult struct {
+	Post
+	// Rank is the relevance of the post, title matches weigh more than content matches.
+	Rank float64 `json:"rank" gorm:"column:rank"`
+	// TitleHighlight is the HTML-escaped title with matches wrapped in <mark> tags.
+	TitleHighlight string `json:"title_highlight" gorm:"column:title_highlight"`
+	// Snippet is an HTML-escaped fragment of the content around the best matches, wrapped in <mark> tags.
+	Snippet string `json:"snippet" gorm:"column:snippet"`
+}
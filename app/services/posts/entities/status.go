@@ -0,0 +1,11 @@
+package entities
+
+// Status is a stage of a post lifecycle, only published posts are visible to everyone.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusScheduled Status = "scheduled"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
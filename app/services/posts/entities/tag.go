@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a label posts can be grouped by.
+type Tag struct {
+	ID        TagID     `json:"id" gorm:"column:id; primary_key:yes"`
+	Name      string    `json:"name" gorm:"column:name"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// JSONAPIType ...
+func (Tag) JSONAPIType() string {
+	return "tags"
+}
+
+// JSONAPIID ...
+func (t Tag) JSONAPIID() string {
+	return strconv.FormatUint(uint64(t.ID), 10)
+}
+
+// TableName ...
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// Validate checks whether a given Tag object is valid.
+func (t Tag) Validate() error {
+	if name := strings.TrimSpace(t.Name); name == "" || len(name) > 64 || name != t.Name {
+		return ErrInvalidTagName
+	}
+	return nil
+}
+
+// TagCount is a tag with the number of published posts it's assigned to.
+type TagCount struct {
+	Tag
+	Count int64 `json:"count" gorm:"column:count"`
+}
@@ -0,0 +1,83 @@
+package entities
+
+// TransferFormat is a file format posts are exported to and imported from.
+type TransferFormat string
+
+const (
+	TransferCSV    TransferFormat = "csv"
+	TransferJSON   TransferFormat = "json"
+	TransferNDJSON TransferFormat = "ndjson"
+)
+
+// ImportMode decides how imported posts are matched with stored ones, matched posts are updated and the rest created.
+type ImportMode string
+
+const (
+	// ImportByID matches posts by id, new posts keep their ids, so the same content has the same ids everywhere.
+	ImportByID ImportMode = "id"
+	// ImportBySlug matches posts by current or former slug, new posts get fresh ids.
+	ImportBySlug ImportMode = "slug"
+)
+
+// TransferColumns lists post fields CSV files can contain, exports contain all of them in this order by default.
+// Tags are joined with TagSeparator, timestamps are in RFC 3339 format.
+var TransferColumns = []string{
+	"id", "slug", "user_id", "title", "content", "format", "status", "published_at", "scheduled_at", "tags",
+	"created_at", "updated_at",
+}
+
+// TagSeparator separates tag names in the tags column of CSV files.
+const TagSeparator = "|"
+
+// ContentType returns the media type of files in f.
+func (f TransferFormat) ContentType() string {
+	switch f {
+	case TransferCSV:
+		return "text/csv; charset=utf-8"
+	case TransferNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+// Valid checks whether posts can be transferred in f.
+func (f TransferFormat) Valid() bool {
+	return f == TransferCSV || f == TransferJSON || f == TransferNDJSON
+}
+
+// Valid checks whether posts can be matched in m.
+func (m ImportMode) Valid() bool {
+	return m == ImportByID || m == ImportBySlug
+}
+
+// ImportOptions configures an import.
+type ImportOptions struct {
+	Format TransferFormat
+	Mode   ImportMode
+	// Columns maps CSV headers to TransferColumns, headers that are column names already don't have to be mapped,
+	// and headers mapped to "-" are ignored.
+	Columns map[string]string
+	// DryRun validates records and reports what would have been written, without writing anything.
+	DryRun bool
+	// Progress, if set, is called with the report so far every ImportProgressInterval records.
+	Progress func(report ImportReport)
+}
+
+// ImportProgressInterval is the number of records between two ImportOptions.Progress calls.
+const ImportProgressInterval = 100
+
+// ImportReport summarizes an import.
+type ImportReport struct {
+	Records int           `json:"records"`
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Failed  int           `json:"failed"`
+	DryRun  bool          `json:"dry_run"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError describes a record that failed to import, records are numbered from 1 in the order they were read.
+type ImportError struct {
+	Record int    `json:"record"`
+	Error  string `json:"error"`
+}
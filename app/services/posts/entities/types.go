@@ -1,5 +1,22 @@
 package entities
 
+import (
+	"math"
+
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
 type (
-	PostID uint32
+	PostID     uint32
+	CommentID  uint32
+	RevisionID uint32
+	TagID      uint32
+	Version    uint32
+)
+
+const (
+	// AnyVersion disables version checks of write operations.
+	AnyVersion Version = 0
+	// AnyViewer sees every post, drafts of other users included.
+	AnyViewer userentities.UserID = math.MaxUint32
 )
@@ -2,15 +2,43 @@ package interfaces
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
 )
 
 // PostsService is an interface that is used by outside packages to interact with posts.
 type PostsService interface {
-	IndexPosts(ctx context.Context) ([]entities.Post, error)
+	IndexPosts(ctx context.Context, viewer userentities.UserID, opts query.Options) ([]entities.Post, query.PageInfo, error)
+	SearchPosts(ctx context.Context, viewer userentities.UserID, q string, opts query.Options) ([]entities.SearchResult, query.PageInfo, error)
 	FindPost(ctx context.Context, id entities.PostID) (entities.Post, error)
-	UpdatePost(ctx context.Context, post *entities.Post) error
-	CreatePost(ctx context.Context, post *entities.Post) error
-	DeletePost(ctx context.Context, id entities.PostID) error
+	FindPostBySlug(ctx context.Context, slug string) (entities.Post, error)
+	UpdatePost(ctx context.Context, post *entities.Post, author userentities.UserID) error
+	PatchPost(ctx context.Context, id entities.PostID, version entities.Version, author userentities.UserID, patch func(post *entities.Post) error) (entities.Post, error)
+	CreatePost(ctx context.Context, post *entities.Post, author userentities.UserID) error
+	DeletePost(ctx context.Context, id entities.PostID, version entities.Version) error
+	CreatePosts(ctx context.Context, posts []entities.Post, author userentities.UserID, atomic bool) ([]entities.BatchResult, error)
+	UpdatePosts(ctx context.Context, posts []entities.Post, author userentities.UserID, atomic bool) ([]entities.BatchResult, error)
+	DeletePosts(ctx context.Context, posts []entities.Post, atomic bool) ([]entities.BatchResult, error)
+	ExportPosts(ctx context.Context, w io.Writer, format entities.TransferFormat, columns []string, viewer userentities.UserID, opts query.Options) (int, error)
+	ImportPosts(ctx context.Context, r io.Reader, opts entities.ImportOptions, author userentities.UserID) (entities.ImportReport, error)
+	FeedPosts(ctx context.Context, filter entities.FeedFilter, limit int) ([]entities.Post, time.Time, error)
+	SitemapPages(ctx context.Context, size int) ([]time.Time, error)
+	SitemapPosts(ctx context.Context, page, size int) ([]entities.Post, error)
+	PublishPost(ctx context.Context, id entities.PostID, at time.Time) (entities.Post, error)
+	UnpublishPost(ctx context.Context, id entities.PostID) (entities.Post, error)
+	ArchivePost(ctx context.Context, id entities.PostID) (entities.Post, error)
+	PublishScheduledPosts(ctx context.Context) (int64, error)
+	IndexRevisions(ctx context.Context, id entities.PostID) ([]entities.Revision, error)
+	DiffRevisions(ctx context.Context, id entities.PostID, from, to entities.RevisionID) (string, error)
+	FindRevision(ctx context.Context, id entities.PostID, revision entities.RevisionID) (entities.Revision, error)
+	RevertPost(ctx context.Context, id entities.PostID, revision entities.RevisionID, version entities.Version, author userentities.UserID) (entities.Post, error)
+	IndexTags(ctx context.Context) ([]entities.TagCount, error)
+	FindTag(ctx context.Context, id entities.TagID) (entities.Tag, error)
+	CreateTag(ctx context.Context, tag *entities.Tag) error
+	UpdateTag(ctx context.Context, tag *entities.Tag) error
+	DeleteTag(ctx context.Context, id entities.TagID) error
 }
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"gorm.io/gorm"
+)
+
+// CreatePosts creates posts on behalf of author in one transaction, like CreatePost does for each of them.
+// If atomic, either all posts are created or none of them, otherwise every post succeeds or fails on its own.
+// Throws entities.ErrInvalidBatchSize if posts is empty or bigger than entities.MaxBatchSize.
+func (s *PostsService) CreatePosts(ctx context.Context, posts []entities.Post, author userentities.UserID, atomic bool) ([]entities.BatchResult, error) {
+	return s.batch(posts, atomic, func(tx *gorm.DB, post *entities.Post) error {
+		return s.create(tx, post, author)
+	})
+}
+
+// UpdatePosts updates posts on behalf of author in one transaction, like UpdatePost does for each of them.
+// If atomic, either all posts are updated or none of them, otherwise every post succeeds or fails on its own.
+// Throws entities.ErrInvalidBatchSize if posts is empty or bigger than entities.MaxBatchSize.
+func (s *PostsService) UpdatePosts(ctx context.Context, posts []entities.Post, author userentities.UserID, atomic bool) ([]entities.BatchResult, error) {
+	return s.batch(posts, atomic, func(tx *gorm.DB, post *entities.Post) error {
+		return s.replace(tx, post, author)
+	})
+}
+
+// DeletePosts deletes posts identified by their ids in one transaction, like DeletePost does for each of them, unless
+// post.Version is entities.AnyVersion it has to match the stored version.
+// If atomic, either all posts are deleted or none of them, otherwise every post succeeds or fails on its own.
+// Throws entities.ErrInvalidBatchSize if posts is empty or bigger than entities.MaxBatchSize.
+func (s *PostsService) DeletePosts(ctx context.Context, posts []entities.Post, atomic bool) ([]entities.BatchResult, error) {
+	return s.batch(posts, atomic, func(tx *gorm.DB, post *entities.Post) error {
+		return s.delete(tx, post.ID, post.Version)
+	})
+}
+
+// batch runs write for every post in one transaction. Every write runs in a nested transaction, so a failed one leaves
+// no trace, and the rest of the batch goes on to report errors of all items. An atomic batch is rolled back as a whole
+// if any of the writes failed.
+func (s *PostsService) batch(posts []entities.Post, atomic bool, write func(tx *gorm.DB, post *entities.Post) error) ([]entities.BatchResult, error) {
+	if len(posts) == 0 || len(posts) > entities.MaxBatchSize {
+		return nil, entities.ErrInvalidBatchSize
+	}
+	results := make([]entities.BatchResult, len(posts))
+	failed := false
+	err := s.writer.Transaction(func(tx *gorm.DB) error {
+		for i := range posts {
+			results[i].Post = posts[i]
+			results[i].Err = tx.Transaction(func(item *gorm.DB) error {
+				return write(item, &results[i].Post)
+			})
+			if results[i].Err != nil {
+				results[i].Post = posts[i]
+				failed = true
+			}
+		}
+		if atomic && failed {
+			return entities.ErrBatchAborted
+		}
+		return nil
+	})
+	if errors.Is(err, entities.ErrBatchAborted) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = entities.BatchResult{Post: posts[i], Err: entities.ErrBatchAborted}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
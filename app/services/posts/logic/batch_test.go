@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_Batch(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := postsServiceTestInstance.CreatePosts(ctx, nil, 0, true)
+	assert.ErrorIs(t, err, entities.ErrInvalidBatchSize)
+
+	// An atomic batch with an invalid item writes nothing.
+	results, err := postsServiceTestInstance.CreatePosts(ctx, []entities.Post{
+		{Title: "test-title-1", Content: "test-content"},
+		{Title: "", Content: "test-content"},
+	}, 0, true)
+	if assert.NoError(t, err) && assert.Len(t, results, 2) {
+		assert.ErrorIs(t, results[0].Err, entities.ErrBatchAborted)
+		assert.ErrorIs(t, results[1].Err, entities.ErrInvalidTitle)
+		assert.Zero(t, results[0].Post.ID)
+	}
+
+	// A partial batch writes valid items.
+	results, err = postsServiceTestInstance.CreatePosts(ctx, []entities.Post{
+		{Title: "test-title-1", Content: "test-content"},
+		{Title: "", Content: "test-content"},
+		{Title: "test-title-3", Content: "test-content"},
+	}, 0, false)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 3) {
+		return
+	}
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, entities.ErrInvalidTitle)
+	assert.NoError(t, results[2].Err)
+	first, third := results[0].Post, results[2].Post
+	defer postsServiceTestInstance.DeletePost(ctx, first.ID, entities.AnyVersion)
+	defer postsServiceTestInstance.DeletePost(ctx, third.ID, entities.AnyVersion)
+	found, err := postsServiceTestInstance.FindPost(ctx, third.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "test-title-3", found.Title)
+	}
+
+	// Updates are conditioned on versions like single updates.
+	first.Title = "test-title-1-new"
+	third.Title = "test-title-3-new"
+	third.Version++
+	results, err = postsServiceTestInstance.UpdatePosts(ctx, []entities.Post{first, third}, 0, false)
+	if assert.NoError(t, err) && assert.Len(t, results, 2) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "test-title-1-new", results[0].Post.Title)
+		assert.ErrorIs(t, results[1].Err, entities.ErrVersionMismatch)
+	}
+
+	// Deletes.
+	results, err = postsServiceTestInstance.DeletePosts(ctx, []entities.Post{{ID: first.ID}, {ID: third.ID}}, true)
+	if assert.NoError(t, err) && assert.Len(t, results, 2) {
+		assert.NoError(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+	}
+	_, err = postsServiceTestInstance.FindPost(ctx, first.ID)
+	assert.ErrorIs(t, err, entities.ErrPostNotFound)
+}
@@ -0,0 +1,308 @@
+package logic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	helpers "github.com/samber/lo"
+)
+
+// postEncoder writes posts to a file, Close has to be called once all of them are written.
+type postEncoder interface {
+	Encode(post entities.Post) error
+	Close() error
+}
+
+// postDecoder reads posts from a file, io.EOF is thrown once all of them are read.
+// Errors wrapping entities.ErrMalformedRecord only affect the returned record, reading can go on.
+type postDecoder interface {
+	Decode() (entities.Post, error)
+}
+
+// newEncoder instantiates an encoder of format, columns are only used by CSV and default to entities.TransferColumns.
+func newEncoder(w io.Writer, format entities.TransferFormat, columns []string) (postEncoder, error) {
+	switch format {
+	case entities.TransferCSV:
+		if len(columns) == 0 {
+			columns = entities.TransferColumns
+		}
+		for _, column := range columns {
+			if !helpers.Contains(entities.TransferColumns, column) {
+				return nil, fmt.Errorf("%w: %v", entities.ErrUnknownColumn, column)
+			}
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvEncoder{writer: writer, columns: columns}, nil
+	case entities.TransferJSON:
+		return &jsonEncoder{writer: w, array: true}, nil
+	case entities.TransferNDJSON:
+		return &jsonEncoder{writer: w}, nil
+	}
+	return nil, entities.ErrUnknownTransferFormat
+}
+
+// newDecoder instantiates a decoder of format, mapping is only used by CSV, see entities.ImportOptions.Columns.
+func newDecoder(r io.Reader, format entities.TransferFormat, mapping map[string]string) (postDecoder, error) {
+	switch format {
+	case entities.TransferCSV:
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+		header, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]string, len(header))
+		for i, name := range header {
+			name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+			column, ok := mapping[name]
+			if !ok {
+				column = name
+			}
+			if column != "-" && !helpers.Contains(entities.TransferColumns, column) {
+				return nil, fmt.Errorf("%w: %v", entities.ErrUnknownColumn, name)
+			}
+			columns[i] = column
+		}
+		return &csvDecoder{reader: reader, columns: columns}, nil
+	case entities.TransferJSON:
+		return &jsonDecoder{decoder: json.NewDecoder(r), array: true}, nil
+	case entities.TransferNDJSON:
+		return &jsonDecoder{decoder: json.NewDecoder(r)}, nil
+	}
+	return nil, entities.ErrUnknownTransferFormat
+}
+
+// csvEncoder writes a header followed by a record per post.
+type csvEncoder struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func (e *csvEncoder) Encode(post entities.Post) error {
+	record := helpers.Map(e.columns, func(column string, _ int) string {
+		return formatColumn(post, column)
+	})
+	return e.writer.Write(record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// csvDecoder reads a record per post, columns are resolved from the header, "-" columns are skipped.
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func (d *csvDecoder) Decode() (entities.Post, error) {
+	record, err := d.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return entities.Post{}, fmt.Errorf("%w: %v", entities.ErrMalformedRecord, err)
+		}
+		return entities.Post{}, err
+	}
+	var post entities.Post
+	for i, column := range d.columns {
+		if column == "-" {
+			continue
+		}
+		if err := parseColumn(&post, column, record[i]); err != nil {
+			return entities.Post{}, fmt.Errorf("%w: %v: %v", entities.ErrMalformedRecord, column, err)
+		}
+	}
+	return post, nil
+}
+
+// jsonEncoder writes posts as a JSON array, or as newline delimited JSON objects.
+type jsonEncoder struct {
+	writer  io.Writer
+	array   bool
+	started bool
+}
+
+func (e *jsonEncoder) Encode(post entities.Post) error {
+	encoded, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+	prefix := ""
+	if e.array {
+		prefix = ",\n"
+		if !e.started {
+			prefix = "[\n"
+		}
+	}
+	e.started = true
+	if !e.array {
+		encoded = append(encoded, '\n')
+	}
+	_, err = e.writer.Write(append([]byte(prefix), encoded...))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if !e.array {
+		return nil
+	}
+	closing := "\n]\n"
+	if !e.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.writer, closing)
+	return err
+}
+
+// jsonDecoder reads posts from a JSON array, or from a stream of JSON objects.
+// Values of wrong types only fail their record, syntax errors end the stream.
+type jsonDecoder struct {
+	decoder *json.Decoder
+	array   bool
+	started bool
+}
+
+func (d *jsonDecoder) Decode() (entities.Post, error) {
+	if d.array && !d.started {
+		d.started = true
+		token, err := d.decoder.Token()
+		if err != nil {
+			return entities.Post{}, err
+		}
+		if token != json.Delim('[') {
+			return entities.Post{}, fmt.Errorf("expected an array of posts, got %v", token)
+		}
+	}
+	if d.array && !d.decoder.More() {
+		if _, err := d.decoder.Token(); err != nil {
+			return entities.Post{}, err
+		}
+		return entities.Post{}, io.EOF
+	}
+	var post entities.Post
+	if err := d.decoder.Decode(&post); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return entities.Post{}, fmt.Errorf("%w: %v", entities.ErrMalformedRecord, err)
+		}
+		return entities.Post{}, err
+	}
+	return post, nil
+}
+
+// formatColumn renders a field of post as a CSV value.
+func formatColumn(post entities.Post, column string) string {
+	switch column {
+	case "id":
+		return strconv.FormatUint(uint64(post.ID), 10)
+	case "slug":
+		return post.Slug
+	case "user_id":
+		return strconv.FormatUint(uint64(post.UserID), 10)
+	case "title":
+		return post.Title
+	case "content":
+		return post.Content
+	case "format":
+		return string(post.Format)
+	case "status":
+		return string(post.Status)
+	case "published_at":
+		return formatTime(post.PublishedAt)
+	case "scheduled_at":
+		return formatTime(post.ScheduledAt)
+	case "tags":
+		return strings.Join(helpers.Map(post.Tags, func(tag entities.Tag, _ int) string {
+			return tag.Name
+		}), entities.TagSeparator)
+	case "created_at":
+		return formatTime(&post.CreatedAt)
+	case "updated_at":
+		return formatTime(&post.UpdatedAt)
+	}
+	return ""
+}
+
+// parseColumn sets a field of post from a CSV value, empty values leave fields zero.
+// An empty tags column clears tags of the post, unlike a missing one.
+func parseColumn(post *entities.Post, column string, value string) error {
+	if column == "tags" {
+		post.Tags = make([]entities.Tag, 0)
+		for _, name := range strings.Split(value, entities.TagSeparator) {
+			if name = strings.TrimSpace(name); name != "" {
+				post.Tags = append(post.Tags, entities.Tag{Name: name})
+			}
+		}
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+	switch column {
+	case "id":
+		id, err := strconv.ParseUint(value, 10, 32)
+		post.ID = entities.PostID(id)
+		return err
+	case "slug":
+		post.Slug = value
+	case "user_id":
+		id, err := strconv.ParseUint(value, 10, 32)
+		post.UserID = userentities.UserID(id)
+		return err
+	case "title":
+		post.Title = value
+	case "content":
+		post.Content = value
+	case "format":
+		post.Format = markup.Format(value)
+	case "status":
+		post.Status = entities.Status(value)
+	case "published_at":
+		return parseTime(&post.PublishedAt, value)
+	case "scheduled_at":
+		return parseTime(&post.ScheduledAt, value)
+	case "created_at":
+		at, err := time.Parse(time.RFC3339Nano, value)
+		post.CreatedAt = at
+		return err
+	case "updated_at":
+		at, err := time.Parse(time.RFC3339Nano, value)
+		post.UpdatedAt = at
+		return err
+	}
+	return nil
+}
+
+// formatTime renders an optional timestamp as a CSV value.
+func formatTime(at *time.Time) string {
+	if at == nil || at.IsZero() {
+		return ""
+	}
+	return at.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTime parses an optional timestamp out of a CSV value.
+func parseTime(target **time.Time, value string) error {
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return err
+	}
+	at = at.UTC()
+	*target = &at
+	return nil
+}
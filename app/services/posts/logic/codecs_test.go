@@ -0,0 +1,165 @@
+package logic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	published := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	ps := []entities.Post{
+		{
+			ID: 1, UserID: 2, Title: "first, \"quoted\"", Content: "multi\nline", Slug: "first-quoted",
+			Format: markup.FormatMarkdown, Status: entities.StatusPublished, PublishedAt: &published,
+			Tags: []entities.Tag{{Name: "go"}, {Name: "news"}},
+		},
+		{ID: 2, Title: "second", Format: markup.FormatPlain, Status: entities.StatusDraft, Tags: []entities.Tag{}},
+	}
+
+	for _, format := range []entities.TransferFormat{entities.TransferCSV, entities.TransferJSON, entities.TransferNDJSON} {
+		var buffer bytes.Buffer
+		encoder, err := newEncoder(&buffer, format, []string{"id", "user_id", "title", "content", "slug", "format", "status", "published_at", "tags"})
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for _, post := range ps {
+			assert.NoError(t, encoder.Encode(post))
+		}
+		assert.NoError(t, encoder.Close())
+
+		decoder, err := newDecoder(&buffer, format, nil)
+		if !assert.NoError(t, err, format) {
+			continue
+		}
+		for _, expected := range ps {
+			post, err := decoder.Decode()
+			if assert.NoError(t, err, format) {
+				assert.Equal(t, expected.ID, post.ID, format)
+				assert.Equal(t, expected.Title, post.Title, format)
+				assert.Equal(t, expected.Content, post.Content, format)
+				assert.Equal(t, expected.Status, post.Status, format)
+				assert.Equal(t, len(expected.Tags), len(post.Tags), format)
+				if expected.PublishedAt != nil && assert.NotNil(t, post.PublishedAt, format) {
+					assert.True(t, expected.PublishedAt.Equal(*post.PublishedAt), format)
+				}
+			}
+		}
+		_, err = decoder.Decode()
+		assert.ErrorIs(t, err, io.EOF, format)
+	}
+}
+
+func TestCodecs_Decode(t *testing.T) {
+	cases := []struct {
+		format    entities.TransferFormat
+		mapping   map[string]string
+		source    string
+		assertion func(ps []entities.Post, errs []error, err error)
+	}{
+		// Mapped, ignored and byte order marked headers.
+		{
+			format:  entities.TransferCSV,
+			mapping: map[string]string{"Headline": "title", "Body": "content", "Notes": "-"},
+			source:  "\ufeffHeadline,Body,Notes,tags\nHello,World,whatever, go | news \n",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, ps, 1) {
+					assert.Equal(t, "Hello", ps[0].Title)
+					assert.Equal(t, "World", ps[0].Content)
+					assert.Equal(t, []entities.Tag{{Name: "go"}, {Name: "news"}}, ps[0].Tags)
+				}
+			},
+		},
+		// Unknown header.
+		{
+			format: entities.TransferCSV,
+			source: "title,body\nHello,World\n",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.ErrorIs(t, err, entities.ErrUnknownColumn)
+			},
+		},
+		// Malformed records don't stop the import.
+		{
+			format: entities.TransferCSV,
+			source: "id,title\nx,first\n1,second,extra\n2,third\n",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, errs, 3) {
+					assert.ErrorIs(t, errs[0], entities.ErrMalformedRecord)
+					assert.ErrorIs(t, errs[1], entities.ErrMalformedRecord)
+					assert.NoError(t, errs[2])
+				}
+				assert.Equal(t, "third", ps[2].Title)
+			},
+		},
+		// Values of wrong types only fail their record.
+		{
+			format: entities.TransferNDJSON,
+			source: "{\"title\":\"first\"}\n{\"title\":1}\n{\"title\":\"third\"}\n",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.NoError(t, err)
+				if assert.Len(t, errs, 3) {
+					assert.NoError(t, errs[0])
+					assert.ErrorIs(t, errs[1], entities.ErrMalformedRecord)
+					assert.NoError(t, errs[2])
+				}
+			},
+		},
+		// Syntax errors end the stream.
+		{
+			format: entities.TransferJSON,
+			source: "[{\"title\":\"first\"}, {\"title\":",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, entities.ErrMalformedRecord)
+				assert.Len(t, errs, 1)
+			},
+		},
+		// Empty array.
+		{
+			format: entities.TransferJSON,
+			source: "[]",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, ps)
+			},
+		},
+		// Unknown format.
+		{
+			format: "xml",
+			assertion: func(ps []entities.Post, errs []error, err error) {
+				assert.ErrorIs(t, err, entities.ErrUnknownTransferFormat)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		decoder, err := newDecoder(strings.NewReader(c.source), c.format, c.mapping)
+		ps := make([]entities.Post, 0)
+		errs := make([]error, 0)
+		for err == nil {
+			var post entities.Post
+			post, err = decoder.Decode()
+			if err == io.EOF {
+				err = nil
+				break
+			}
+			if err != nil && !errors.Is(err, entities.ErrMalformedRecord) {
+				break
+			}
+			ps = append(ps, post)
+			errs = append(errs, err)
+			err = nil
+		}
+		c.assertion(ps, errs, err)
+	}
+}
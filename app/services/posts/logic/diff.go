@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes.
+	diffContext = 3
+)
+
+// diffLine is a single line of a diff, kind is one of ' ', '-' and '+'.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff renders a line-based unified diff of two texts, returns an empty string if they are equal.
+func unifiedDiff(fromName, toName, from, to string) string {
+	lines := diffLines(strings.Split(from, "\n"), strings.Split(to, "\n"))
+	var builder strings.Builder
+	// Line numbers (1-based) in both texts of the current position.
+	fromLine, toLine := 1, 1
+	for start := 0; start < len(lines); {
+		// Find the next change, skipping unchanged lines.
+		change := start
+		for change < len(lines) && lines[change].kind == ' ' {
+			change++
+		}
+		if change == len(lines) {
+			break
+		}
+		// Extend the hunk until there are more than 2*diffContext unchanged lines in a row.
+		first := change - diffContext
+		if first < start {
+			first = start
+		}
+		last := change
+		for i, unchanged := change, 0; i < len(lines) && unchanged <= 2*diffContext; i++ {
+			if lines[i].kind == ' ' {
+				unchanged++
+				continue
+			}
+			unchanged = 0
+			last = i
+		}
+		end := last + diffContext + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fromLine, toLine = fromLine+first-start, toLine+first-start
+		fromCount, toCount := 0, 0
+		for _, line := range lines[first:end] {
+			if line.kind != '+' {
+				fromCount++
+			}
+			if line.kind != '-' {
+				toCount++
+			}
+		}
+		if builder.Len() == 0 {
+			fmt.Fprintf(&builder, "--- %v\n+++ %v\n", fromName, toName)
+		}
+		fmt.Fprintf(&builder, "@@ -%v +%v @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+		for _, line := range lines[first:end] {
+			builder.WriteByte(line.kind)
+			builder.WriteString(line.text)
+			builder.WriteByte('\n')
+		}
+		fromLine, toLine = fromLine+fromCount, toLine+toCount
+		start = end
+	}
+	return builder.String()
+}
+
+// hunkRange renders a start,count range of a hunk header, empty ranges start at the preceding line.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%v,%v", start, count)
+}
+
+// diffLines aligns two lists of lines along their longest common subsequence.
+func diffLines(from, to []string) []diffLine {
+	return appendDiff(make([]diffLine, 0, len(from)+len(to)), from, to)
+}
+
+// appendDiff appends an alignment of two lists of lines to lines. It splits from in halves and to where the longest
+// common subsequences of the halves meet (Hirschberg's algorithm), so it only needs memory linear in the input.
+func appendDiff(lines []diffLine, from, to []string) []diffLine {
+	for len(from) > 0 && len(to) > 0 && from[0] == to[0] {
+		lines = append(lines, diffLine{kind: ' ', text: from[0]})
+		from, to = from[1:], to[1:]
+	}
+	suffix := 0
+	for suffix < len(from) && suffix < len(to) && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	common := from[len(from)-suffix:]
+	from, to = from[:len(from)-suffix], to[:len(to)-suffix]
+
+	switch {
+	case len(from) == 0 || len(to) == 0:
+		lines = appendLines(lines, '-', from)
+		lines = appendLines(lines, '+', to)
+	case len(from) == 1:
+		match := -1
+		for j := range to {
+			if to[j] == from[0] {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			lines = appendLines(lines, '-', from)
+			lines = appendLines(lines, '+', to)
+			break
+		}
+		lines = appendLines(lines, '+', to[:match])
+		lines = append(lines, diffLine{kind: ' ', text: from[0]})
+		lines = appendLines(lines, '+', to[match+1:])
+	default:
+		half := len(from) / 2
+		forward := lcsLengths(from[:half], to, false)
+		backward := lcsLengths(from[half:], to, true)
+		split := 0
+		for j := range forward {
+			if forward[j]+backward[len(to)-j] > forward[split]+backward[len(to)-split] {
+				split = j
+			}
+		}
+		lines = appendDiff(lines, from[:half], to[:split])
+		lines = appendDiff(lines, from[half:], to[split:])
+	}
+	return appendLines(lines, ' ', common)
+}
+
+// appendLines appends texts to lines as lines of a kind.
+func appendLines(lines []diffLine, kind byte, texts []string) []diffLine {
+	for _, text := range texts {
+		lines = append(lines, diffLine{kind: kind, text: text})
+	}
+	return lines
+}
+
+// lcsLengths returns the lengths of the longest common subsequences of from and every prefix of to, lengths[j] being
+// the one of to[:j]. If reverse is set, both lists are read backwards, so lengths[j] is the one of the last j lines.
+func lcsLengths(from, to []string, reverse bool) []int {
+	previous, current := make([]int, len(to)+1), make([]int, len(to)+1)
+	for i := range from {
+		line := from[i]
+		if reverse {
+			line = from[len(from)-1-i]
+		}
+		for j := range to {
+			other := to[j]
+			if reverse {
+				other = to[len(to)-1-j]
+			}
+			switch {
+			case line == other:
+				current[j+1] = previous[j] + 1
+			case previous[j+1] >= current[j]:
+				current[j+1] = previous[j+1]
+			default:
+				current[j+1] = current[j]
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	numbered := func(from, to int, replace map[int]string) string {
+		lines := make([]string, 0)
+		for i := from; i <= to; i++ {
+			line := "line " + strings.Repeat("i", i%5) + string(rune('a'+i))
+			if r, ok := replace[i]; ok {
+				line = r
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+	}
+	cases := []struct {
+		from, to  string
+		assertion func(string)
+	}{
+		// Equal.
+		{
+			from: "same",
+			to:   "same",
+			assertion: func(diff string) {
+				assert.Empty(t, diff)
+			},
+		},
+		// Single change.
+		{
+			from: "title: a\nuser_id: 1\n\nfirst\nsecond",
+			to:   "title: b\nuser_id: 1\n\nfirst\nsecond",
+			assertion: func(diff string) {
+				assert.Equal(t, "--- from\n+++ to\n@@ -1,4 +1,4 @@\n-title: a\n+title: b\n user_id: 1\n \n first\n", diff)
+			},
+		},
+		// Distant changes are split into hunks.
+		{
+			from: numbered(0, 19, nil),
+			to:   numbered(0, 19, map[int]string{1: "changed", 18: "changed"}),
+			assertion: func(diff string) {
+				assert.Equal(t, 2, strings.Count(diff, "@@ -"))
+				assert.Contains(t, diff, "@@ -1,5 +1,5 @@\n")
+				assert.Contains(t, diff, "@@ -16,5 +16,5 @@\n")
+			},
+		},
+		// Insertion into an empty text.
+		{
+			from: "",
+			to:   "added",
+			assertion: func(diff string) {
+				assert.Equal(t, "--- from\n+++ to\n@@ -1 +1 @@\n-\n+added\n", diff)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(unifiedDiff("from", "to", c.from, c.to))
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	lines := func() []string {
+		result := make([]string, random.Intn(12))
+		for i := range result {
+			result[i] = string(rune('a' + random.Intn(4)))
+		}
+		return result
+	}
+	for i := 0; i < 500; i++ {
+		from, to := lines(), lines()
+		var before, after []string
+		unchanged := 0
+		for _, line := range diffLines(from, to) {
+			if line.kind != '+' {
+				before = append(before, line.text)
+			}
+			if line.kind != '-' {
+				after = append(after, line.text)
+			}
+			if line.kind == ' ' {
+				unchanged++
+			}
+		}
+		// Both texts are restored, and all of their longest common subsequence is kept unchanged.
+		assert.Equal(t, strings.Join(from, "\n"), strings.Join(before, "\n"))
+		assert.Equal(t, strings.Join(to, "\n"), strings.Join(after, "\n"))
+		assert.Equal(t, lcsLengths(from, to, false)[len(to)], unchanged)
+	}
+}
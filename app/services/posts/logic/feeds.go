@@ -0,0 +1,84 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// removedMarker names the change marker of published posts being deleted, unpublished or archived.
+	removedMarker = "posts.removed"
+	// markSQL moves a change marker to now.
+	markSQL = `INSERT INTO change_markers (name, changed_at) VALUES (?, ?)
+	ON CONFLICT (name) DO UPDATE SET changed_at = EXCLUDED.changed_at`
+)
+
+// FeedPosts returns up to limit published posts matching filter along with their tags and authors, most recently
+// published first. It also returns the last time any post matching filter changed, whatever its status, or any
+// published post was removed, so that posts leaving the feed count as changes. Throws entities.ErrTagNotFound if
+// filter.Tag names no tag.
+func (s *PostsService) FeedPosts(ctx context.Context, filter entities.FeedFilter, limit int) ([]entities.Post, time.Time, error) {
+	db := s.reader.Model(&entities.Post{})
+	if filter.UserID != 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Tag != "" {
+		var tag entities.Tag
+		err := s.reader.First(&tag, "name = ?", filter.Tag).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, time.Time{}, entities.ErrTagNotFound
+			}
+			return nil, time.Time{}, err
+		}
+		db = db.Where("id IN (SELECT post_id FROM post_tags WHERE tag_id = ?)", tag.ID)
+	}
+	db = db.Session(&gorm.Session{})
+
+	var updated struct {
+		At *time.Time `gorm:"column:at"`
+	}
+	if err := db.Select("MAX(updated_at) AS at").Scan(&updated).Error; err != nil {
+		return nil, time.Time{}, err
+	}
+	posts := make([]entities.Post, 0)
+	err := db.Where("status = ?", entities.StatusPublished).
+		Preload("Tags").
+		Preload("User").
+		Order("published_at DESC, id DESC").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	removed, err := s.lastRemoval(s.reader)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if updated.At == nil || updated.At.Before(removed) {
+		return posts, removed, nil
+	}
+	return posts, updated.At.UTC(), nil
+}
+
+// markRemoval records that a published post has been deleted, unpublished or archived.
+func (s *PostsService) markRemoval(db *gorm.DB) error {
+	return db.Exec(markSQL, removedMarker, time.Now().UTC()).Error
+}
+
+// lastRemoval returns the last time a published post has been deleted, unpublished or archived, or the zero time.
+func (s *PostsService) lastRemoval(db *gorm.DB) (time.Time, error) {
+	var removed struct {
+		At *time.Time `gorm:"column:at"`
+	}
+	err := db.Raw("SELECT changed_at AS at FROM change_markers WHERE name = ?", removedMarker).Scan(&removed).Error
+	if err != nil || removed.At == nil {
+		return time.Time{}, err
+	}
+	return removed.At.UTC(), nil
+}
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_FeedPosts(t *testing.T) {
+	ctx := context.Background()
+
+	tag := entities.Tag{Name: "test-feed-tag"}
+	if !assert.NoError(t, postsServiceTestInstance.CreateTag(ctx, &tag)) {
+		return
+	}
+	defer postsServiceTestInstance.DeleteTag(ctx, tag.ID)
+	post := entities.Post{Title: "test-feed-title", Content: "test-content", Tags: []entities.Tag{{Name: tag.Name}}}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+
+	// Drafts are left out, but still count as changes.
+	ps, updated, err := postsServiceTestInstance.FeedPosts(ctx, entities.FeedFilter{Tag: tag.Name}, 10)
+	if assert.NoError(t, err) {
+		assert.Empty(t, ps)
+		assert.WithinDuration(t, post.UpdatedAt, updated, time.Millisecond)
+	}
+
+	published, err := postsServiceTestInstance.PublishPost(ctx, post.ID, time.Now())
+	if !assert.NoError(t, err) {
+		return
+	}
+	ps, updated, err = postsServiceTestInstance.FeedPosts(ctx, entities.FeedFilter{Tag: tag.Name}, 10)
+	if assert.NoError(t, err) && assert.Len(t, ps, 1) {
+		assert.Equal(t, post.ID, ps[0].ID)
+		assert.Len(t, ps[0].Tags, 1)
+		assert.WithinDuration(t, published.UpdatedAt, updated, time.Millisecond)
+	}
+
+	// Deleting a published post leaves no row behind, but still counts as a change.
+	removed := entities.Post{Title: "test-feed-removed", Content: "test-content", Tags: []entities.Tag{{Name: tag.Name}}}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &removed, 0)) {
+		return
+	}
+	if _, err = postsServiceTestInstance.PublishPost(ctx, removed.ID, time.Now()); !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, postsServiceTestInstance.DeletePost(ctx, removed.ID, entities.AnyVersion)) {
+		return
+	}
+	ps, updated, err = postsServiceTestInstance.FeedPosts(ctx, entities.FeedFilter{Tag: tag.Name}, 10)
+	if assert.NoError(t, err) && assert.Len(t, ps, 1) {
+		assert.True(t, updated.After(published.UpdatedAt))
+		assert.WithinDuration(t, time.Now(), updated, time.Minute)
+	}
+
+	_, _, err = postsServiceTestInstance.FeedPosts(ctx, entities.FeedFilter{Tag: "test-feed-missing"}, 10)
+	assert.ErrorIs(t, err, entities.ErrTagNotFound)
+}
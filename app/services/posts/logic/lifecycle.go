@@ -0,0 +1,117 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// transitions lists statuses a post can move to from each status.
+	transitions = map[entities.Status][]entities.Status{
+		entities.StatusDraft:     {entities.StatusScheduled, entities.StatusPublished, entities.StatusArchived},
+		entities.StatusScheduled: {entities.StatusDraft, entities.StatusScheduled, entities.StatusPublished, entities.StatusArchived},
+		entities.StatusPublished: {entities.StatusDraft, entities.StatusArchived},
+		entities.StatusArchived:  {entities.StatusDraft},
+	}
+)
+
+// canTransition checks whether a post can move from one status to another.
+func canTransition(from, to entities.Status) bool {
+	return helpers.Contains(transitions[from], to)
+}
+
+// PublishPost publishes a post, or schedules it to be published if at is in the future.
+// Throws entities.ErrPostNotFound if id is invalid, and entities.ErrInvalidTransition if the post can't be published.
+func (s *PostsService) PublishPost(ctx context.Context, id entities.PostID, at time.Time) (entities.Post, error) {
+	now := time.Now().UTC()
+	if at.After(now) {
+		at = at.UTC()
+		return s.transition(ctx, id, entities.StatusScheduled, func(post *entities.Post) {
+			post.ScheduledAt = &at
+		})
+	}
+	return s.transition(ctx, id, entities.StatusPublished, func(post *entities.Post) {
+		post.PublishedAt = &now
+		post.ScheduledAt = nil
+	})
+}
+
+// UnpublishPost moves a published or scheduled post back to drafts.
+// Throws entities.ErrPostNotFound if id is invalid, and entities.ErrInvalidTransition if the post is a draft already.
+func (s *PostsService) UnpublishPost(ctx context.Context, id entities.PostID) (entities.Post, error) {
+	return s.transition(ctx, id, entities.StatusDraft, func(post *entities.Post) {
+		post.PublishedAt = nil
+		post.ScheduledAt = nil
+	})
+}
+
+// ArchivePost archives a post, hiding it from everyone but its owner.
+// Throws entities.ErrPostNotFound if id is invalid, and entities.ErrInvalidTransition if the post is archived already.
+func (s *PostsService) ArchivePost(ctx context.Context, id entities.PostID) (entities.Post, error) {
+	return s.transition(ctx, id, entities.StatusArchived, func(post *entities.Post) {
+		post.ScheduledAt = nil
+	})
+}
+
+// PublishScheduledPosts publishes all scheduled posts that are due, returning the number of published posts.
+func (s *PostsService) PublishScheduledPosts(ctx context.Context) (int64, error) {
+	result := s.writer.Model(&entities.Post{}).
+		Where("status = ? AND scheduled_at <= ?", entities.StatusScheduled, time.Now().UTC()).
+		Updates(map[string]interface{}{
+			"status":       entities.StatusPublished,
+			"published_at": gorm.Expr("scheduled_at"),
+			"scheduled_at": nil,
+			"updated_at":   time.Now().UTC(),
+			"version":      gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// transition moves a locked post to another status, applying status-specific changes.
+func (s *PostsService) transition(ctx context.Context, id entities.PostID, to entities.Status, apply func(post *entities.Post)) (entities.Post, error) {
+	var post entities.Post
+	err := s.writer.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, "id = ?", id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrPostNotFound
+			}
+			return err
+		}
+		if !canTransition(post.Status, to) {
+			return entities.ErrInvalidTransition
+		}
+		removed := post.Status == entities.StatusPublished && to != entities.StatusPublished
+		post.Status = to
+		post.UpdatedAt = time.Now().UTC()
+		apply(&post)
+		if err := s.update(tx, &post, entities.AnyVersion); err != nil || !removed {
+			return err
+		}
+		return s.markRemoval(tx)
+	})
+	if err != nil {
+		return entities.Post{}, err
+	}
+	return post, nil
+}
+
+// visible restricts a posts query to posts a viewer can see: published ones, and all of their own.
+// entities.AnyViewer sees every post.
+func visible(db *gorm.DB, viewer userentities.UserID) *gorm.DB {
+	if viewer == entities.AnyViewer {
+		return db
+	}
+	if viewer == 0 {
+		return db.Where("status = ?", entities.StatusPublished)
+	}
+	return db.Where("(status = ? OR user_id = ?)", entities.StatusPublished, viewer)
+}
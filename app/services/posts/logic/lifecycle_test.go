@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to entities.Status
+		allowed  bool
+	}{
+		{from: entities.StatusDraft, to: entities.StatusPublished, allowed: true},
+		{from: entities.StatusDraft, to: entities.StatusDraft, allowed: false},
+		{from: entities.StatusScheduled, to: entities.StatusScheduled, allowed: true},
+		{from: entities.StatusPublished, to: entities.StatusScheduled, allowed: false},
+		{from: entities.StatusPublished, to: entities.StatusArchived, allowed: true},
+		{from: entities.StatusArchived, to: entities.StatusPublished, allowed: false},
+		{from: entities.StatusArchived, to: entities.StatusDraft, allowed: true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.allowed, canTransition(c.from, c.to), "%v -> %v", c.from, c.to)
+	}
+}
+
+func TestPostsService_PublishPost(t *testing.T) {
+	ctx := context.Background()
+	future := time.Now().UTC().Add(time.Hour).Truncate(time.Microsecond)
+
+	cases := []struct {
+		setup     func() (entities.PostID, error)
+		base      func(id entities.PostID) (entities.Post, error)
+		assertion func(post entities.Post, err error)
+	}{
+		// Publish now.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{Title: "test-title", Content: "test-content"}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PublishPost(ctx, id, time.Time{})
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, entities.StatusPublished, post.Status)
+					assert.NotNil(t, post.PublishedAt)
+					assert.Nil(t, post.ScheduledAt)
+				}
+			},
+		},
+		// Schedule, not due yet.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{Title: "test-title", Content: "test-content"}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				_, err := postsServiceTestInstance.PublishPost(ctx, id, future)
+				if err != nil {
+					return entities.Post{}, err
+				}
+				if _, err = postsServiceTestInstance.PublishScheduledPosts(ctx); err != nil {
+					return entities.Post{}, err
+				}
+				return postsServiceTestInstance.FindPost(ctx, id)
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, entities.StatusScheduled, post.Status)
+					if assert.NotNil(t, post.ScheduledAt) {
+						assert.True(t, future.Equal(*post.ScheduledAt))
+					}
+					assert.Nil(t, post.PublishedAt)
+				}
+			},
+		},
+		// Archived posts have to be moved to drafts first.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{Title: "test-title", Content: "test-content"}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				if err != nil {
+					return 0, err
+				}
+				_, err = postsServiceTestInstance.ArchivePost(ctx, post.ID)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PublishPost(ctx, id, time.Time{})
+			},
+			assertion: func(post entities.Post, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+			},
+		},
+		// Non-existing post.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{Title: "test-title", Content: "test-content"}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				_ = postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.UnpublishPost(ctx, id)
+			},
+			assertion: func(post entities.Post, err error) {
+				assert.ErrorIs(t, err, entities.ErrPostNotFound)
+			},
+		},
+	}
+	for _, c := range cases {
+		postID, err := c.setup()
+		assert.Nil(t, err)
+		c.assertion(c.base(postID))
+		_ = postsServiceTestInstance.DeletePost(ctx, postID, entities.AnyVersion)
+	}
+}
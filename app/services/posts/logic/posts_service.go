@@ -3,12 +3,18 @@ package logic
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Verify that PostsService satisfies the interfaces.PostsService interface.
@@ -32,23 +38,100 @@ func NewPostsService(ctx context.Context, reader *gorm.DB, writer *gorm.DB) (*Po
 	}, nil
 }
 
-// IndexPosts returns an array of all existing posts, throws entities.ErrPostNotFound if table is empty.
-func (s *PostsService) IndexPosts(ctx context.Context) ([]entities.Post, error) {
-	var posts []entities.Post
-	err := s.reader.Find(&posts).Error
+// IndexPosts returns a page of posts visible to viewer, throws entities.ErrPostNotFound if table is empty.
+// Only requested fields are selected, and requested related resources are preloaded.
+func (s *PostsService) IndexPosts(ctx context.Context, viewer userentities.UserID, opts query.Options) ([]entities.Post, query.PageInfo, error) {
+	posts, page, err := query.Paginate[entities.Post](visible(s.reader, viewer), entities.PostResource, opts)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, entities.ErrPostNotFound
+			return nil, query.PageInfo{}, entities.ErrPostNotFound
 		}
-		return nil, err
+		return nil, query.PageInfo{}, err
 	}
-	return posts, nil
+	return posts, page, nil
+}
+
+// SearchPosts returns a page of posts visible to viewer matching a full-text search query, most relevant first.
+func (s *PostsService) SearchPosts(ctx context.Context, viewer userentities.UserID, q string, opts query.Options) ([]entities.SearchResult, query.PageInfo, error) {
+	expression, args, err := tsquery(q)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	results := s.reader.Raw(fmt.Sprintf(searchSQL, expression), args...)
+	return query.Paginate[entities.SearchResult](visible(s.reader.Table("(?) AS results", results), viewer), entities.SearchResultResource, opts)
 }
 
 // FindPost fetches a post by provided id, throws entities.ErrPostNotFound if id is invalid.
 func (s *PostsService) FindPost(ctx context.Context, id entities.PostID) (entities.Post, error) {
+	return s.find(s.reader, id)
+}
+
+// UpdatePost updates a post in persistent repository on behalf of author, recording a revision, throws
+// entities.ErrPostNotFound if id is invalid. Server-managed fields provided by the caller are ignored. If post.Version
+// is set, the update only succeeds if it matches the stored version, throws entities.ErrVersionMismatch otherwise.
+func (s *PostsService) UpdatePost(ctx context.Context, post *entities.Post, author userentities.UserID) error {
+	return s.replace(s.writer, post, author)
+}
+
+// PatchPost applies patch to a stored post and saves the result on behalf of author, recording a revision, throws
+// entities.ErrPostNotFound if id is invalid.
+// The post is locked for the duration of the patch, so concurrent patches don't overwrite each other, and patch
+// shouldn't wait on anything but the post.
+// Unless version is entities.AnyVersion, throws entities.ErrVersionMismatch if it doesn't match the stored version.
+func (s *PostsService) PatchPost(ctx context.Context, id entities.PostID, version entities.Version, author userentities.UserID, patch func(post *entities.Post) error) (entities.Post, error) {
+	var post entities.Post
+	err := s.writer.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Tags").First(&post, "id = ?", id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrPostNotFound
+			}
+			return err
+		}
+		if version != entities.AnyVersion && version != post.Version {
+			return entities.ErrVersionMismatch
+		}
+		stored := post
+		if err = patch(&post); err != nil {
+			return err
+		}
+		protect(&post, stored)
+		if err = post.Validate(); err != nil {
+			return err
+		}
+		err = s.claimSlug(tx, &post, stored.Title, func() error {
+			return s.update(tx, &post, version)
+		})
+		if err != nil {
+			return err
+		}
+		if err = s.assignTags(tx, &post); err != nil {
+			return err
+		}
+		return s.revise(tx, post, author)
+	})
+	if err != nil {
+		return entities.Post{}, err
+	}
+	return post, nil
+}
+
+// CreatePost creates a post in persistent repository on behalf of author, recording its first revision, throws
+// entities.ErrDuplicatePost if id is conflicting.
+func (s *PostsService) CreatePost(ctx context.Context, post *entities.Post, author userentities.UserID) error {
+	return s.create(s.writer, post, author)
+}
+
+// DeletePost deletes a post from persistent repository, throws entities.ErrPostNotFound if id is invalid.
+// Unless version is entities.AnyVersion, throws entities.ErrVersionMismatch if it doesn't match the stored version.
+func (s *PostsService) DeletePost(ctx context.Context, id entities.PostID, version entities.Version) error {
+	return s.delete(s.writer, id, version)
+}
+
+// find fetches a post along with its tags.
+func (s *PostsService) find(db *gorm.DB, id entities.PostID) (entities.Post, error) {
 	var post entities.Post
-	err := s.reader.First(&post, "id = ?", id).Error
+	err := db.Preload("Tags").First(&post, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entities.Post{}, entities.ErrPostNotFound
@@ -58,36 +141,156 @@ func (s *PostsService) FindPost(ctx context.Context, id entities.PostID) (entiti
 	return post, nil
 }
 
-// UpdatePost updates a post in persistent repository, throws entities.ErrPostNotFound if id is invalid.
-func (s *PostsService) UpdatePost(ctx context.Context, post *entities.Post) error {
+// create implements CreatePost on db, which may already be in a transaction.
+func (s *PostsService) create(db *gorm.DB, post *entities.Post, author userentities.UserID) error {
 	if err := post.Validate(); err != nil {
 		return err
 	}
-	_, err := s.FindPost(ctx, post.ID)
-	if err != nil {
+	// New posts always start as drafts, they are published through the lifecycle.
+	post.Status = entities.StatusDraft
+	post.PublishedAt, post.ScheduledAt = nil, nil
+	post.Slug = ""
+	if post.Format == "" {
+		post.Format = markup.FormatPlain
+	}
+	if err := render(post); err != nil {
 		return err
 	}
-	return s.writer.Save(&post).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := s.claimSlug(tx, post, "", func() error {
+			return tx.Omit(clause.Associations).Create(post).Error
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.assignTags(tx, post); err != nil {
+			return err
+		}
+		return s.revise(tx, *post, author)
+	})
+	// Check for duplicate key error, didn't find a check in gorm :(
+	if err != nil && strings.Contains(err.Error(), "SQLSTATE 23505") {
+		err = entities.ErrDuplicatePost
+	}
+	return unknownUser(err)
 }
 
-// CreatePost creates a post in persistent repository, throws entities.ErrDuplicatePost if id is conflicting.
-func (s *PostsService) CreatePost(ctx context.Context, post *entities.Post) error {
+// replace implements UpdatePost on db, which may already be in a transaction.
+func (s *PostsService) replace(db *gorm.DB, post *entities.Post, author userentities.UserID) error {
 	if err := post.Validate(); err != nil {
 		return err
 	}
-	err := s.writer.Create(&post).Error
-	// Check for duplicate key error, didn't find a check in gorm :(
-	if err != nil && strings.Contains(err.Error(), "SQLSTATE 23505") {
-		err = entities.ErrDuplicatePost
+	stored, err := s.find(db, post.ID)
+	if err != nil {
+		return err
+	}
+	expected := post.Version
+	if post.Format == "" {
+		post.Format = stored.Format
+	}
+	protect(post, stored)
+	return unknownUser(db.Transaction(func(tx *gorm.DB) error {
+		err := s.claimSlug(tx, post, stored.Title, func() error {
+			return s.update(tx, post, expected)
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.assignTags(tx, post); err != nil {
+			return err
+		}
+		return s.revise(tx, *post, author)
+	}))
+}
+
+// unknownUser translates foreign key violations of post users and revision authors.
+func unknownUser(err error) error {
+	// Check for foreign key error, didn't find a check in gorm :(
+	if err != nil && strings.Contains(err.Error(), "SQLSTATE 23503") &&
+		(strings.Contains(err.Error(), "user_id_fkey") || strings.Contains(err.Error(), "author_id_fkey")) {
+		return userentities.ErrUserNotFound
 	}
 	return err
 }
 
-// DeletePost deletes a post from persistent repository, throws entities.ErrPostNotFound if id is invalid.
-func (s *PostsService) DeletePost(ctx context.Context, id entities.PostID) error {
-	post, err := s.FindPost(ctx, id)
+// delete implements DeletePost on db, which may already be in a transaction.
+func (s *PostsService) delete(db *gorm.DB, id entities.PostID, version entities.Version) error {
+	post, err := s.find(db, id)
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		deletion := tx
+		if version != entities.AnyVersion {
+			deletion = deletion.Where("version = ?", version)
+		}
+		result := deletion.Delete(&post)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrVersionMismatch
+		}
+		if post.Status == entities.StatusPublished {
+			return s.markRemoval(tx)
+		}
+		return nil
+	})
+}
+
+// update writes all client-writable fields of a post, bumping its version, throws entities.ErrVersionMismatch if
+// expected version is set and doesn't match the stored one.
+func (s *PostsService) update(db *gorm.DB, post *entities.Post, expected entities.Version) error {
+	if err := render(post); err != nil {
+		return err
+	}
+	db = db.Model(post).Omit(clause.Associations).Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}})
+	if expected != entities.AnyVersion {
+		db = db.Where("version = ?", expected)
+	}
+	result := db.Updates(map[string]interface{}{
+		"user_id":      post.UserID,
+		"title":        post.Title,
+		"slug":         post.Slug,
+		"content":      post.Content,
+		"format":       post.Format,
+		"content_html": post.ContentHTML,
+		"excerpt":      post.Excerpt,
+		"reading_time": post.ReadingTime,
+		"updated_at":   post.UpdatedAt,
+		"status":       post.Status,
+		"published_at": post.PublishedAt,
+		"scheduled_at": post.ScheduledAt,
+		"version":      gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrVersionMismatch
+	}
+	return nil
+}
+
+// render derives server-rendered fields of a post from its content.
+func render(post *entities.Post) error {
+	rendered, err := markup.Render(post.Format, post.Content)
 	if err != nil {
 		return err
 	}
-	return s.writer.Delete(&post).Error
+	post.ContentHTML = rendered
+	post.Excerpt = markup.Excerpt(rendered)
+	post.ReadingTime = markup.ReadingTime(rendered)
+	return nil
+}
+
+// protect restores server-managed fields of an updated post from its stored version, and bumps updated_at.
+func protect(post *entities.Post, stored entities.Post) {
+	post.ID = stored.ID
+	post.CreatedAt = stored.CreatedAt
+	post.Slug = stored.Slug
+	post.Status = stored.Status
+	post.PublishedAt = stored.PublishedAt
+	post.ScheduledAt = stored.ScheduledAt
+	post.UpdatedAt = time.Now().UTC()
 }
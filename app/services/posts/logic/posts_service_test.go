@@ -2,13 +2,19 @@ package logic
 
 import (
 	"context"
+	"math"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/interfaces"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -63,11 +69,16 @@ func TestPostsService_IndexPosts(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				if err != nil {
+					return 0, err
+				}
+				_, err = postsServiceTestInstance.PublishPost(ctx, post.ID, time.Time{})
 				return post.ID, err
 			},
 			base: func() ([]entities.Post, error) {
-				return postsServiceTestInstance.IndexPosts(ctx)
+				posts, _, err := postsServiceTestInstance.IndexPosts(ctx, 0, query.Options{})
+				return posts, err
 			},
 			assertion: func(posts []entities.Post, err error) {
 				if assert.NoError(t, err) {
@@ -75,7 +86,34 @@ func TestPostsService_IndexPosts(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Drafts are only visible to their owners.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-draft",
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func() ([]entities.Post, error) {
+				opts, err := entities.PostResource.Parse(url.Values{"filter[title]": {"test-draft"}})
+				if err != nil {
+					return nil, err
+				}
+				posts, _, err := postsServiceTestInstance.IndexPosts(ctx, 0, opts)
+				return posts, err
+			},
+			assertion: func(posts []entities.Post, err error) {
+				if assert.NoError(t, err) {
+					assert.Empty(t, posts)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 	}
@@ -89,6 +127,97 @@ func TestPostsService_IndexPosts(t *testing.T) {
 	}
 }
 
+func TestPostsService_SearchPosts(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		setup     func() (entities.PostID, error)
+		base      func() ([]entities.SearchResult, error)
+		assertion func(results []entities.SearchResult, err error)
+		cleanup   func(entities.PostID) error
+	}{
+		// Title match.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "Quarterly newsletter",
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				if err != nil {
+					return 0, err
+				}
+				_, err = postsServiceTestInstance.PublishPost(ctx, post.ID, time.Time{})
+				return post.ID, err
+			},
+			base: func() ([]entities.SearchResult, error) {
+				results, _, err := postsServiceTestInstance.SearchPosts(ctx, 0, "quarter", query.Options{})
+				return results, err
+			},
+			assertion: func(results []entities.SearchResult, err error) {
+				if assert.NoError(t, err) && assert.NotEmpty(t, results) {
+					assert.Contains(t, results[0].TitleHighlight, "<mark>")
+					assert.NotZero(t, results[0].Rank)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Markup in matched text is escaped.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   `Escaped <img src=x onerror="alert(1)"> headline`,
+					Content: "Escaped <script>alert(1)</script> snippet",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				if err != nil {
+					return 0, err
+				}
+				_, err = postsServiceTestInstance.PublishPost(ctx, post.ID, time.Time{})
+				return post.ID, err
+			},
+			base: func() ([]entities.SearchResult, error) {
+				results, _, err := postsServiceTestInstance.SearchPosts(ctx, 0, "escaped", query.Options{})
+				return results, err
+			},
+			assertion: func(results []entities.SearchResult, err error) {
+				if assert.NoError(t, err) && assert.NotEmpty(t, results) {
+					assert.Contains(t, results[0].TitleHighlight, "<mark>Escaped</mark> &lt;img")
+					assert.NotContains(t, results[0].TitleHighlight, "<img")
+					assert.Contains(t, results[0].Snippet, "&lt;script&gt;")
+					assert.NotContains(t, results[0].Snippet, "<script>")
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Empty query.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func() ([]entities.SearchResult, error) {
+				results, _, err := postsServiceTestInstance.SearchPosts(ctx, 0, " ", query.Options{})
+				return results, err
+			},
+			assertion: func(results []entities.SearchResult, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrEmptySearchQuery)
+				}
+			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
+	}
+	for _, c := range cases {
+		postID, err := c.setup()
+		assert.Nil(t, err)
+		results, err := c.base()
+		c.assertion(results, err)
+		err = c.cleanup(postID)
+		assert.Nil(t, err)
+	}
+}
+
 func TestPostsService_FindPost(t *testing.T) {
 	ctx := context.Background()
 
@@ -105,7 +234,7 @@ func TestPostsService_FindPost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -117,7 +246,7 @@ func TestPostsService_FindPost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 		// Non-existing post.
@@ -127,8 +256,8 @@ func TestPostsService_FindPost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
-				_ = postsServiceTestInstance.DeletePost(ctx, post.ID)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				_ = postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -169,7 +298,7 @@ func TestPostsService_UpdatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -178,16 +307,46 @@ func TestPostsService_UpdatePost(t *testing.T) {
 					Title:   "test-title-new",
 					Content: "test-content-new",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
 				if assert.NoError(t, err) {
 					assert.NotZero(t, post)
+					assert.Equal(t, entities.Version(2), post.Version)
 				}
 			},
 			cleanup: func(id entities.PostID) error { return nil },
 		},
+		// Stale version.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   strings.Repeat("x", 255),
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				post := entities.Post{
+					ID:      id,
+					Title:   "test-title-new",
+					Content: "test-content-new",
+					Version: 2,
+				}
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
+				return post, err
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrVersionMismatch)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
 		// Non-existing post.
 		{
 			setup: func() (entities.PostID, error) {
@@ -195,8 +354,8 @@ func TestPostsService_UpdatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
-				_ = postsServiceTestInstance.DeletePost(ctx, post.ID)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				_ = postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -205,7 +364,7 @@ func TestPostsService_UpdatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content-new",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -225,6 +384,127 @@ func TestPostsService_UpdatePost(t *testing.T) {
 	}
 }
 
+func TestPostsService_PatchPost(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		setup     func() (entities.PostID, error)
+		base      func(id entities.PostID) (entities.Post, error)
+		assertion func(post entities.Post, err error)
+		cleanup   func(entities.PostID) error
+	}{
+		// Existing record, protected fields are kept.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   strings.Repeat("x", 255),
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PatchPost(ctx, id, entities.AnyVersion, 0, func(post *entities.Post) error {
+					post.ID = 0
+					post.Title = "test-title-new"
+					post.CreatedAt = time.Time{}
+					return nil
+				})
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.NoError(t, err) {
+					assert.NotZero(t, post.ID)
+					assert.NotZero(t, post.CreatedAt)
+					assert.Equal(t, "test-title-new", post.Title)
+					assert.Equal(t, "test-content", post.Content)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Stale version.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   strings.Repeat("x", 255),
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PatchPost(ctx, id, 2, 0, func(post *entities.Post) error {
+					post.Title = "test-title-new"
+					return nil
+				})
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrVersionMismatch)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Invalid result.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   strings.Repeat("x", 255),
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PatchPost(ctx, id, entities.AnyVersion, 0, func(post *entities.Post) error {
+					post.Title = ""
+					return nil
+				})
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrInvalidTitle)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Non-existing post.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   strings.Repeat("x", 255),
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				_ = postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (entities.Post, error) {
+				return postsServiceTestInstance.PatchPost(ctx, id, entities.AnyVersion, 0, func(post *entities.Post) error { return nil })
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrPostNotFound)
+				}
+			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
+	}
+	for _, c := range cases {
+		postID, err := c.setup()
+		assert.Nil(t, err)
+		post, err := c.base(postID)
+		c.assertion(post, err)
+		err = c.cleanup(postID)
+		assert.Nil(t, err)
+	}
+}
+
 func TestPostsService_CreatePost(t *testing.T) {
 	ctx := context.Background()
 
@@ -242,7 +522,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -251,8 +531,65 @@ func TestPostsService_CreatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Markdown is rendered.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func(id entities.PostID) (entities.Post, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "**test** content<script>alert(1)</script>",
+					Format:  markup.FormatMarkdown,
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post, err
+			},
+			assertion: func(post entities.Post, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "<p><strong>test</strong> content&lt;script&gt;alert(1)&lt;/script&gt;</p>\n", post.ContentHTML)
+					assert.Equal(t, "test content<script>alert(1)</script>", post.Excerpt)
+					assert.Equal(t, 1, post.ReadingTime)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
+			},
+		},
+		// Unknown format.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func(id entities.PostID) (entities.Post, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+					Format:  "rst",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post, err
+			},
+			assertion: func(post entities.Post, err error) {
+				assert.ErrorIs(t, err, markup.ErrUnknownFormat)
 			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
+		// Unknown user.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func(id entities.PostID) (entities.Post, error) {
+				post := entities.Post{
+					UserID:  math.MaxInt32,
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				return post, err
+			},
+			assertion: func(post entities.Post, err error) {
+				assert.ErrorIs(t, err, userentities.ErrUserNotFound)
+			},
+			cleanup: func(id entities.PostID) error { return nil },
 		},
 		// Invalid title (empty).
 		{
@@ -262,7 +599,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 					Title:   "",
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -280,7 +617,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 256),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -297,7 +634,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -306,7 +643,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -315,7 +652,7 @@ func TestPostsService_CreatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 	}
@@ -353,7 +690,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -362,7 +699,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content-updated",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -371,7 +708,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 		// Invalid title (empty).
@@ -381,7 +718,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -390,7 +727,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   "",
 					Content: "test-content-updated",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -399,7 +736,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 		// Invalid title (overflow).
@@ -409,7 +746,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -418,7 +755,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 256),
 					Content: "test-content-updated",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -427,7 +764,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				return postsServiceTestInstance.DeletePost(ctx, id)
+				return postsServiceTestInstance.DeletePost(ctx, id, entities.AnyVersion)
 			},
 		},
 		// Non-existing post.
@@ -437,8 +774,8 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content",
 				}
-				err := postsServiceTestInstance.CreatePost(ctx, &post)
-				_ = postsServiceTestInstance.DeletePost(ctx, post.ID)
+				err := postsServiceTestInstance.CreatePost(ctx, &post, 0)
+				_ = postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
 				return post.ID, err
 			},
 			base: func(id entities.PostID) (entities.Post, error) {
@@ -447,7 +784,7 @@ func TestPostsService_DeletePost(t *testing.T) {
 					Title:   strings.Repeat("x", 255),
 					Content: "test-content-updated",
 				}
-				err := postsServiceTestInstance.UpdatePost(ctx, &post)
+				err := postsServiceTestInstance.UpdatePost(ctx, &post, 0)
 				return post, err
 			},
 			assertion: func(post entities.Post, err error) {
@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IndexRevisions returns all revisions of a post, newest first, throws entities.ErrPostNotFound if id is invalid.
+func (s *PostsService) IndexRevisions(ctx context.Context, id entities.PostID) ([]entities.Revision, error) {
+	if _, err := s.FindPost(ctx, id); err != nil {
+		return nil, err
+	}
+	var revisions []entities.Revision
+	err := s.reader.Preload("Tags", orderTags).Where("post_id = ?", id).Order("version DESC").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DiffRevisions renders a unified diff between two revisions of a post, throws entities.ErrRevisionNotFound if either
+// of them doesn't belong to the post.
+func (s *PostsService) DiffRevisions(ctx context.Context, id entities.PostID, from, to entities.RevisionID) (string, error) {
+	before, err := s.findRevision(s.reader, id, from)
+	if err != nil {
+		return "", err
+	}
+	after, err := s.findRevision(s.reader, id, to)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(
+		fmt.Sprintf("revision %v (version %v)", before.ID, before.Version),
+		fmt.Sprintf("revision %v (version %v)", after.ID, after.Version),
+		before.Text(), after.Text(),
+	), nil
+}
+
+// FindRevision fetches a revision of a post, throws entities.ErrRevisionNotFound if it doesn't belong to the post.
+func (s *PostsService) FindRevision(ctx context.Context, id entities.PostID, revision entities.RevisionID) (entities.Revision, error) {
+	return s.findRevision(s.reader, id, revision)
+}
+
+// RevertPost restores a post to a snapshot taken by one of its revisions on behalf of author, recording a new
+// revision. Throws entities.ErrPostNotFound if id is invalid, and entities.ErrRevisionNotFound if the revision doesn't
+// belong to the post.
+// Unless version is entities.AnyVersion, throws entities.ErrVersionMismatch if it doesn't match the stored version.
+func (s *PostsService) RevertPost(ctx context.Context, id entities.PostID, revision entities.RevisionID, version entities.Version, author userentities.UserID) (entities.Post, error) {
+	var post entities.Post
+	err := s.writer.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, "id = ?", id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrPostNotFound
+			}
+			return err
+		}
+		if version != entities.AnyVersion && version != post.Version {
+			return entities.ErrVersionMismatch
+		}
+		snapshot, err := s.findRevision(tx, id, revision)
+		if err != nil {
+			return err
+		}
+		title := post.Title
+		post.UserID = snapshot.UserID
+		post.Title = snapshot.Title
+		post.Content = snapshot.Content
+		post.Format = snapshot.Format
+		// Tags deleted since the snapshot was taken are gone from it as well.
+		post.Tags = append(make([]entities.Tag, 0, len(snapshot.Tags)), snapshot.Tags...)
+		post.UpdatedAt = time.Now().UTC()
+		err = s.claimSlug(tx, &post, title, func() error {
+			return s.update(tx, &post, version)
+		})
+		if err != nil {
+			return err
+		}
+		if err = s.assignTags(tx, &post); err != nil {
+			return err
+		}
+		return s.revise(tx, post, author)
+	})
+	if err != nil {
+		return entities.Post{}, unknownUser(err)
+	}
+	return post, nil
+}
+
+// findRevision fetches a revision of a post.
+func (s *PostsService) findRevision(db *gorm.DB, id entities.PostID, revision entities.RevisionID) (entities.Revision, error) {
+	var result entities.Revision
+	err := db.Preload("Tags", orderTags).First(&result, "id = ? AND post_id = ?", revision, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Revision{}, entities.ErrRevisionNotFound
+		}
+		return entities.Revision{}, err
+	}
+	return result, nil
+}
+
+// revise records a revision of a freshly written post, has to be called in the transaction of the write.
+func (s *PostsService) revise(tx *gorm.DB, post entities.Post, author userentities.UserID) error {
+	// Writes that keep current tags leave them unloaded.
+	if post.Tags == nil {
+		if err := tx.Model(&post).Order("name").Association("Tags").Find(&post.Tags); err != nil {
+			return err
+		}
+	}
+	revision := entities.NewRevision(post, author)
+	// Tags are only referenced, never written through a revision.
+	return tx.Omit("Tags.*").Create(&revision).Error
+}
+
+// orderTags orders preloaded tags by name.
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/markup"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_Revisions(t *testing.T) {
+	ctx := context.Background()
+	tag := entities.Tag{Name: "test-revision-tag"}
+	if !assert.NoError(t, postsServiceTestInstance.CreateTag(ctx, &tag)) {
+		return
+	}
+	defer postsServiceTestInstance.DeleteTag(ctx, tag.ID)
+	post := entities.Post{Title: "test-title", Content: "test-content", Format: markup.FormatMarkdown, Tags: []entities.Tag{{Name: tag.Name}}}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+	edited := entities.Post{ID: post.ID, Title: "test-title-new", Content: "test-content", Format: markup.FormatPlain, Tags: []entities.Tag{}}
+	if !assert.NoError(t, postsServiceTestInstance.UpdatePost(ctx, &edited, 0)) {
+		return
+	}
+
+	// Every write is recorded, newest first.
+	revisions, err := postsServiceTestInstance.IndexRevisions(ctx, post.ID)
+	if !assert.NoError(t, err) || !assert.Len(t, revisions, 2) {
+		return
+	}
+	assert.Equal(t, "test-title-new", revisions[0].Title)
+	assert.Equal(t, edited.Version, revisions[0].Version)
+	assert.Nil(t, revisions[0].AuthorID)
+	assert.Equal(t, markup.FormatMarkdown, revisions[1].Format)
+	if assert.Len(t, revisions[1].Tags, 1) {
+		assert.Equal(t, tag.ID, revisions[1].Tags[0].ID)
+	}
+
+	// Diff.
+	diff, err := postsServiceTestInstance.DiffRevisions(ctx, post.ID, revisions[1].ID, revisions[0].ID)
+	if assert.NoError(t, err) {
+		assert.Contains(t, diff, "-title: test-title\n+title: test-title-new\n")
+		assert.Contains(t, diff, "-format: markdown\n-tags: test-revision-tag\n+format: plain\n+tags: \n")
+	}
+	_, err = postsServiceTestInstance.DiffRevisions(ctx, post.ID+1, revisions[1].ID, revisions[0].ID)
+	assert.ErrorIs(t, err, entities.ErrRevisionNotFound)
+
+	// Revert restores format and tags too, and can be conditioned on a version.
+	_, err = postsServiceTestInstance.RevertPost(ctx, post.ID, revisions[1].ID, post.Version, 0)
+	assert.ErrorIs(t, err, entities.ErrVersionMismatch)
+	reverted, err := postsServiceTestInstance.RevertPost(ctx, post.ID, revisions[1].ID, edited.Version, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "test-title", reverted.Title)
+		assert.Equal(t, markup.FormatMarkdown, reverted.Format)
+		if assert.Len(t, reverted.Tags, 1) {
+			assert.Equal(t, tag.ID, reverted.Tags[0].ID)
+		}
+	}
+	revisions, err = postsServiceTestInstance.IndexRevisions(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, revisions, 3)
+	}
+}
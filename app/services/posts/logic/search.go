@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+)
+
+const (
+	// searchSQL ranks and highlights posts matching a tsquery, title (weight A) is ranked above content (weight B).
+	// Highlights are built from HTML-escaped text, so the <mark> tags are the only markup they ever contain.
+	searchSQL = `SELECT posts.*,
+		ts_rank('{0.1, 0.2, 0.4, 1.0}', posts.search_vector, search.query)::float8 AS rank,
+		ts_headline('english', ` + escapedTitleSQL + `, search.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+		ts_headline('english', ` + escapedContentSQL + `, search.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, FragmentDelimiter=" … "') AS snippet
+	FROM posts, (SELECT %v AS query) AS search
+	WHERE posts.search_vector @@ search.query`
+	// escapedTitleSQL and escapedContentSQL escape the characters that are special in HTML text and attributes.
+	escapedTitleSQL   = `replace(replace(replace(replace(replace(posts.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+	escapedContentSQL = `replace(replace(replace(replace(replace(posts.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+)
+
+var (
+	// searchTokens matches quoted phrases and bare words of a search query.
+	searchTokens = regexp.MustCompile(`"([^"]*)"|(\S+)`)
+	// nonWordCharacters matches everything that can't be a part of a tsquery lexeme.
+	nonWordCharacters = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// tsquery compiles a user search query into a parameterized tsquery expression.
+// Quoted phrases have to match as phrases, and bare words are prefix-matched, all parts have to match.
+func tsquery(q string) (string, []any, error) {
+	parts := make([]string, 0)
+	args := make([]any, 0)
+	for _, match := range searchTokens.FindAllStringSubmatch(q, -1) {
+		if phrase := strings.TrimSpace(match[1]); phrase != "" {
+			parts = append(parts, "phraseto_tsquery('english', ?)")
+			args = append(args, phrase)
+			continue
+		}
+		for _, word := range nonWordCharacters.Split(match[2], -1) {
+			if word == "" {
+				continue
+			}
+			parts = append(parts, "to_tsquery('english', ?)")
+			args = append(args, strings.ToLower(word)+":*")
+		}
+	}
+	if len(parts) == 0 {
+		return "", nil, entities.ErrEmptySearchQuery
+	}
+	return "(" + strings.Join(parts, " && ") + ")", args, nil
+}
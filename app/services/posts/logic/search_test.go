@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTsquery(t *testing.T) {
+	cases := []struct {
+		q         string
+		assertion func(string, []any, error)
+	}{
+		// Words and phrases.
+		{
+			q: `News "weekly digest" e-mail`,
+			assertion: func(expression string, args []any, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "(to_tsquery('english', ?) && phraseto_tsquery('english', ?) && to_tsquery('english', ?) && to_tsquery('english', ?))", expression)
+					assert.Equal(t, []any{"news:*", "weekly digest", "e:*", "mail:*"}, args)
+				}
+			},
+		},
+		// Nothing searchable.
+		{
+			q: ` "" !& `,
+			assertion: func(expression string, args []any, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, entities.ErrEmptySearchQuery)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(tsquery(c.q))
+	}
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+)
+
+const (
+	// sitemapPagesSQL numbers pages of published posts ordered by id from 0, and finds the last change of each one.
+	sitemapPagesSQL = `SELECT MAX(updated_at) AS updated_at
+	FROM (
+		SELECT updated_at, (ROW_NUMBER() OVER (ORDER BY id) - 1) / ? AS page FROM posts WHERE status = ?
+	) AS pages
+	GROUP BY page
+	ORDER BY page`
+)
+
+// SitemapPages splits published posts ordered by id into pages of size, returning the last time posts of each page
+// changed, in the order of pages. Removing a published post shifts every page, so it counts as a change of all of them.
+func (s *PostsService) SitemapPages(ctx context.Context, size int) ([]time.Time, error) {
+	var pages []struct {
+		UpdatedAt time.Time `gorm:"column:updated_at"`
+	}
+	if err := s.reader.Raw(sitemapPagesSQL, size, entities.StatusPublished).Scan(&pages).Error; err != nil {
+		return nil, err
+	}
+	removed, err := s.lastRemoval(s.reader)
+	if err != nil {
+		return nil, err
+	}
+	updated := make([]time.Time, 0, len(pages))
+	for _, page := range pages {
+		if page.UpdatedAt.Before(removed) {
+			updated = append(updated, removed)
+			continue
+		}
+		updated = append(updated, page.UpdatedAt.UTC())
+	}
+	return updated, nil
+}
+
+// SitemapPosts returns a page of published posts ordered by id, pages of size are numbered from 1.
+// Only ids, slugs and update times are selected.
+func (s *PostsService) SitemapPosts(ctx context.Context, page, size int) ([]entities.Post, error) {
+	posts := make([]entities.Post, 0)
+	err := s.reader.Select("id", "slug", "updated_at").
+		Where("status = ?", entities.StatusPublished).
+		Order("id").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
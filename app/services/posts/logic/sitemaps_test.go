@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	helpers "github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_Sitemap(t *testing.T) {
+	ctx := context.Background()
+
+	ids := make([]entities.PostID, 0)
+	for _, title := range []string{"test-sitemap-1", "test-sitemap-2", "test-sitemap-draft"} {
+		post := entities.Post{Title: title, Content: "test-content"}
+		if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+			return
+		}
+		defer postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+		if title != "test-sitemap-draft" {
+			if _, err := postsServiceTestInstance.PublishPost(ctx, post.ID, time.Now()); !assert.NoError(t, err) {
+				return
+			}
+		}
+		ids = append(ids, post.ID)
+	}
+
+	// Pages of a single post.
+	pages, err := postsServiceTestInstance.SitemapPages(ctx, 1)
+	if !assert.NoError(t, err) || !assert.GreaterOrEqual(t, len(pages), 2) {
+		return
+	}
+	found := make([]entities.PostID, 0)
+	for page := 1; page <= len(pages); page++ {
+		ps, err := postsServiceTestInstance.SitemapPosts(ctx, page, 1)
+		if assert.NoError(t, err) && assert.Len(t, ps, 1) {
+			assert.NotEmpty(t, ps[0].Slug)
+			assert.Empty(t, ps[0].Title)
+			assert.False(t, pages[page-1].Before(ps[0].UpdatedAt.Truncate(time.Millisecond)))
+			found = append(found, ps[0].ID)
+		}
+	}
+	assert.Subset(t, found, ids[:2])
+	assert.False(t, helpers.Contains(found, ids[2]))
+
+	ps, err := postsServiceTestInstance.SitemapPosts(ctx, len(pages)+1, 1)
+	if assert.NoError(t, err) {
+		assert.Empty(t, ps)
+	}
+
+	// Unpublishing a post shifts pages, so it changes all of them.
+	unpublished, err := postsServiceTestInstance.UnpublishPost(ctx, ids[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	pages, err = postsServiceTestInstance.SitemapPages(ctx, 1)
+	if assert.NoError(t, err) {
+		for _, updated := range pages {
+			assert.False(t, updated.Before(unpublished.UpdatedAt.Truncate(time.Millisecond)))
+		}
+	}
+}
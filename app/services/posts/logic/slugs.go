@@ -0,0 +1,149 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSlugLength bounds the part of a slug derived from a title, in characters.
+	maxSlugLength = 80
+	// maxSlugAttempts bounds how many times a write retries with the next suffix after losing a slug to a concurrent
+	// write.
+	maxSlugAttempts = 5
+	// slugSavePoint names the save point writes roll back to when their slug is taken.
+	slugSavePoint = "slug"
+)
+
+// FindPostBySlug fetches a post by its current or former slug, throws entities.ErrSlugNotFound if slug is unknown.
+// Callers can tell a former slug apart by comparing it to the slug of the returned post.
+func (s *PostsService) FindPostBySlug(ctx context.Context, slug string) (entities.Post, error) {
+	var post entities.Post
+	err := s.reader.Preload("Tags").First(&post, "slug = ?", slug).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.reader.Preload("Tags").First(&post, "id = (SELECT post_id FROM post_slugs WHERE slug = ?)", slug).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Post{}, entities.ErrSlugNotFound
+		}
+		return entities.Post{}, err
+	}
+	return post, nil
+}
+
+// claimSlug derives a slug of a post from its title and runs write, which has to persist it.
+// post.Slug is expected to hold the stored slug and title the stored title, the slug is kept unless the title changed
+// to one the slug wasn't derived from and moved to the history otherwise. Colliding slugs get a numeric suffix, and
+// uniqueness is enforced by the database, so a write that lost its slug to a concurrent one is retried with the next
+// free suffix.
+func (s *PostsService) claimSlug(tx *gorm.DB, post *entities.Post, title string, write func() error) error {
+	previous := post.Slug
+	base := slugify(post.Title)
+	if previous != "" && (post.Title == title || hasSlugBase(previous, base)) {
+		return write()
+	}
+	for attempt := 1; ; attempt++ {
+		slug, err := s.freeSlug(tx, post.ID, base)
+		if err != nil {
+			return err
+		}
+		post.Slug = slug
+		if err = tx.SavePoint(slugSavePoint).Error; err != nil {
+			return err
+		}
+		err = write()
+		if err == nil {
+			break
+		}
+		if attempt == maxSlugAttempts || !slugTaken(err) {
+			return err
+		}
+		if err = tx.RollbackTo(slugSavePoint).Error; err != nil {
+			return err
+		}
+	}
+	// Reclaiming a former slug removes it from the history.
+	err := tx.Exec("DELETE FROM post_slugs WHERE slug = ? AND post_id = ?", post.Slug, post.ID).Error
+	if err != nil || previous == "" {
+		return err
+	}
+	return tx.Exec("INSERT INTO post_slugs (slug, post_id) VALUES (?, ?) ON CONFLICT DO NOTHING", previous, post.ID).Error
+}
+
+// freeSlug returns base, or base with the lowest numeric suffix, that is neither current nor former slug of another
+// post.
+func (s *PostsService) freeSlug(db *gorm.DB, id entities.PostID, base string) (string, error) {
+	var taken []string
+	err := db.Raw(
+		"SELECT slug FROM posts WHERE (slug = ? OR slug LIKE ?) AND id <> ? "+
+			"UNION SELECT slug FROM post_slugs WHERE (slug = ? OR slug LIKE ?) AND post_id <> ?",
+		base, base+"-%", id, base, base+"-%", id,
+	).Scan(&taken).Error
+	if err != nil {
+		return "", err
+	}
+	slug := base
+	for n := 2; helpers.Contains(taken, slug); n++ {
+		slug = fmt.Sprintf("%v-%v", base, n)
+	}
+	return slug, nil
+}
+
+// slugify derives a slug from a title. Letters and digits of any script are lowercased and kept, along with marks
+// that combine with them, anything else collapses into single hyphens. Titles without letters or digits produce
+// "post".
+func slugify(title string) string {
+	var builder strings.Builder
+	length := 0
+	hyphen := false
+	for _, r := range title {
+		if length >= maxSlugLength {
+			break
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || (unicode.Is(unicode.M, r) && length > 0 && !hyphen):
+			if hyphen {
+				builder.WriteRune('-')
+				length++
+				hyphen = false
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			length++
+		default:
+			hyphen = length > 0
+		}
+	}
+	if length == 0 {
+		return "post"
+	}
+	return builder.String()
+}
+
+// hasSlugBase checks whether slug was derived from base, possibly with a numeric suffix.
+func hasSlugBase(slug string, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix := strings.TrimPrefix(slug, base+"-")
+	if suffix == slug {
+		return false
+	}
+	_, err := strconv.ParseUint(suffix, 10, 64)
+	return err == nil
+}
+
+// slugTaken checks whether a write failed on a unique violation of a slug.
+func slugTaken(err error) bool {
+	// Check for duplicate key error, didn't find a check in gorm :(
+	return strings.Contains(err.Error(), "SQLSTATE 23505") && strings.Contains(err.Error(), "slug")
+}
@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Hello, World!", expected: "hello-world"},
+		{title: "  Go 1.18 -- generics  ", expected: "go-1-18-generics"},
+		{title: "Привіт, світе", expected: "привіт-світе"},
+		{title: "Crème brûlée", expected: "crème-brûlée"},
+		{title: "नमस्ते दुनिया", expected: "नमस्ते-दुनिया"},
+		{title: "?!", expected: "post"},
+		{title: strings.Repeat("a", 100), expected: strings.Repeat("a", maxSlugLength)},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, slugify(c.title), c.title)
+	}
+}
+
+func TestHasSlugBase(t *testing.T) {
+	assert.True(t, hasSlugBase("hello", "hello"))
+	assert.True(t, hasSlugBase("hello-2", "hello"))
+	assert.False(t, hasSlugBase("hello-world", "hello"))
+	assert.False(t, hasSlugBase("hello", "hello-world"))
+}
+
+func TestPostsService_Slugs(t *testing.T) {
+	ctx := context.Background()
+	first := entities.Post{Title: "Test Slug", Content: "test-content"}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &first, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, first.ID, entities.AnyVersion)
+	second := entities.Post{Title: "test slug", Content: "test-content"}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &second, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, second.ID, entities.AnyVersion)
+
+	// Collisions are suffixed.
+	assert.Equal(t, "test-slug", first.Slug)
+	assert.Equal(t, "test-slug-2", second.Slug)
+
+	// Edits that keep the title keep the slug.
+	second.Content = "test-content-new"
+	if assert.NoError(t, postsServiceTestInstance.UpdatePost(ctx, &second, 0)) {
+		assert.Equal(t, "test-slug-2", second.Slug)
+	}
+
+	// Renamed posts are still found by former slugs.
+	second.Title = "Test Slug Renamed"
+	if !assert.NoError(t, postsServiceTestInstance.UpdatePost(ctx, &second, 0)) {
+		return
+	}
+	assert.Equal(t, "test-slug-renamed", second.Slug)
+	found, err := postsServiceTestInstance.FindPostBySlug(ctx, "test-slug-2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, second.ID, found.ID)
+		assert.Equal(t, "test-slug-renamed", found.Slug)
+	}
+
+	// Former slugs aren't given to other posts.
+	third := entities.Post{Title: "Test Slug", Content: "test-content"}
+	if assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &third, 0)) {
+		defer postsServiceTestInstance.DeletePost(ctx, third.ID, entities.AnyVersion)
+		assert.Equal(t, "test-slug-3", third.Slug)
+	}
+
+	_, err = postsServiceTestInstance.FindPostBySlug(ctx, "test-slug-missing")
+	assert.ErrorIs(t, err, entities.ErrSlugNotFound)
+}
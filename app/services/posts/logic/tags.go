@@ -0,0 +1,138 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// IndexTags returns all tags with the number of published posts they are assigned to, most used first.
+func (s *PostsService) IndexTags(ctx context.Context) ([]entities.TagCount, error) {
+	var tags []entities.TagCount
+	err := s.reader.Model(&entities.Tag{}).
+		Select("tags.*, COUNT(posts.id) AS count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.status = ?", entities.StatusPublished).
+		Group("tags.id").
+		Order("count DESC, tags.name").
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FindTag returns a tag by id, throws entities.ErrTagNotFound if id is invalid.
+func (s *PostsService) FindTag(ctx context.Context, id entities.TagID) (entities.Tag, error) {
+	var tag entities.Tag
+	err := s.reader.First(&tag, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Tag{}, entities.ErrTagNotFound
+		}
+		return entities.Tag{}, err
+	}
+	return tag, nil
+}
+
+// CreateTag creates a tag, throws entities.ErrDuplicateTag if name is taken.
+func (s *PostsService) CreateTag(ctx context.Context, tag *entities.Tag) error {
+	if err := tag.Validate(); err != nil {
+		return err
+	}
+	tag.ID = 0
+	return duplicateTag(s.writer.Create(tag).Error)
+}
+
+// UpdateTag renames a tag, throws entities.ErrTagNotFound if id is invalid and entities.ErrDuplicateTag if name is
+// taken. Posts the tag is assigned to get a new version, since their representation changes.
+func (s *PostsService) UpdateTag(ctx context.Context, tag *entities.Tag) error {
+	if err := tag.Validate(); err != nil {
+		return err
+	}
+	stored, err := s.FindTag(ctx, tag.ID)
+	if err != nil {
+		return err
+	}
+	tag.CreatedAt = stored.CreatedAt
+	tag.UpdatedAt = time.Now().UTC()
+	return s.writer.Transaction(func(tx *gorm.DB) error {
+		if err := duplicateTag(tx.Save(tag).Error); err != nil {
+			return err
+		}
+		return touchTagged(tx, tag.ID)
+	})
+}
+
+// DeleteTag deletes a tag, unassigning it from all posts, throws entities.ErrTagNotFound if id is invalid.
+// Posts the tag was assigned to get a new version, since their representation changes.
+func (s *PostsService) DeleteTag(ctx context.Context, id entities.TagID) error {
+	tag, err := s.FindTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.writer.Transaction(func(tx *gorm.DB) error {
+		if err := touchTagged(tx, tag.ID); err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+}
+
+// touchTagged bumps versions and modification times of posts a tag is assigned to.
+func touchTagged(tx *gorm.DB, id entities.TagID) error {
+	return tx.Model(&entities.Post{}).
+		Where("id IN (SELECT post_id FROM post_tags WHERE tag_id = ?)", id).
+		Updates(map[string]interface{}{
+			"updated_at": time.Now().UTC(),
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+}
+
+// assignTags replaces tags of a freshly written post, unless they are nil.
+// Tags are referenced by id or by name, and have to exist, throws entities.ErrTagNotFound otherwise.
+func (s *PostsService) assignTags(tx *gorm.DB, post *entities.Post) error {
+	if post.Tags == nil {
+		return nil
+	}
+	ids := make([]entities.TagID, 0)
+	names := make([]string, 0)
+	for _, tag := range post.Tags {
+		if tag.ID != 0 {
+			ids = append(ids, tag.ID)
+		} else {
+			names = append(names, tag.Name)
+		}
+	}
+	tags := make([]entities.Tag, 0)
+	if len(post.Tags) > 0 {
+		err := tx.Where("id IN ? OR name IN ?", ids, names).Order("name").Find(&tags).Error
+		if err != nil {
+			return err
+		}
+	}
+	for _, requested := range post.Tags {
+		if !helpers.ContainsBy(tags, func(tag entities.Tag) bool {
+			return tag.ID == requested.ID || (requested.ID == 0 && tag.Name == requested.Name)
+		}) {
+			return entities.ErrTagNotFound
+		}
+	}
+	post.Tags = tags
+	return tx.Model(post).Association("Tags").Replace(tags)
+}
+
+// duplicateTag translates unique violations of tag names.
+func duplicateTag(err error) error {
+	// Check for duplicate key error, didn't find a check in gorm :(
+	if err != nil && strings.Contains(err.Error(), "SQLSTATE 23505") {
+		return entities.ErrDuplicateTag
+	}
+	return err
+}
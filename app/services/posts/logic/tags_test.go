@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_Tags(t *testing.T) {
+	ctx := context.Background()
+	tag := entities.Tag{Name: "test-tag"}
+	if !assert.NoError(t, postsServiceTestInstance.CreateTag(ctx, &tag)) {
+		return
+	}
+	defer postsServiceTestInstance.DeleteTag(ctx, tag.ID)
+	assert.ErrorIs(t, postsServiceTestInstance.CreateTag(ctx, &entities.Tag{Name: "test-tag"}), entities.ErrDuplicateTag)
+	assert.ErrorIs(t, postsServiceTestInstance.CreateTag(ctx, &entities.Tag{Name: " "}), entities.ErrInvalidTagName)
+
+	// Tags are assigned by name, and have to exist.
+	post := entities.Post{Title: "test-title", Content: "test-content", Tags: []entities.Tag{{Name: "test-tag"}}}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+	missing := entities.Post{Title: "test-title", Content: "test-content", Tags: []entities.Tag{{Name: "test-missing"}}}
+	assert.ErrorIs(t, postsServiceTestInstance.CreatePost(ctx, &missing, 0), entities.ErrTagNotFound)
+	found, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+	if assert.NoError(t, err) && assert.Len(t, found.Tags, 1) {
+		assert.Equal(t, tag.ID, found.Tags[0].ID)
+	}
+
+	// Filter and counts only consider published posts.
+	_, err = postsServiceTestInstance.PublishPost(ctx, post.ID, time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	opts, err := entities.PostResource.Parse(url.Values{"filter[tag]": {"test-tag"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	posts, _, err := postsServiceTestInstance.IndexPosts(ctx, 0, opts)
+	if assert.NoError(t, err) && assert.Len(t, posts, 1) {
+		assert.Equal(t, post.ID, posts[0].ID)
+	}
+	counts, err := postsServiceTestInstance.IndexTags(ctx)
+	if assert.NoError(t, err) {
+		for _, count := range counts {
+			if count.ID == tag.ID {
+				assert.Equal(t, int64(1), count.Count)
+			}
+		}
+	}
+
+	// Rename, posts the tag is assigned to get a new version.
+	before, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tag.Name = "test-tag-new"
+	if assert.NoError(t, postsServiceTestInstance.UpdateTag(ctx, &tag)) {
+		found, err := postsServiceTestInstance.FindTag(ctx, tag.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "test-tag-new", found.Name)
+		}
+		renamed, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, before.Version+1, renamed.Version)
+			assert.NotEqual(t, before.ETag(), renamed.ETag())
+		}
+	}
+
+	// Nil tags keep current ones, an empty list clears them.
+	edited := entities.Post{ID: post.ID, Title: "test-title-new", Content: "test-content"}
+	if assert.NoError(t, postsServiceTestInstance.UpdatePost(ctx, &edited, 0)) {
+		found, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+		if assert.NoError(t, err) {
+			assert.Len(t, found.Tags, 1)
+		}
+	}
+	edited.Tags = []entities.Tag{}
+	if assert.NoError(t, postsServiceTestInstance.UpdatePost(ctx, &edited, 0)) {
+		found, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+		if assert.NoError(t, err) {
+			assert.Empty(t, found.Tags)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exportBatchSize is the number of posts fetched at once while exporting.
+const exportBatchSize = 500
+
+// ExportPosts writes posts matching filters of opts that viewer can see to w in format, along with their tags,
+// ordered by id. CSV files contain columns, entities.TransferColumns if none are provided, throws entities.ErrUnknownColumn for
+// unknown ones. Returns the number of posts written.
+func (s *PostsService) ExportPosts(ctx context.Context, w io.Writer, format entities.TransferFormat, columns []string, viewer userentities.UserID, opts query.Options) (int, error) {
+	encoder, err := newEncoder(w, format, columns)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	batch := make([]entities.Post, 0, exportBatchSize)
+	err = entities.PostResource.Filter(visible(s.reader.Model(&entities.Post{}), viewer), opts).
+		Preload("Tags").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, post := range batch {
+				if err := encoder.Encode(post); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return count, err
+	}
+	return count, encoder.Close()
+}
+
+// ImportPosts reads posts from r and writes them on behalf of author, matching them with stored posts as
+// configured by opts. Every post is written in its own transaction, like CreatePost or UpdatePost would write it,
+// except that its status and tags are imported as well, missing tags are created.
+// Records that fail are listed in the report and the import goes on, errors reading r end the import and are
+// thrown along with the report so far.
+func (s *PostsService) ImportPosts(ctx context.Context, r io.Reader, opts entities.ImportOptions, author userentities.UserID) (entities.ImportReport, error) {
+	report := entities.ImportReport{DryRun: opts.DryRun}
+	if !opts.Mode.Valid() {
+		return report, entities.ErrUnknownImportMode
+	}
+	decoder, err := newDecoder(r, opts.Format, opts.Columns)
+	if err != nil {
+		return report, err
+	}
+	err = s.importPosts(ctx, decoder, opts, author, &report)
+	if opts.Mode == entities.ImportByID && !opts.DryRun && report.Created > 0 {
+		// Posts created with their own ids leave the id sequence behind.
+		syncErr := s.writer.Exec("SELECT setval(pg_get_serial_sequence('posts', 'id'), (SELECT MAX(id) FROM posts))").Error
+		if err == nil {
+			err = syncErr
+		}
+	}
+	if opts.Progress != nil {
+		opts.Progress(report)
+	}
+	return report, err
+}
+
+// importPosts imports every record decoder reads, adding outcomes to report.
+func (s *PostsService) importPosts(ctx context.Context, decoder postDecoder, opts entities.ImportOptions, author userentities.UserID, report *entities.ImportReport) error {
+	for {
+		post, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		report.Records++
+		if err != nil && !errors.Is(err, entities.ErrMalformedRecord) {
+			report.Failed++
+			report.Errors = append(report.Errors, entities.ImportError{Record: report.Records, Error: err.Error()})
+			return err
+		}
+		if err == nil {
+			var created bool
+			created, err = s.importPost(ctx, &post, opts.Mode, opts.DryRun, author)
+			switch {
+			case err != nil:
+			case created:
+				report.Created++
+			default:
+				report.Updated++
+			}
+		}
+		if err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, entities.ImportError{Record: report.Records, Error: err.Error()})
+		}
+		if opts.Progress != nil && report.Records%entities.ImportProgressInterval == 0 {
+			opts.Progress(*report)
+		}
+	}
+}
+
+// importPost writes an imported post, or only checks it on a dry run, returns whether it is a new post.
+func (s *PostsService) importPost(ctx context.Context, post *entities.Post, mode entities.ImportMode, dryRun bool, author userentities.UserID) (bool, error) {
+	if err := post.Validate(); err != nil {
+		return false, err
+	}
+	if _, ok := transitions[post.Status]; post.Status != "" && !ok {
+		return false, entities.ErrInvalidStatus
+	}
+	slug := post.Slug
+	if mode == entities.ImportBySlug && slug != "" && slugify(slug) != slug {
+		return false, entities.ErrInvalidSlug
+	}
+	stored, found, err := s.match(ctx, *post, mode)
+	if err != nil || dryRun {
+		return !found, err
+	}
+	status, publishedAt, scheduledAt := post.Status, post.PublishedAt, post.ScheduledAt
+	err = s.writer.Transaction(func(tx *gorm.DB) error {
+		if err := createTags(tx, post.Tags); err != nil {
+			return err
+		}
+		var err error
+		if found {
+			post.ID, post.Version = stored.ID, entities.AnyVersion
+			err = s.replace(tx, post, author)
+		} else {
+			if mode == entities.ImportBySlug {
+				post.ID = 0
+			}
+			err = s.create(tx, post, author)
+			if err == nil && mode == entities.ImportBySlug && slug != "" && slug != post.Slug {
+				// New posts keep their slugs, so that importing them again matches them. Unmatched slugs aren't
+				// current or former slugs of any post, a concurrent write claiming one is rejected by the database.
+				post.Slug = slug
+				err = tx.Model(post).UpdateColumn("slug", slug).Error
+			}
+		}
+		if err != nil || status == "" {
+			return err
+		}
+		post.Status, post.PublishedAt, post.ScheduledAt = status, publishedAt, scheduledAt
+		err = tx.Model(post).UpdateColumns(map[string]interface{}{
+			"status":       post.Status,
+			"published_at": post.PublishedAt,
+			"scheduled_at": post.ScheduledAt,
+		}).Error
+		if err != nil || !found || stored.Status != entities.StatusPublished || status == entities.StatusPublished {
+			return err
+		}
+		return s.markRemoval(tx)
+	})
+	return !found, err
+}
+
+// match finds the stored post an imported one updates, if any.
+func (s *PostsService) match(ctx context.Context, post entities.Post, mode entities.ImportMode) (entities.Post, bool, error) {
+	var stored entities.Post
+	var err error
+	switch {
+	case mode == entities.ImportByID && post.ID != 0:
+		stored, err = s.find(s.writer, post.ID)
+	case mode == entities.ImportBySlug && post.Slug != "":
+		stored, err = s.FindPostBySlug(ctx, post.Slug)
+	default:
+		return entities.Post{}, false, nil
+	}
+	if errors.Is(err, entities.ErrPostNotFound) || errors.Is(err, entities.ErrSlugNotFound) {
+		return entities.Post{}, false, nil
+	}
+	return stored, err == nil, err
+}
+
+// createTags creates tags that don't exist yet, tags are referenced by name since ids differ between environments.
+func createTags(tx *gorm.DB, tags []entities.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	missing := make([]entities.Tag, 0, len(tags))
+	for i := range tags {
+		tags[i].ID = 0
+		if err := tags[i].Validate(); err != nil {
+			return err
+		}
+		missing = append(missing, entities.Tag{Name: tags[i].Name})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error
+}
@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostsService_Transfer(t *testing.T) {
+	ctx := context.Background()
+
+	post := entities.Post{Title: "test-transfer", Content: "test-content", Tags: []entities.Tag{}}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, 0)) {
+		return
+	}
+	defer postsServiceTestInstance.DeletePost(ctx, post.ID, entities.AnyVersion)
+	opts, err := entities.PostResource.Parse(map[string][]string{"filter[slug]": {post.Slug}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Filtered export.
+	var buffer bytes.Buffer
+	count, err := postsServiceTestInstance.ExportPosts(ctx, &buffer, entities.TransferCSV, []string{"id", "slug", "title"}, entities.AnyViewer, opts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "id,slug,title\n"+formatColumn(post, "id")+","+post.Slug+",test-transfer\n", buffer.String())
+	}
+	// Drafts are only exported to viewers who can see them.
+	buffer.Reset()
+	count, err = postsServiceTestInstance.ExportPosts(ctx, &buffer, entities.TransferCSV, []string{"id"}, 0, opts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, count)
+	}
+	_, err = postsServiceTestInstance.ExportPosts(ctx, &buffer, entities.TransferCSV, []string{"body"}, entities.AnyViewer, query.Options{})
+	assert.ErrorIs(t, err, entities.ErrUnknownColumn)
+
+	// Dry runs validate records without writing them.
+	source := "slug,title,status,tags\n" + post.Slug + ",test-transfer,published,test-imported\n,,draft,\n,test-transfer-new,unknown,\n"
+	report, err := postsServiceTestInstance.ImportPosts(ctx, strings.NewReader(source), entities.ImportOptions{
+		Format: entities.TransferCSV,
+		Mode:   entities.ImportBySlug,
+		DryRun: true,
+	}, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, entities.ImportReport{
+			Records: 3, Updated: 1, Failed: 2, DryRun: true,
+			Errors: []entities.ImportError{
+				{Record: 2, Error: entities.ErrInvalidTitle.Error()},
+				{Record: 3, Error: entities.ErrInvalidStatus.Error()},
+			},
+		}, report)
+	}
+	stored, err := postsServiceTestInstance.FindPost(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, entities.StatusDraft, stored.Status)
+	}
+
+	// Imports update matched posts along with their status, creating missing tags.
+	var progress []entities.ImportReport
+	report, err = postsServiceTestInstance.ImportPosts(ctx, strings.NewReader(source), entities.ImportOptions{
+		Format: entities.TransferCSV,
+		Mode:   entities.ImportBySlug,
+		Progress: func(report entities.ImportReport) {
+			progress = append(progress, report)
+		},
+	}, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, []entities.ImportReport{report}, progress)
+	}
+	stored, err = postsServiceTestInstance.FindPost(ctx, post.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, entities.StatusPublished, stored.Status)
+		if assert.Len(t, stored.Tags, 1) {
+			assert.Equal(t, "test-imported", stored.Tags[0].Name)
+			defer postsServiceTestInstance.DeleteTag(ctx, stored.Tags[0].ID)
+		}
+	}
+
+	// Unmatched posts are created with their slugs, so that importing them again updates them.
+	source = `[{"slug": "test-transfer-missing", "title": "test-transfer-created"}]`
+	for _, expected := range []entities.ImportReport{{Records: 1, Created: 1}, {Records: 1, Updated: 1}} {
+		report, err = postsServiceTestInstance.ImportPosts(ctx, strings.NewReader(source), entities.ImportOptions{
+			Format: entities.TransferJSON,
+			Mode:   entities.ImportBySlug,
+		}, 0)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, report)
+		}
+	}
+	created, err := postsServiceTestInstance.FindPostBySlug(ctx, "test-transfer-missing")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "test-transfer-missing", created.Slug)
+		assert.Equal(t, entities.StatusDraft, created.Status)
+		defer postsServiceTestInstance.DeletePost(ctx, created.ID, entities.AnyVersion)
+	}
+	report, err = postsServiceTestInstance.ImportPosts(ctx, strings.NewReader(`[{"slug": "Test Transfer", "title": "test-transfer"}]`), entities.ImportOptions{
+		Format: entities.TransferJSON,
+		Mode:   entities.ImportBySlug,
+	}, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []entities.ImportError{{Record: 1, Error: entities.ErrInvalidSlug.Error()}}, report.Errors)
+	}
+
+	_, err = postsServiceTestInstance.ImportPosts(ctx, strings.NewReader(""), entities.ImportOptions{Format: entities.TransferJSON}, 0)
+	assert.ErrorIs(t, err, entities.ErrUnknownImportMode)
+}
@@ -0,0 +1,28 @@
+package query
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnknownField is thrown when a field that is not whitelisted for a resource is requested.
+	ErrUnknownField = errors.New("unknown field requested")
+	// ErrUnknownInclude is thrown when a related resource that is not whitelisted for a resource is requested.
+	ErrUnknownInclude = errors.New("unknown related resource requested")
+	// ErrMalformedFilter is thrown when a filter param doesn't follow filter[field][operator] syntax.
+	ErrMalformedFilter = errors.New("malformed filter")
+	// ErrUnknownFilter is thrown when filtering by a field that is not whitelisted for a resource is requested.
+	ErrUnknownFilter = errors.New("filtering by this field is not supported")
+	// ErrUnsupportedOperator is thrown when a filter operator is not whitelisted for a field.
+	ErrUnsupportedOperator = errors.New("filter operator is not supported")
+	// ErrInvalidFilterValue is thrown when a filter value doesn't match the field type.
+	ErrInvalidFilterValue = errors.New("invalid filter value")
+	// ErrUnknownSort is thrown when sorting by a field that is not whitelisted for a resource is requested.
+	ErrUnknownSort = errors.New("sorting by this field is not supported")
+	// ErrInvalidLimit is thrown when page size is not a positive integer.
+	ErrInvalidLimit = errors.New("limit has to be a positive integer")
+	// ErrInvalidCursor is thrown when a cursor is malformed or was issued for a different sort order.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrConflictingCursors is thrown when both after and before cursors are provided.
+	ErrConflictingCursors = errors.New("after and before cursors can't be used together")
+)
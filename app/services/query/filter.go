@@ -0,0 +1,186 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// Operator is a comparison clients can use in filters.
+type Operator string
+
+const (
+	OperatorEq       Operator = "eq"
+	OperatorNe       Operator = "ne"
+	OperatorGt       Operator = "gt"
+	OperatorGte      Operator = "gte"
+	OperatorLt       Operator = "lt"
+	OperatorLte      Operator = "lte"
+	OperatorIn       Operator = "in"
+	OperatorContains Operator = "contains"
+)
+
+// Type is a type of filterable field, used to validate filter values before they reach the database.
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeInteger
+	TypeTime
+)
+
+var (
+	// operators maps filter operators to their sql templates.
+	operators = map[Operator]string{
+		OperatorEq:       "%v = ?",
+		OperatorNe:       "%v <> ?",
+		OperatorGt:       "%v > ?",
+		OperatorGte:      "%v >= ?",
+		OperatorLt:       "%v < ?",
+		OperatorLte:      "%v <= ?",
+		OperatorIn:       "%v IN ?",
+		OperatorContains: "%v ILIKE ?",
+	}
+	// filterParam matches filter[field] and filter[field][operator] query params.
+	filterParam = regexp.MustCompile(`^filter\[(\w+)](?:\[(\w+)])?$`)
+	// timeLayouts lists layouts accepted for TypeTime filter values.
+	timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+	// likeEscaper escapes ILIKE wildcards in user input.
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// Filter declares a filterable field.
+type Filter struct {
+	// Type is used to validate filter values.
+	Type Type
+	// Operators whitelists comparisons allowed for the field.
+	Operators []Operator
+	// Column overrides the compared column, used for fields that don't map to a column of the resource.
+	Column string
+	// Wrap is an optional sql template the comparison is embedded in (as %v), used to filter by related records.
+	Wrap string
+}
+
+// Condition is a single parsed filter.
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    any
+}
+
+// parseFilters reads ?filter[field]=value and ?filter[field][operator]=value params.
+func (r Resource) parseFilters(values url.Values) ([]Condition, error) {
+	keys := helpers.Keys(values)
+	sort.Strings(keys)
+	conditions := make([]Condition, 0)
+	for _, key := range keys {
+		match := filterParam.FindStringSubmatch(key)
+		if match == nil {
+			if strings.HasPrefix(key, "filter") {
+				return nil, fmt.Errorf("%w: %v", ErrMalformedFilter, key)
+			}
+			continue
+		}
+		field, operator := match[1], Operator(match[2])
+		if operator == "" {
+			operator = OperatorEq
+		}
+		filter, ok := r.Filterable[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownFilter, field)
+		}
+		if !helpers.Contains(filter.Operators, operator) {
+			return nil, fmt.Errorf("%w: %v[%v]", ErrUnsupportedOperator, field, operator)
+		}
+		for _, raw := range values[key] {
+			value, err := filter.parse(operator, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", err, field)
+			}
+			conditions = append(conditions, Condition{Field: field, Operator: operator, Value: value})
+		}
+	}
+	return conditions, nil
+}
+
+// parse validates a raw filter value, converting it to a database parameter.
+func (f Filter) parse(operator Operator, raw string) (any, error) {
+	if operator == OperatorIn {
+		values := make([]any, 0)
+		for _, item := range splitList(raw) {
+			value, err := f.parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return nil, ErrInvalidFilterValue
+		}
+		return values, nil
+	}
+	if operator == OperatorContains {
+		return "%" + likeEscaper.Replace(raw) + "%", nil
+	}
+	return f.parseValue(raw)
+}
+
+// parseValue validates a single raw value against the field type.
+func (f Filter) parseValue(raw string) (any, error) {
+	switch f.Type {
+	case TypeInteger:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidFilterValue
+		}
+		return value, nil
+	case TypeTime:
+		for _, layout := range timeLayouts {
+			if value, err := time.Parse(layout, raw); err == nil {
+				return value.UTC(), nil
+			}
+		}
+		return nil, ErrInvalidFilterValue
+	}
+	return raw, nil
+}
+
+// parseSorts reads ?sort=-field,field param.
+func (r Resource) parseSorts(values url.Values) ([]Sort, error) {
+	sorts := make([]Sort, 0)
+	for _, field := range splitList(values.Get("sort")) {
+		s := Sort{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if !helpers.Contains(r.Sortable, s.Field) {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownSort, s.Field)
+		}
+		if helpers.ContainsBy(sorts, func(existing Sort) bool { return existing.Field == s.Field }) {
+			continue
+		}
+		sorts = append(sorts, s)
+	}
+	return sorts, nil
+}
+
+// Filter compiles parsed conditions into parameterized gorm clauses.
+func (r Resource) Filter(db *gorm.DB, opts Options) *gorm.DB {
+	for _, c := range opts.Filters {
+		filter := r.Filterable[c.Field]
+		column := r.Fields[c.Field]
+		if filter.Column != "" {
+			column = filter.Column
+		}
+		condition := fmt.Sprintf(operators[c.Operator], column)
+		if filter.Wrap != "" {
+			condition = fmt.Sprintf(filter.Wrap, condition)
+		}
+		db = db.Where(condition, c.Value)
+	}
+	return db
+}
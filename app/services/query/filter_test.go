@@ -0,0 +1,152 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	filterTestResource = Resource{
+		Fields: map[string]string{
+			"id":         "id",
+			"title":      "title",
+			"user_id":    "user_id",
+			"created_at": "created_at",
+		},
+		Filterable: map[string]Filter{
+			"user_id":    {Type: TypeInteger, Operators: []Operator{OperatorEq, OperatorIn}},
+			"title":      {Type: TypeString, Operators: []Operator{OperatorContains}},
+			"created_at": {Type: TypeTime, Operators: []Operator{OperatorGte, OperatorLt}},
+			"tag": {
+				Type:      TypeString,
+				Operators: []Operator{OperatorEq},
+				Column:    "tags.name",
+				Wrap:      "id IN (SELECT record_id FROM tags WHERE %v)",
+			},
+		},
+		Sortable: []string{"title", "created_at"},
+	}
+)
+
+func TestResource_parseFilters(t *testing.T) {
+	cases := []struct {
+		values    url.Values
+		assertion func([]Condition, error)
+	}{
+		// Valid.
+		{
+			values: url.Values{
+				"filter[user_id]":         {"3"},
+				"filter[created_at][gte]": {"2026-01-01"},
+				"filter[title][contains]": {"50%_off"},
+				"unrelated":               {"value"},
+			},
+			assertion: func(conditions []Condition, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, []Condition{
+						{Field: "created_at", Operator: OperatorGte, Value: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+						{Field: "title", Operator: OperatorContains, Value: `%50\%\_off%`},
+						{Field: "user_id", Operator: OperatorEq, Value: int64(3)},
+					}, conditions)
+				}
+			},
+		},
+		// In.
+		{
+			values: url.Values{"filter[user_id][in]": {"1,2"}},
+			assertion: func(conditions []Condition, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, []Condition{{Field: "user_id", Operator: OperatorIn, Value: []any{int64(1), int64(2)}}}, conditions)
+				}
+			},
+		},
+		// Unknown field.
+		{
+			values: url.Values{"filter[content]": {"x"}},
+			assertion: func(conditions []Condition, err error) {
+				assert.ErrorIs(t, err, ErrUnknownFilter)
+			},
+		},
+		// Unsupported operator.
+		{
+			values: url.Values{"filter[created_at][eq]": {"2026-01-01"}},
+			assertion: func(conditions []Condition, err error) {
+				assert.ErrorIs(t, err, ErrUnsupportedOperator)
+			},
+		},
+		// Invalid value.
+		{
+			values: url.Values{"filter[user_id]": {"three"}},
+			assertion: func(conditions []Condition, err error) {
+				assert.ErrorIs(t, err, ErrInvalidFilterValue)
+			},
+		},
+		// Malformed.
+		{
+			values: url.Values{"filter[user_id": {"3"}},
+			assertion: func(conditions []Condition, err error) {
+				assert.ErrorIs(t, err, ErrMalformedFilter)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(filterTestResource.parseFilters(c.values))
+	}
+}
+
+func TestResource_parseSorts(t *testing.T) {
+	cases := []struct {
+		values    url.Values
+		assertion func([]Sort, error)
+	}{
+		// Valid.
+		{
+			values: url.Values{"sort": {"-created_at,title"}},
+			assertion: func(sorts []Sort, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, []Sort{{Field: "created_at", Desc: true}, {Field: "title"}}, sorts)
+				}
+			},
+		},
+		// Unknown.
+		{
+			values: url.Values{"sort": {"content"}},
+			assertion: func(sorts []Sort, err error) {
+				assert.ErrorIs(t, err, ErrUnknownSort)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(filterTestResource.parseSorts(c.values))
+	}
+}
+
+func TestResource_Filter(t *testing.T) {
+	db := dryRun(t)
+	opts, err := filterTestResource.Parse(url.Values{"filter[user_id][in]": {"1,2"}, "filter[title][contains]": {"x"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var records []testRecord
+	statement := filterTestResource.Filter(db.Session(&gorm.Session{}), opts).Find(&records).Statement
+	assert.Equal(t, `SELECT * FROM "records" WHERE title ILIKE $1 AND user_id IN ($2,$3)`, statement.SQL.String())
+	assert.Equal(t, []any{"%x%", int64(1), int64(2)}, statement.Vars)
+}
+
+func TestResource_Filter_wrapped(t *testing.T) {
+	db := dryRun(t)
+	opts, err := filterTestResource.Parse(url.Values{"filter[tag]": {"go"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var records []testRecord
+	statement := filterTestResource.Filter(db.Session(&gorm.Session{}), opts).Find(&records).Statement
+	assert.Equal(t, `SELECT * FROM "records" WHERE id IN (SELECT record_id FROM tags WHERE tags.name = $1)`, statement.SQL.String())
+	assert.Equal(t, []any{"go"}, statement.Vars)
+}
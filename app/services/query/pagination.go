@@ -0,0 +1,270 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLimit is the page size used when neither client nor resource specify one.
+	DefaultLimit = 20
+	// MaxLimit is the biggest page size used when resource doesn't specify one.
+	MaxLimit = 100
+	// tiebreaker is the json field that makes every sort order total.
+	tiebreaker = "id"
+)
+
+// Sort is a single sort key.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Page describes which slice of a list a client requested.
+type Page struct {
+	// Limit is the maximum number of records to return.
+	Limit int
+	// After is a cursor of the record the page starts after.
+	After *Cursor
+	// Before is a cursor of the record the page ends before.
+	Before *Cursor
+	// Total requests the total number of records matching the query.
+	Total bool
+}
+
+// PageInfo describes how to fetch neighbouring pages.
+type PageInfo struct {
+	// Next is an encoded cursor of the next page, empty if there is none.
+	Next string
+	// Prev is an encoded cursor of the previous page, empty if there is none.
+	Prev string
+	// Total is the number of records matching the query, only set when requested.
+	Total *int64
+}
+
+// Cursor is a position in a sorted list, it is opaque to clients.
+type Cursor struct {
+	// Sort is a signature of the sort order the cursor was issued for.
+	Sort string `json:"s"`
+	// Values are values of sort keys of the record the cursor points at.
+	Values []any `json:"v"`
+}
+
+// Encode serializes the cursor into an opaque url-safe string.
+func (c Cursor) Encode() string {
+	marshalled, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(marshalled)
+}
+
+// DecodeCursor deserializes a cursor produced by Cursor.Encode.
+func DecodeCursor(raw string) (*Cursor, error) {
+	marshalled, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(marshalled))
+	decoder.UseNumber()
+	var cursor Cursor
+	if err = decoder.Decode(&cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	for i, value := range cursor.Values {
+		if number, ok := value.(json.Number); ok {
+			if integer, err := number.Int64(); err == nil {
+				cursor.Values[i] = integer
+			} else if float, err := number.Float64(); err == nil {
+				cursor.Values[i] = float
+			}
+		}
+	}
+	return &cursor, nil
+}
+
+// parsePage reads ?limit=, ?after=, ?before= and ?total= params.
+func (r Resource) parsePage(values url.Values) (Page, error) {
+	page := Page{Limit: r.defaultLimit()}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Page{}, fmt.Errorf("%w: %v", ErrInvalidLimit, raw)
+		}
+		page.Limit = helpers.Min([]int{limit, r.maxLimit()})
+	}
+	if raw := values.Get("after"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		if err != nil {
+			return Page{}, err
+		}
+		page.After = cursor
+	}
+	if raw := values.Get("before"); raw != "" {
+		if page.After != nil {
+			return Page{}, ErrConflictingCursors
+		}
+		cursor, err := DecodeCursor(raw)
+		if err != nil {
+			return Page{}, err
+		}
+		page.Before = cursor
+	}
+	page.Total, _ = strconv.ParseBool(values.Get("total"))
+	return page, nil
+}
+
+// defaultLimit returns resource page size, falling back to DefaultLimit.
+func (r Resource) defaultLimit() int {
+	if r.DefaultLimit > 0 {
+		return r.DefaultLimit
+	}
+	return DefaultLimit
+}
+
+// maxLimit returns resource maximum page size, falling back to MaxLimit.
+func (r Resource) maxLimit() int {
+	if r.MaxLimit > 0 {
+		return r.MaxLimit
+	}
+	return MaxLimit
+}
+
+// sorts returns the requested sort order (or resource default), made total by the tiebreaker.
+func (r Resource) sorts(opts Options) []Sort {
+	sorts := opts.Sorts
+	if len(sorts) == 0 {
+		sorts = r.DefaultSort
+	}
+	sorts = append([]Sort{}, sorts...)
+	if !helpers.ContainsBy(sorts, func(s Sort) bool { return s.Field == tiebreaker }) {
+		desc := len(sorts) > 0 && sorts[len(sorts)-1].Desc
+		sorts = append(sorts, Sort{Field: tiebreaker, Desc: desc})
+	}
+	return sorts
+}
+
+// signature identifies a sort order, so that cursors can't be reused across orders.
+func signature(sorts []Sort) string {
+	return strings.Join(helpers.Map(sorts, func(s Sort, _ int) string {
+		if s.Desc {
+			return "-" + s.Field
+		}
+		return s.Field
+	}), ",")
+}
+
+// Paginate fetches a single page of records of type T, applying projection, includes, filters and sort order.
+func Paginate[T any](db *gorm.DB, r Resource, opts Options) ([]T, PageInfo, error) {
+	var info PageInfo
+	db = r.Filter(db, opts)
+	if opts.Page.Total {
+		var total int64
+		if err := db.Session(&gorm.Session{}).Model(new(T)).Count(&total).Error; err != nil {
+			return nil, PageInfo{}, err
+		}
+		info.Total = &total
+	}
+
+	sorts := r.sorts(opts)
+	backward := opts.Page.Before != nil
+	cursor := opts.Page.After
+	if backward {
+		cursor = opts.Page.Before
+	}
+	if cursor != nil {
+		if cursor.Sort != signature(sorts) || len(cursor.Values) != len(sorts) {
+			return nil, PageInfo{}, ErrInvalidCursor
+		}
+		condition, args := r.keyset(sorts, cursor.Values, backward)
+		db = db.Where(condition, args...)
+	}
+	for _, s := range sorts {
+		desc := s.Desc != backward
+		db = db.Order(fmt.Sprintf("%v %v", r.Fields[s.Field], helpers.Ternary(desc, "DESC", "ASC")))
+	}
+
+	limit := opts.Page.Limit
+	if limit <= 0 {
+		limit = r.defaultLimit()
+	}
+	opts.required = append(opts.required, helpers.Map(sorts, func(s Sort, _ int) string { return s.Field })...)
+	var records []T
+	if err := r.Apply(db, opts).Limit(limit + 1).Find(&records).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	more := len(records) > limit
+	if more {
+		records = records[:limit]
+	}
+	if backward {
+		records = helpers.Reverse(records)
+	}
+	if len(records) == 0 {
+		return records, info, nil
+	}
+
+	first := Cursor{Sort: signature(sorts), Values: sortValues(records[0], sorts)}
+	last := Cursor{Sort: signature(sorts), Values: sortValues(records[len(records)-1], sorts)}
+	if (!backward && more) || (backward && cursor != nil) {
+		info.Next = last.Encode()
+	}
+	if (backward && more) || (!backward && cursor != nil) {
+		info.Prev = first.Encode()
+	}
+	return records, info, nil
+}
+
+// keyset builds a parameterized condition selecting records strictly after (or before) provided sort key values.
+func (r Resource) keyset(sorts []Sort, values []any, backward bool) (string, []any) {
+	disjunction := make([]string, 0, len(sorts))
+	args := make([]any, 0, len(sorts)*(len(sorts)+1)/2)
+	for i, s := range sorts {
+		conjunction := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conjunction = append(conjunction, r.Fields[sorts[j].Field]+" = ?")
+			args = append(args, values[j])
+		}
+		operator := helpers.Ternary(s.Desc != backward, "<", ">")
+		conjunction = append(conjunction, fmt.Sprintf("%v %v ?", r.Fields[s.Field], operator))
+		args = append(args, values[i])
+		disjunction = append(disjunction, "("+strings.Join(conjunction, " AND ")+")")
+	}
+	return strings.Join(disjunction, " OR "), args
+}
+
+// sortValues extracts values of sort keys from a record, looking fields up by json name.
+func sortValues(record any, sorts []Sort) []any {
+	v := reflect.Indirect(reflect.ValueOf(record))
+	values := make([]any, len(sorts))
+	for i, s := range sorts {
+		if field, ok := fieldByJSONName(v, s.Field); ok {
+			values[i] = field.Interface()
+		}
+	}
+	return values
+}
+
+// fieldByJSONName finds a struct field by its json name, descending into embedded structs.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			if field, ok := fieldByJSONName(v.Field(i), name); ok {
+				return field, true
+			}
+			continue
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
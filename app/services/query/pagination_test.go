@@ -0,0 +1,121 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	cases := []struct {
+		raw       string
+		assertion func(*Cursor, error)
+	}{
+		// Round trip.
+		{
+			raw: Cursor{Sort: "-created_at,-id", Values: []any{"2026-01-01T00:00:00Z", 42}}.Encode(),
+			assertion: func(cursor *Cursor, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "-created_at,-id", cursor.Sort)
+					assert.Equal(t, []any{"2026-01-01T00:00:00Z", int64(42)}, cursor.Values)
+				}
+			},
+		},
+		// Garbage.
+		{
+			raw: "not-a-cursor",
+			assertion: func(cursor *Cursor, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrInvalidCursor)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(DecodeCursor(c.raw))
+	}
+}
+
+func TestResource_parsePage(t *testing.T) {
+	resource := Resource{MaxLimit: 50}
+	cursor := Cursor{Sort: "-id", Values: []any{1}}.Encode()
+	cases := []struct {
+		values    url.Values
+		assertion func(Page, error)
+	}{
+		// Defaults.
+		{
+			values: url.Values{},
+			assertion: func(page Page, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, Page{Limit: DefaultLimit}, page)
+				}
+			},
+		},
+		// Limit is capped, total requested.
+		{
+			values: url.Values{"limit": {"500"}, "total": {"true"}, "after": {cursor}},
+			assertion: func(page Page, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, 50, page.Limit)
+					assert.True(t, page.Total)
+					assert.NotNil(t, page.After)
+				}
+			},
+		},
+		// Invalid limit.
+		{
+			values: url.Values{"limit": {"-1"}},
+			assertion: func(page Page, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrInvalidLimit)
+				}
+			},
+		},
+		// Both cursors.
+		{
+			values: url.Values{"after": {cursor}, "before": {cursor}},
+			assertion: func(page Page, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrConflictingCursors)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(resource.parsePage(c.values))
+	}
+}
+
+func TestResource_keyset(t *testing.T) {
+	resource := Resource{Fields: map[string]string{"id": "id", "created_at": "created_at"}}
+	sorts := resource.sorts(Options{Sorts: []Sort{{Field: "created_at", Desc: true}}})
+	cases := []struct {
+		backward  bool
+		assertion func(string, []any)
+	}{
+		// Forward.
+		{
+			backward: false,
+			assertion: func(condition string, args []any) {
+				assert.Equal(t, "(created_at < ?) OR (created_at = ? AND id < ?)", condition)
+				assert.Equal(t, []any{"t", "t", 1}, args)
+			},
+		},
+		// Backward.
+		{
+			backward: true,
+			assertion: func(condition string, args []any) {
+				assert.Equal(t, "(created_at > ?) OR (created_at = ? AND id > ?)", condition)
+				assert.Equal(t, []any{"t", "t", 1}, args)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(resource.keyset(sorts, []any{"t", 1}, c.backward))
+	}
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	helpers "github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// Resource is a per-resource whitelist of what clients are allowed to query.
+type Resource struct {
+	// Fields maps json field names to database columns.
+	Fields map[string]string
+	// Includes maps include names to gorm associations.
+	Includes map[string]Include
+	// Filterable declares fields (keys of Fields) clients can filter by.
+	Filterable map[string]Filter
+	// Sortable lists fields (keys of Fields) clients can sort by.
+	Sortable []string
+	// DefaultSort is the sort order used when client doesn't request one.
+	DefaultSort []Sort
+	// DefaultLimit is the page size used when client doesn't request one, DefaultLimit is used when zero.
+	DefaultLimit int
+	// MaxLimit is the biggest page size a client can request, MaxLimit is used when zero.
+	MaxLimit int
+}
+
+// Include describes a related resource that can be embedded.
+type Include struct {
+	// Association is the name of the gorm association to preload.
+	Association string
+	// Requires lists json fields the preload depends on, they are always selected when the include is requested.
+	Requires []string
+}
+
+// Options wraps everything a client can ask of a list or fetch endpoint.
+type Options struct {
+	// Fields is a list of json fields to render, all fields are rendered when empty.
+	Fields []string
+	// Includes is a list of related resources to embed.
+	Includes []string
+	// Filters is a list of conditions every returned record satisfies.
+	Filters []Condition
+	// Sorts is the requested sort order, resource default is used when empty.
+	Sorts []Sort
+	// Page describes the requested slice of the list.
+	Page Page
+
+	// required lists json fields that have to be selected regardless of the projection.
+	required []string
+}
+
+// Parse reads ?fields=, ?include=, filtering, sorting and paging params, validating them against the resource whitelist.
+func (r Resource) Parse(values url.Values) (Options, error) {
+	var opts Options
+	filters, err := r.parseFilters(values)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Filters = filters
+	sorts, err := r.parseSorts(values)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Sorts = sorts
+	page, err := r.parsePage(values)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Page = page
+	for _, field := range splitList(values.Get("fields")) {
+		if _, ok := r.Fields[field]; !ok {
+			return Options{}, fmt.Errorf("%w: %v", ErrUnknownField, field)
+		}
+		if !helpers.Contains(opts.Fields, field) {
+			opts.Fields = append(opts.Fields, field)
+		}
+	}
+	for _, include := range splitList(values.Get("include")) {
+		if _, ok := r.Includes[include]; !ok {
+			return Options{}, fmt.Errorf("%w: %v", ErrUnknownInclude, include)
+		}
+		if !helpers.Contains(opts.Includes, include) {
+			opts.Includes = append(opts.Includes, include)
+		}
+	}
+	return opts, nil
+}
+
+// Apply pushes the projection and preloads down into a gorm query.
+func (r Resource) Apply(db *gorm.DB, opts Options) *gorm.DB {
+	if len(opts.Fields) > 0 {
+		fields := append(append([]string{}, opts.Fields...), opts.required...)
+		for _, include := range opts.Includes {
+			fields = append(fields, r.Includes[include].Requires...)
+		}
+		columns := helpers.Map(helpers.Uniq(fields), func(field string, _ int) string { return r.Fields[field] })
+		db = db.Select(columns)
+	}
+	for _, include := range opts.Includes {
+		db = db.Preload(r.Includes[include].Association)
+	}
+	return db
+}
+
+// Keys returns json keys that should be rendered for opts, nil means everything.
+func (opts Options) Keys() []string {
+	if len(opts.Fields) == 0 {
+		return nil
+	}
+	return append(append([]string{}, opts.Fields...), opts.Includes...)
+}
+
+// splitList splits a comma-separated query param, dropping empty values.
+func splitList(raw string) []string {
+	values := helpers.Map(strings.Split(raw, ","), func(s string, _ int) string { return strings.TrimSpace(s) })
+	return helpers.Filter(values, func(s string, _ int) bool { return s != "" })
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	testResource = Resource{
+		Fields: map[string]string{
+			"id":      "id",
+			"title":   "title",
+			"user_id": "user_id",
+		},
+		Includes: map[string]Include{
+			"user": {Association: "User", Requires: []string{"user_id"}},
+		},
+	}
+)
+
+// testRecord is a minimal model used to compile queries.
+type testRecord struct {
+	ID     uint32 `gorm:"column:id"`
+	Title  string `gorm:"column:title"`
+	UserID uint32 `gorm:"column:user_id"`
+}
+
+// TableName ...
+func (testRecord) TableName() string {
+	return "records"
+}
+
+// dryRun instantiates a gorm connection that compiles queries without running them.
+func dryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestResource_Parse(t *testing.T) {
+	cases := []struct {
+		values    url.Values
+		assertion func(Options, error)
+	}{
+		// Empty.
+		{
+			values: url.Values{},
+			assertion: func(opts Options, err error) {
+				if assert.NoError(t, err) {
+					assert.Empty(t, opts.Fields)
+					assert.Empty(t, opts.Includes)
+					assert.Nil(t, opts.Keys())
+					assert.Equal(t, DefaultLimit, opts.Page.Limit)
+				}
+			},
+		},
+		// Valid fields and includes.
+		{
+			values: url.Values{"fields": {"id, title,,id"}, "include": {"user"}},
+			assertion: func(opts Options, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, []string{"id", "title"}, opts.Fields)
+					assert.Equal(t, []string{"user"}, opts.Includes)
+					assert.Equal(t, []string{"id", "title", "user"}, opts.Keys())
+				}
+			},
+		},
+		// Unknown field.
+		{
+			values: url.Values{"fields": {"id,password"}},
+			assertion: func(opts Options, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrUnknownField)
+				}
+			},
+		},
+		// Unknown include.
+		{
+			values: url.Values{"include": {"comments"}},
+			assertion: func(opts Options, err error) {
+				if assert.Error(t, err) {
+					assert.ErrorIs(t, err, ErrUnknownInclude)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.assertion(testResource.Parse(c.values))
+	}
+}
+
+func TestResource_Apply(t *testing.T) {
+	db := dryRun(t)
+	cases := []struct {
+		opts      Options
+		assertion func(string)
+	}{
+		// Everything.
+		{
+			opts: Options{},
+			assertion: func(sql string) {
+				assert.Equal(t, `SELECT * FROM "records"`, sql)
+			},
+		},
+		// Projection keeps columns required by includes.
+		{
+			opts: Options{Fields: []string{"title"}, Includes: []string{"user"}},
+			assertion: func(sql string) {
+				assert.Equal(t, `SELECT "title","user_id" FROM "records"`, sql)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var records []testRecord
+		statement := testResource.Apply(db.Session(&gorm.Session{}), c.opts).Find(&records).Statement
+		c.assertion(statement.SQL.String())
+	}
+}
@@ -0,0 +1,46 @@
+package sitemaps
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keeps rendered sitemaps for a while, so that crawlers don't query the database on every request.
+type Cache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a rendered sitemap and the time it expires at.
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// NewCache instantiates a new Cache keeping sitemaps for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Get returns a sitemap cached under key, rendering and caching it if it's missing or expired. Failures aren't cached,
+// and concurrent requests wait for a single render rather than querying the database all at once.
+func (c *Cache) Get(key string, render func() ([]byte, error)) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expires) {
+		return entry.body, nil
+	}
+	body, err := render()
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = cacheEntry{body: body, expires: now.Add(c.ttl)}
+	return body, nil
+}
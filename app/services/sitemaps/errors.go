@@ -0,0 +1,10 @@
+package sitemaps
+
+import (
+	"errors"
+)
+
+var (
+	// ErrPageNotFound is thrown when a sitemap page is requested past the last one.
+	ErrPageNotFound = errors.New("no sitemap page found with provided number")
+)
@@ -0,0 +1,69 @@
+package sitemaps
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	ContentType = "application/xml; charset=utf-8"
+	// MaxURLs is the biggest number of URLs a single sitemap can list, bigger sites are split into an index.
+	MaxURLs = 50000
+	// namespace is the XML namespace of sitemaps and sitemap indexes.
+	namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// URL is a page listed by a sitemap, or a sitemap listed by a sitemap index.
+type URL struct {
+	Loc          string
+	LastModified time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []urlElement `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []urlElement `xml:"sitemap"`
+}
+
+type urlElement struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Sitemap renders a sitemap listing urls, which should be no more than MaxURLs.
+func Sitemap(urls []URL) ([]byte, error) {
+	return marshal(urlSet{Xmlns: namespace, URLs: elements(urls)})
+}
+
+// Index renders a sitemap index listing sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	return marshal(sitemapIndex{Xmlns: namespace, Sitemaps: elements(sitemaps)})
+}
+
+// elements converts URLs to XML elements, unknown modification times are left out.
+func elements(urls []URL) []urlElement {
+	result := make([]urlElement, 0, len(urls))
+	for _, u := range urls {
+		element := urlElement{Loc: u.Loc}
+		if !u.LastModified.IsZero() {
+			element.LastMod = u.LastModified.UTC().Format(time.RFC3339)
+		}
+		result = append(result, element)
+	}
+	return result
+}
+
+// marshal renders an XML document along with its declaration.
+func marshal(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
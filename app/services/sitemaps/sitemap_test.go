@@ -0,0 +1,82 @@
+package sitemaps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSitemap(t *testing.T) {
+	body, err := Sitemap([]URL{
+		{Loc: "https://example.com/posts/by-slug/a&b", LastModified: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Loc: "https://example.com/"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/posts/by-slug/a&amp;b</loc>
+    <lastmod>2026-01-02T03:04:05Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/</loc>
+  </url>
+</urlset>
+`, string(body))
+	}
+}
+
+func TestIndex(t *testing.T) {
+	body, err := Index([]URL{{Loc: "https://example.com/sitemaps/posts-1.xml", LastModified: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://example.com/sitemaps/posts-1.xml</loc>
+    <lastmod>2026-01-02T03:04:05Z</lastmod>
+  </sitemap>
+</sitemapindex>
+`, string(body))
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(time.Hour)
+	cache.now = func() time.Time {
+		return now
+	}
+	renders := 0
+	render := func() ([]byte, error) {
+		renders++
+		return []byte{byte(renders)}, nil
+	}
+
+	body, err := cache.Get("a", render)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, body)
+
+	// Cached.
+	now = now.Add(59 * time.Minute)
+	body, _ = cache.Get("a", render)
+	assert.Equal(t, []byte{1}, body)
+
+	// Other keys are cached on their own.
+	body, _ = cache.Get("b", render)
+	assert.Equal(t, []byte{2}, body)
+
+	// Expired.
+	now = now.Add(time.Minute)
+	body, _ = cache.Get("a", render)
+	assert.Equal(t, []byte{3}, body)
+
+	// Failures aren't cached.
+	_, err = cache.Get("c", func() ([]byte, error) {
+		return nil, errors.New("failed")
+	})
+	assert.Error(t, err)
+	body, _ = cache.Get("c", render)
+	assert.Equal(t, []byte{4}, body)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrNilKeys is thrown when an unexpected nil key store is encountered.
+	ErrNilKeys = errors.New("key store is nil")
+	// ErrInvalidToken is thrown when an access token fails verification.
+	ErrInvalidToken = errors.New("invalid access token")
+	// ErrInvalidRefreshToken is thrown when a refresh token fails verification, has expired, or has been used already.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+)
+
+// Settings configure issued and accepted tokens.
+type Settings struct {
+	// Issuer is the iss claim of issued tokens.
+	Issuer string
+	// Audience is the aud claim of issued tokens, accepted tokens have to be meant for it as well.
+	Audience string
+	// TrustedIssuers are other issuers, like other services, whose access tokens are accepted.
+	TrustedIssuers []string
+	// AccessTTL and RefreshTTL are lifetimes of issued tokens.
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// Leeway makes up for clock skew between issuers.
+	Leeway time.Duration
+}
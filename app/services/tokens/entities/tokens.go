@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// Values of the token_use claim.
+const (
+	UseAccess  = "access"
+	UseRefresh = "refresh"
+)
+
+// RefreshToken represents an unused refresh token, identified by its jti claim.
+type RefreshToken struct {
+	ID        string              `gorm:"column:id; primary_key:yes"`
+	UserID    userentities.UserID `gorm:"column:user_id"`
+	ExpiresAt time.Time           `gorm:"column:expires_at"`
+	CreatedAt time.Time           `gorm:"column:created_at"`
+}
+
+// TableName ...
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// TokenPair is an access token along with the refresh token that replaces it, shaped like an OAuth 2.0 token response.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/jwt"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// TokensService is an interface that is used by outside packages to issue and verify JWTs.
+type TokensService interface {
+	Issue(ctx context.Context, id userentities.UserID) (entities.TokenPair, error)
+	Refresh(ctx context.Context, token string) (entities.TokenPair, error)
+	Revoke(ctx context.Context, token string) error
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+	Cleanup(ctx context.Context) (int64, error)
+}
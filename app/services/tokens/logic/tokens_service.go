@@ -0,0 +1,203 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/jwt"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/interfaces"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"gorm.io/gorm"
+)
+
+// Verify that TokensService satisfies the interfaces.TokensService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.TokensService = (*TokensService)(nil)
+
+// TokensService implements interfaces.TokensService.
+type TokensService struct {
+	writer   *gorm.DB
+	keys     *jwt.KeyStore
+	settings entities.Settings
+}
+
+// NewTokensService instantiates a new TokensService, tokens are signed and verified with keys.
+func NewTokensService(ctx context.Context, writer *gorm.DB, keys *jwt.KeyStore, settings entities.Settings) (*TokensService, error) {
+	if writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	if keys == nil {
+		return nil, entities.ErrNilKeys
+	}
+	return &TokensService{
+		writer:   writer,
+		keys:     keys,
+		settings: settings,
+	}, nil
+}
+
+// Issue issues an access token for a user, along with a refresh token that can be exchanged for a new pair once.
+func (s *TokensService) Issue(ctx context.Context, id userentities.UserID) (entities.TokenPair, error) {
+	return s.issue(s.writer.WithContext(ctx), id)
+}
+
+// Refresh exchanges a refresh token for a new pair. A refresh token that has already been exchanged is likely stolen,
+// so using it again revokes every refresh token of its user. Throws entities.ErrInvalidRefreshToken if token fails
+// verification, has expired or has been used.
+func (s *TokensService) Refresh(ctx context.Context, token string) (entities.TokenPair, error) {
+	claims, id, err := s.parseRefresh(token)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	var (
+		pair   entities.TokenPair
+		reused bool
+	)
+	err = s.writer.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", claims.ID, id).Delete(&entities.RefreshToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused = true
+			return tx.Where("user_id = ?", id).Delete(&entities.RefreshToken{}).Error
+		}
+		pair, err = s.issue(tx, id)
+		return err
+	})
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	if reused {
+		return entities.TokenPair{}, entities.ErrInvalidRefreshToken
+	}
+	return pair, nil
+}
+
+// Revoke revokes a refresh token, throws entities.ErrInvalidRefreshToken if token fails verification or has been
+// used already.
+func (s *TokensService) Revoke(ctx context.Context, token string) error {
+	claims, id, err := s.parseRefresh(token)
+	if err != nil {
+		return err
+	}
+	result := s.writer.WithContext(ctx).Where("id = ? AND user_id = ?", claims.ID, id).Delete(&entities.RefreshToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// Verify verifies an access token, issued either by this API or by a trusted issuer, returning its claims.
+// Throws entities.ErrInvalidToken wrapping the reason verification failed, refresh tokens are never accepted.
+func (s *TokensService) Verify(ctx context.Context, token string) (jwt.Claims, error) {
+	validation := jwt.Validation{
+		Issuers:  append([]string{s.settings.Issuer}, s.settings.TrustedIssuers...),
+		Audience: s.settings.Audience,
+		Leeway:   s.settings.Leeway,
+	}
+	claims, err := jwt.Parse(token, s.keys.Keys(), validation, time.Now())
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%w: %v", entities.ErrInvalidToken, err)
+	}
+	if claims.Use == entities.UseRefresh {
+		return jwt.Claims{}, fmt.Errorf("%w: refresh tokens can't be used for access", entities.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// Cleanup deletes expired refresh tokens, returning the number of tokens deleted.
+func (s *TokensService) Cleanup(ctx context.Context) (int64, error) {
+	result := s.writer.WithContext(ctx).Where("expires_at <= ?", time.Now().UTC()).Delete(&entities.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+// issue signs a new token pair, storing the refresh token with db.
+func (s *TokensService) issue(db *gorm.DB, id userentities.UserID) (entities.TokenPair, error) {
+	key, err := s.keys.SigningKey()
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	now := time.Now().UTC()
+	access, _, err := s.sign(key, id, entities.UseAccess, now, s.settings.AccessTTL)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	refresh, jti, err := s.sign(key, id, entities.UseRefresh, now, s.settings.RefreshTTL)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	err = db.Create(&entities.RefreshToken{
+		ID:        jti,
+		UserID:    id,
+		ExpiresAt: now.Add(s.settings.RefreshTTL),
+		CreatedAt: now,
+	}).Error
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	return entities.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.settings.AccessTTL / time.Second),
+	}, nil
+}
+
+// sign signs a token of a given use for a user, returning the token and its id.
+func (s *TokensService) sign(key jwt.Key, id userentities.UserID, use string, now time.Time, ttl time.Duration) (string, string, error) {
+	jti, err := newID()
+	if err != nil {
+		return "", "", err
+	}
+	claims := jwt.Claims{
+		Issuer:    s.settings.Issuer,
+		Subject:   strconv.FormatUint(uint64(id), 10),
+		ExpiresAt: now.Add(ttl).Unix(),
+		NotBefore: now.Unix(),
+		IssuedAt:  now.Unix(),
+		ID:        jti,
+		Use:       use,
+	}
+	if s.settings.Audience != "" {
+		claims.Audience = jwt.Audience{s.settings.Audience}
+	}
+	token, err := jwt.Sign(claims, key)
+	return token, jti, err
+}
+
+// parseRefresh verifies a refresh token issued by this API, returning its claims and user id.
+func (s *TokensService) parseRefresh(token string) (jwt.Claims, userentities.UserID, error) {
+	validation := jwt.Validation{
+		Issuers:  []string{s.settings.Issuer},
+		Audience: s.settings.Audience,
+		Leeway:   s.settings.Leeway,
+	}
+	claims, err := jwt.Parse(token, s.keys.Keys(), validation, time.Now())
+	if err != nil {
+		return jwt.Claims{}, 0, fmt.Errorf("%w: %v", entities.ErrInvalidRefreshToken, err)
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || claims.Use != entities.UseRefresh || claims.ID == "" {
+		return jwt.Claims{}, 0, entities.ErrInvalidRefreshToken
+	}
+	return claims, userentities.UserID(id), nil
+}
+
+// newID generates a random token id.
+func newID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,232 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/jwt"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/interfaces"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tokensServiceTestInstance interfaces.TokensService
+	usersServiceTestInstance  *users.UsersService
+	testKeys                  = jwt.NewKeyStore(jwt.KeySet{{ID: "test", Algorithm: jwt.HS256, Secret: []byte("test-secret")}}, "test")
+	testSettings              = entities.Settings{
+		Issuer:         "test-issuer",
+		Audience:       "test-audience",
+		TrustedIssuers: []string{"test-service"},
+		AccessTTL:      time.Minute,
+		RefreshTTL:     time.Hour,
+	}
+)
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewTokensService(t *testing.T) {
+	ctx := context.Background()
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewTokensService(ctx, writer, testKeys, testSettings)
+	assert.Nil(t, err)
+	_, err = NewTokensService(ctx, nil, testKeys, testSettings)
+	assert.ErrorIs(t, err, entities.ErrNilDB)
+	_, err = NewTokensService(ctx, writer, nil, testSettings)
+	assert.ErrorIs(t, err, entities.ErrNilKeys)
+}
+
+func TestTokensService_Issue(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	pair, err := tokensServiceTestInstance.Issue(ctx, user.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Bearer", pair.TokenType)
+	assert.Equal(t, int64(60), pair.ExpiresIn)
+	claims, err := tokensServiceTestInstance.Verify(ctx, pair.AccessToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "test-issuer", claims.Issuer)
+		assert.Equal(t, entities.UseAccess, claims.Use)
+		assert.True(t, claims.Audience.Contains("test-audience"))
+	}
+	// Refresh tokens can't be used for access.
+	_, err = tokensServiceTestInstance.Verify(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidToken)
+}
+
+func TestTokensService_Verify(t *testing.T) {
+	ctx := context.Background()
+	key, err := testKeys.SigningKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+	expires := time.Now().Add(time.Minute).Unix()
+
+	cases := []struct {
+		claims    jwt.Claims
+		assertion func(err error)
+	}{
+		// Trusted issuer.
+		{
+			claims: jwt.Claims{Issuer: "test-service", Audience: jwt.Audience{"test-audience"}, ExpiresAt: expires},
+			assertion: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		// Untrusted issuer.
+		{
+			claims: jwt.Claims{Issuer: "other-service", Audience: jwt.Audience{"test-audience"}, ExpiresAt: expires},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidToken)
+				assert.ErrorContains(t, err, jwt.ErrInvalidIssuer.Error())
+			},
+		},
+		// Other audience.
+		{
+			claims: jwt.Claims{Issuer: "test-service", Audience: jwt.Audience{"other-audience"}, ExpiresAt: expires},
+			assertion: func(err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		token, err := jwt.Sign(c.claims, key)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		_, err = tokensServiceTestInstance.Verify(ctx, token)
+		c.assertion(err)
+	}
+}
+
+func TestTokensService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	first, err := tokensServiceTestInstance.Issue(ctx, user.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := tokensServiceTestInstance.Refresh(ctx, first.RefreshToken)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	_, err = tokensServiceTestInstance.Verify(ctx, second.AccessToken)
+	assert.NoError(t, err)
+
+	// Reusing a refresh token revokes its whole family.
+	_, err = tokensServiceTestInstance.Refresh(ctx, first.RefreshToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+	_, err = tokensServiceTestInstance.Refresh(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+
+	// Access tokens aren't refresh tokens.
+	_, err = tokensServiceTestInstance.Refresh(ctx, second.AccessToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+}
+
+func TestTokensService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	pair, err := tokensServiceTestInstance.Issue(ctx, user.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, tokensServiceTestInstance.Revoke(ctx, pair.RefreshToken))
+	assert.ErrorIs(t, tokensServiceTestInstance.Revoke(ctx, pair.RefreshToken), entities.ErrInvalidRefreshToken)
+	_, err = tokensServiceTestInstance.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, entities.ErrInvalidRefreshToken)
+}
+
+func TestTokensService_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	user, err := createUser(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	settings := testSettings
+	settings.RefreshTTL = -time.Second
+	expired, err := NewTokensService(ctx, writer, testKeys, settings)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if _, err = expired.Issue(ctx, user.ID); !assert.NoError(t, err) {
+		return
+	}
+	deleted, err := tokensServiceTestInstance.Cleanup(ctx)
+	if assert.NoError(t, err) {
+		assert.GreaterOrEqual(t, deleted, int64(1))
+	}
+}
+
+// createUser creates a user tokens are issued for.
+func createUser(ctx context.Context) (userentities.User, error) {
+	user := userentities.User{Fullname: "test-fullname"}
+	err := usersServiceTestInstance.CreateUser(ctx, &user)
+	return user, err
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewTokensService(ctx, writer, testKeys, testSettings)
+	if err != nil {
+		return nil, err
+	}
+	usersService, err := users.NewUsersService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	tokensServiceTestInstance = service
+	usersServiceTestInstance = usersService
+	return func(ctx context.Context) {}, nil
+}
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNilDB is thrown when an unexpected nil db connection is encountered.
+	ErrNilDB = errors.New("db connection is nil")
+	// ErrUserNotFound is thrown when a user is fetched, or referenced by a post, for an invalid id.
+	ErrUserNotFound = errors.New("no user found with provided id")
+	// ErrDuplicateUser is thrown when a user with conflicting id is created.
+	ErrDuplicateUser = errors.New("user id already exists")
+	// ErrInvalidFullname is thrown when fullname is empty, padded with spaces or longer than 255 characters.
+	ErrInvalidFullname = errors.New("fullname has to be a non-empty string of 255 characters or less")
+	// ErrInvalidEmail is thrown when email is not a valid lower-cased address.
+	ErrInvalidEmail = errors.New("email has to be a valid address")
+	// ErrDuplicateEmail is thrown when a user is created or updated with an email taken by another user.
+	ErrDuplicateEmail = errors.New("email already belongs to another user")
+	// ErrUserHasPosts is thrown when a user who still authors posts or revisions is deleted.
+	ErrUserHasPosts = errors.New("user still authors posts")
+)
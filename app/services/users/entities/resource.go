@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+)
+
+var (
+	// UserResource whitelists fields clients can request for users. Emails can be requested, but are only served to
+	// users allowed to see them, and can't be filtered on, so that they can't be guessed either.
+	UserResource = query.Resource{
+		Fields: map[string]string{
+			"id":         "id",
+			"fullname":   "fullname",
+			"email":      "email",
+			"role":       "role",
+			"updated_at": "updated_at",
+			"created_at": "created_at",
+		},
+		Filterable: map[string]query.Filter{
+			"id":         {Type: query.TypeInteger, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"fullname":   {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorContains}},
+			"role":       {Type: query.TypeString, Operators: []query.Operator{query.OperatorEq, query.OperatorIn}},
+			"created_at": {Type: query.TypeTime, Operators: []query.Operator{query.OperatorEq, query.OperatorGt, query.OperatorGte, query.OperatorLt, query.OperatorLte}},
+		},
+		Sortable:    []string{"id", "fullname", "created_at", "updated_at"},
+		DefaultSort: []query.Sort{{Field: "created_at", Desc: true}},
+	}
+)
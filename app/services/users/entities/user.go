@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+)
+
+// User represents an author of newsletter posts.
+type User struct {
+	ID        UserID      `json:"id" gorm:"column:id; primary_key:yes"`
+	Fullname  string      `json:"fullname" gorm:"column:fullname"`
+	Email     string      `json:"email,omitempty" gorm:"column:email"`
+	Role      policy.Role `json:"role,omitempty" gorm:"column:role"`
+	UpdatedAt time.Time   `json:"updated_at" gorm:"column:updated_at"`
+	CreatedAt time.Time   `json:"created_at" gorm:"column:created_at"`
+}
+
+// JSONAPIType ...
+func (User) JSONAPIType() string {
+	return "users"
+}
+
+// JSONAPIID ...
+func (u User) JSONAPIID() string {
+	return strconv.FormatUint(uint64(u.ID), 10)
+}
+
+// TableName ...
+func (User) TableName() string {
+	return "users"
+}
+
+// OwnerID implements policy.Owned, users own themselves.
+func (u User) OwnerID() uint64 {
+	return uint64(u.ID)
+}
+
+// Validate checks whether a given User object is valid.
+func (u User) Validate() error {
+	if len(u.Fullname) == 0 || len(u.Fullname) > 255 || strings.TrimSpace(u.Fullname) != u.Fullname {
+		return ErrInvalidFullname
+	}
+	if u.Email != "" {
+		if email, err := NormalizeEmail(u.Email); err != nil || email != u.Email {
+			return ErrInvalidEmail
+		}
+	}
+	if u.Role != "" {
+		return u.Role.Validate()
+	}
+	return nil
+}
+
+// NormalizeEmail validates an email address and reduces it to a bare lower-cased address, so that users can log in
+// with any spelling of it.
+func NormalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || len(address.Address) > 255 {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(address.Address), nil
+}
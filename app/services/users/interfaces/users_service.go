@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+)
+
+// UsersService is an interface that is used by outside packages to interact with users.
+type UsersService interface {
+	IndexUsers(ctx context.Context, opts query.Options) ([]entities.User, query.PageInfo, error)
+	FindUser(ctx context.Context, id entities.UserID) (entities.User, error)
+	UpdateUser(ctx context.Context, user *entities.User) error
+	CreateUser(ctx context.Context, user *entities.User) error
+	SetRole(ctx context.Context, id entities.UserID, role policy.Role) error
+	DeleteUser(ctx context.Context, id entities.UserID) error
+}
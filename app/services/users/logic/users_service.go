@@ -0,0 +1,150 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// Verify that UsersService satisfies the interfaces.UsersService interface.
+// This should throw a compilation error otherwise.
+var _ interfaces.UsersService = (*UsersService)(nil)
+
+// UsersService implements interfaces.UsersService.
+type UsersService struct {
+	reader *gorm.DB
+	writer *gorm.DB
+}
+
+// NewUsersService instantiates a new UsersService.
+func NewUsersService(ctx context.Context, reader *gorm.DB, writer *gorm.DB) (*UsersService, error) {
+	if reader == nil || writer == nil {
+		return nil, entities.ErrNilDB
+	}
+	return &UsersService{
+		reader: reader,
+		writer: writer,
+	}, nil
+}
+
+// IndexUsers returns a page of users, throws entities.ErrUserNotFound if table is empty.
+func (s *UsersService) IndexUsers(ctx context.Context, opts query.Options) ([]entities.User, query.PageInfo, error) {
+	users, page, err := query.Paginate[entities.User](s.reader, entities.UserResource, opts)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, query.PageInfo{}, entities.ErrUserNotFound
+		}
+		return nil, query.PageInfo{}, err
+	}
+	return users, page, nil
+}
+
+// FindUser fetches a user by provided id, throws entities.ErrUserNotFound if id is invalid.
+func (s *UsersService) FindUser(ctx context.Context, id entities.UserID) (entities.User, error) {
+	var user entities.User
+	err := s.reader.First(&user, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.User{}, entities.ErrUserNotFound
+		}
+		return entities.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateUser updates a user in persistent repository, throws entities.ErrUserNotFound if id is invalid.
+func (s *UsersService) UpdateUser(ctx context.Context, user *entities.User) error {
+	if err := normalize(user); err != nil {
+		return err
+	}
+	var stored entities.User
+	err := s.writer.First(&stored, "id = ?", user.ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrUserNotFound
+		}
+		return err
+	}
+	user.CreatedAt = stored.CreatedAt
+	user.Role = stored.Role
+	user.UpdatedAt = time.Now().UTC()
+	return duplicate(s.writer.Save(user).Error)
+}
+
+// CreateUser creates a user in persistent repository, throws entities.ErrDuplicateUser if id is taken and
+// entities.ErrDuplicateEmail if email is. Users are authors unless created with another role.
+func (s *UsersService) CreateUser(ctx context.Context, user *entities.User) error {
+	if user.Role == "" {
+		user.Role = policy.RoleAuthor
+	}
+	if err := normalize(user); err != nil {
+		return err
+	}
+	return duplicate(s.writer.Create(user).Error)
+}
+
+// SetRole changes a role of a user, throws policy.ErrInvalidRole if role is unknown and entities.ErrUserNotFound if
+// id is invalid.
+func (s *UsersService) SetRole(ctx context.Context, id entities.UserID, role policy.Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	result := s.writer.Model(&entities.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"role":       role,
+		"updated_at": time.Now().UTC(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser deletes a user from persistent repository, throws entities.ErrUserNotFound if id is invalid and
+// entities.ErrUserHasPosts if posts or revisions still reference the user.
+func (s *UsersService) DeleteUser(ctx context.Context, id entities.UserID) error {
+	user, err := s.FindUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.writer.Delete(&user).Error
+	// Check for foreign key error, didn't find a check in gorm :(
+	if err != nil && strings.Contains(err.Error(), "SQLSTATE 23503") {
+		return entities.ErrUserHasPosts
+	}
+	return err
+}
+
+// normalize lower-cases user email and validates the user.
+func normalize(user *entities.User) error {
+	if user.Email != "" {
+		email, err := entities.NormalizeEmail(user.Email)
+		if err != nil {
+			return err
+		}
+		user.Email = email
+	}
+	return user.Validate()
+}
+
+// duplicate maps unique constraint violations to entities.ErrDuplicateEmail or entities.ErrDuplicateUser.
+func duplicate(err error) error {
+	// Check for duplicate key error, didn't find a check in gorm :(
+	if err == nil || !strings.Contains(err.Error(), "SQLSTATE 23505") {
+		return err
+	}
+	if strings.Contains(err.Error(), "users_email_idx") {
+		return entities.ErrDuplicateEmail
+	}
+	return entities.ErrDuplicateUser
+}
@@ -0,0 +1,286 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	eposts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/query"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/interfaces"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	usersServiceTestInstance interfaces.UsersService
+	postsServiceTestInstance *posts.PostsService
+)
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	teardown, err := setup(ctx)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to setup unit tests")
+		os.Exit(1)
+	}
+	exitValue := m.Run()
+	teardown(ctx)
+	os.Exit(exitValue)
+}
+
+func TestNewUsersService(t *testing.T) {
+	ctx := context.Background()
+	reader, err := database.GetReader(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer, err := database.GetWriter(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = NewUsersService(ctx, reader, writer)
+	assert.Nil(t, err)
+	_, err = NewUsersService(ctx, nil, writer)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, entities.ErrNilDB)
+	}
+}
+
+func TestUsersService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		base      func() (entities.User, error)
+		assertion func(user entities.User, err error)
+	}{
+		// Valid.
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: strings.Repeat("x", 255)}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				if assert.NoError(t, err) {
+					assert.NotZero(t, user.ID)
+					assert.NotZero(t, user.CreatedAt)
+				}
+			},
+		},
+		// Invalid fullname (empty).
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: ""}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidFullname)
+			},
+		},
+		// Invalid fullname (too long).
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: strings.Repeat("x", 256)}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidFullname)
+			},
+		},
+		// Invalid fullname (padded).
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: " test-fullname"}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidFullname)
+			},
+		},
+		// Email gets normalized.
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: "test-fullname", Email: " Test-Fullname <Test@Example.com>"}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, "test@example.com", user.Email)
+				}
+			},
+		},
+		// Invalid email.
+		{
+			base: func() (entities.User, error) {
+				user := entities.User{Fullname: "test-fullname", Email: "test-email"}
+				err := usersServiceTestInstance.CreateUser(ctx, &user)
+				return user, err
+			},
+			assertion: func(user entities.User, err error) {
+				assert.ErrorIs(t, err, entities.ErrInvalidEmail)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		user, err := c.base()
+		c.assertion(user, err)
+		if err == nil {
+			assert.NoError(t, usersServiceTestInstance.DeleteUser(ctx, user.ID))
+		}
+	}
+}
+
+func TestUsersService_CreateUser_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	duplicate := entities.User{ID: user.ID, Fullname: "test-fullname"}
+	assert.ErrorIs(t, usersServiceTestInstance.CreateUser(ctx, &duplicate), entities.ErrDuplicateUser)
+}
+
+func TestUsersService_CreateUser_DuplicateEmail(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname", Email: "test-duplicate@example.com"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	duplicate := entities.User{Fullname: "test-fullname", Email: "TEST-duplicate@example.com"}
+	assert.ErrorIs(t, usersServiceTestInstance.CreateUser(ctx, &duplicate), entities.ErrDuplicateEmail)
+}
+
+func TestUsersService_FindUser(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	found, err := usersServiceTestInstance.FindUser(ctx, user.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, user.ID, found.ID)
+		assert.Equal(t, "test-fullname", found.Fullname)
+	}
+	_, err = usersServiceTestInstance.FindUser(ctx, 0)
+	assert.ErrorIs(t, err, entities.ErrUserNotFound)
+}
+
+func TestUsersService_IndexUsers(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-index-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	opts, err := entities.UserResource.Parse(map[string][]string{"filter[fullname]": {"test-index-fullname"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	users, _, err := usersServiceTestInstance.IndexUsers(ctx, opts)
+	if assert.NoError(t, err) && assert.Len(t, users, 1) {
+		assert.Equal(t, user.ID, users[0].ID)
+	}
+	_, err = entities.UserResource.Parse(map[string][]string{"sort": {"secret"}})
+	assert.ErrorIs(t, err, query.ErrUnknownSort)
+}
+
+func TestUsersService_UpdateUser(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+
+	updated := entities.User{ID: user.ID, Fullname: "test-fullname-new"}
+	if assert.NoError(t, usersServiceTestInstance.UpdateUser(ctx, &updated)) {
+		assert.Equal(t, user.CreatedAt.Unix(), updated.CreatedAt.Unix())
+		found, err := usersServiceTestInstance.FindUser(ctx, user.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "test-fullname-new", found.Fullname)
+		}
+	}
+	assert.ErrorIs(t, usersServiceTestInstance.UpdateUser(ctx, &entities.User{Fullname: "test-fullname"}), entities.ErrUserNotFound)
+	assert.ErrorIs(t, usersServiceTestInstance.UpdateUser(ctx, &entities.User{ID: user.ID}), entities.ErrInvalidFullname)
+}
+
+func TestUsersService_SetRole(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	defer usersServiceTestInstance.DeleteUser(ctx, user.ID)
+	assert.Equal(t, policy.RoleAuthor, user.Role)
+
+	if assert.NoError(t, usersServiceTestInstance.SetRole(ctx, user.ID, policy.RoleEditor)) {
+		found, err := usersServiceTestInstance.FindUser(ctx, user.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, policy.RoleEditor, found.Role)
+		}
+	}
+	// Updates keep the role.
+	updated := entities.User{ID: user.ID, Fullname: "test-fullname", Role: policy.RoleAdmin}
+	if assert.NoError(t, usersServiceTestInstance.UpdateUser(ctx, &updated)) {
+		assert.Equal(t, policy.RoleEditor, updated.Role)
+	}
+	assert.ErrorIs(t, usersServiceTestInstance.SetRole(ctx, user.ID, "owner"), policy.ErrInvalidRole)
+	assert.ErrorIs(t, usersServiceTestInstance.SetRole(ctx, 0, policy.RoleReader), entities.ErrUserNotFound)
+}
+
+func TestUsersService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	user := entities.User{Fullname: "test-fullname"}
+	if !assert.NoError(t, usersServiceTestInstance.CreateUser(ctx, &user)) {
+		return
+	}
+	post := eposts.Post{UserID: user.ID, Title: "test-title", Content: "test-content"}
+	if !assert.NoError(t, postsServiceTestInstance.CreatePost(ctx, &post, user.ID)) {
+		return
+	}
+
+	// Authors of posts can't be deleted.
+	assert.ErrorIs(t, usersServiceTestInstance.DeleteUser(ctx, user.ID), entities.ErrUserHasPosts)
+	assert.NoError(t, postsServiceTestInstance.DeletePost(ctx, post.ID, eposts.AnyVersion))
+	assert.NoError(t, usersServiceTestInstance.DeleteUser(ctx, user.ID))
+	assert.ErrorIs(t, usersServiceTestInstance.DeleteUser(ctx, user.ID), entities.ErrUserNotFound)
+}
+
+func setup(ctx context.Context) (func(context.Context), error) {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service, err := NewUsersService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	postsService, err := posts.NewPostsService(ctx, reader, writer)
+	if err != nil {
+		return nil, err
+	}
+	usersServiceTestInstance = service
+	postsServiceTestInstance = postsService
+	return func(ctx context.Context) {}, nil
+}
@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_keys (
+    key                 VARCHAR(255)    PRIMARY KEY
+    , fingerprint       CHAR(64)        NOT NULL
+    , response_status   INTEGER         NOT NULL DEFAULT 0
+    , response_header   TEXT            NOT NULL DEFAULT ''
+    , response_body     BYTEA
+    , created_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , expires_at        TIMESTAMP       NOT NULL
+);
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_scheduled_at_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS status, DROP COLUMN IF EXISTS published_at, DROP COLUMN IF EXISTS scheduled_at;
\ No newline at end of file
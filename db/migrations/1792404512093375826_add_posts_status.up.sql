@@ -0,0 +1,8 @@
+ALTER TABLE posts
+    ADD COLUMN status           VARCHAR(16)     NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived'))
+    , ADD COLUMN published_at   TIMESTAMP
+    , ADD COLUMN scheduled_at   TIMESTAMP;
+-- Posts created before statuses existed were public.
+UPDATE posts SET status = 'published', published_at = created_at;
+CREATE INDEX posts_scheduled_at_idx ON posts (scheduled_at) WHERE status = 'scheduled';
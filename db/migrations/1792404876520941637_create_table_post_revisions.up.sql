@@ -0,0 +1,11 @@
+CREATE TABLE post_revisions (
+    id              SERIAL          PRIMARY KEY
+    , post_id       INTEGER         NOT NULL REFERENCES posts(id) ON DELETE CASCADE
+    , version       INTEGER         NOT NULL
+    , author_id     INTEGER         REFERENCES users(id)
+    , user_id       INTEGER
+    , title         VARCHAR(255)    NOT NULL
+    , content       TEXT            NOT NULL
+    , created_at    TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , UNIQUE (post_id, version)
+);
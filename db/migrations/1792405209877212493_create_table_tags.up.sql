@@ -0,0 +1,12 @@
+CREATE TABLE tags (
+    id              SERIAL          PRIMARY KEY
+    , name          VARCHAR(64)     NOT NULL UNIQUE
+    , updated_at    TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , created_at    TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE post_tags (
+    post_id         INTEGER         NOT NULL REFERENCES posts(id) ON DELETE CASCADE
+    , tag_id        INTEGER         NOT NULL REFERENCES tags(id) ON DELETE CASCADE
+    , PRIMARY KEY (post_id, tag_id)
+);
+CREATE INDEX post_tags_tag_id_idx ON post_tags (tag_id);
@@ -0,0 +1,12 @@
+ALTER TABLE posts ADD COLUMN slug VARCHAR(255);
+-- Slugs of existing posts end with their id, so they don't collide.
+UPDATE posts SET slug = COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(title), '[^[:alnum:]]+', '-', 'g')), ''), 'post') || '-' || id;
+ALTER TABLE posts
+    ALTER COLUMN slug SET NOT NULL
+    , ADD CONSTRAINT posts_slug_key UNIQUE (slug);
+CREATE TABLE post_slugs (
+    slug            VARCHAR(255)    PRIMARY KEY
+    , post_id       INTEGER         NOT NULL REFERENCES posts(id) ON DELETE CASCADE
+    , created_at    TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX post_slugs_post_id_idx ON post_slugs (post_id);
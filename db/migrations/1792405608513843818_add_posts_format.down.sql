@@ -0,0 +1 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS format, DROP COLUMN IF EXISTS content_html, DROP COLUMN IF EXISTS excerpt, DROP COLUMN IF EXISTS reading_time;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE posts
+    ADD COLUMN format           VARCHAR(16)     NOT NULL DEFAULT 'plain'
+        CHECK (format IN ('markdown', 'plain', 'html'))
+    , ADD COLUMN content_html   TEXT            NOT NULL DEFAULT ''
+    , ADD COLUMN excerpt        TEXT            NOT NULL DEFAULT ''
+    , ADD COLUMN reading_time   INTEGER         NOT NULL DEFAULT 0;
+-- Existing posts are plain text, render them close to what markup.Render produces, they are re-rendered on write.
+WITH escaped AS (
+    SELECT id, TRIM(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(content, E'\r\n', E'\n'), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')) AS content
+    FROM posts
+)
+UPDATE posts SET
+    content_html = CASE WHEN escaped.content = '' THEN '' ELSE
+        '<p>' || REGEXP_REPLACE(REGEXP_REPLACE(escaped.content, E'\n[ \t]*\n\\s*', E'</p>\n<p>', 'g'), E'\n(?!<p>)', E'<br>\n', 'g') || E'</p>\n' END
+    , excerpt = LEFT(REGEXP_REPLACE(TRIM(posts.content), '\s+', ' ', 'g'), 280)
+    , reading_time = CEIL(COALESCE(ARRAY_LENGTH(REGEXP_SPLIT_TO_ARRAY(NULLIF(TRIM(posts.content), ''), '\s+'), 1), 0) / 200.0)
+FROM escaped
+WHERE escaped.id = posts.id;
@@ -0,0 +1,14 @@
+CREATE TABLE subscribers (
+    id                      SERIAL          PRIMARY KEY
+    , email                 VARCHAR(255)    NOT NULL UNIQUE
+    , status                VARCHAR(16)     NOT NULL DEFAULT 'pending'
+    , confirmation_token    CHAR(64)        NOT NULL DEFAULT ''
+    , unsubscribe_token     CHAR(64)        NOT NULL UNIQUE
+    , bounces               INTEGER         NOT NULL DEFAULT 0
+    , confirmed_at          TIMESTAMP
+    , unsubscribed_at       TIMESTAMP
+    , updated_at            TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , created_at            TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX subscribers_confirmation_token_idx ON subscribers (confirmation_token) WHERE confirmation_token <> '';
+CREATE INDEX subscribers_status_idx ON subscribers (status);
@@ -0,0 +1,15 @@
+CREATE TABLE deliveries (
+    id                  SERIAL          PRIMARY KEY
+    , post_id           INTEGER         NOT NULL REFERENCES posts(id) ON DELETE CASCADE
+    , subscriber_id     INTEGER         NOT NULL REFERENCES subscribers(id) ON DELETE CASCADE
+    , status            VARCHAR(16)     NOT NULL DEFAULT 'pending'
+    , attempts          INTEGER         NOT NULL DEFAULT 0
+    , last_error        TEXT            NOT NULL DEFAULT ''
+    , next_attempt_at   TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , sent_at           TIMESTAMP
+    , updated_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , created_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+    , UNIQUE (post_id, subscriber_id)
+);
+CREATE INDEX deliveries_pending_idx ON deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX deliveries_subscriber_id_idx ON deliveries (subscriber_id);
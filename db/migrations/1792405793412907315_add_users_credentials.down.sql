@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email, DROP COLUMN IF EXISTS password_hash, DROP COLUMN IF EXISTS failed_logins, DROP COLUMN IF EXISTS locked_until;
\ No newline at end of file
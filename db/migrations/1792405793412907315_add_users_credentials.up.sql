@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN email            VARCHAR(255)    NOT NULL DEFAULT ''
+    , ADD COLUMN password_hash  TEXT            NOT NULL DEFAULT ''
+    , ADD COLUMN failed_logins  INTEGER         NOT NULL DEFAULT 0
+    , ADD COLUMN locked_until   TIMESTAMP;
+CREATE UNIQUE INDEX users_email_idx ON users (email) WHERE email <> '';
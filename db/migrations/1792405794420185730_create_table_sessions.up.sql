@@ -0,0 +1,11 @@
+CREATE TABLE sessions (
+    id                  CHAR(64)        PRIMARY KEY
+    , user_id           INTEGER         NOT NULL REFERENCES users(id) ON DELETE CASCADE
+    , user_agent        VARCHAR(512)    NOT NULL DEFAULT ''
+    , ip                VARCHAR(64)     NOT NULL DEFAULT ''
+    , last_seen_at      TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP
+    , expires_at        TIMESTAMP       NOT NULL
+    , created_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
@@ -0,0 +1,7 @@
+CREATE TABLE refresh_tokens (
+    id                  VARCHAR(64)     PRIMARY KEY
+    , user_id           INTEGER         NOT NULL REFERENCES users(id) ON DELETE CASCADE
+    , expires_at        TIMESTAMP       NOT NULL
+    , created_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
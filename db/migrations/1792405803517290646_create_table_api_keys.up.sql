@@ -0,0 +1,13 @@
+CREATE TABLE api_keys (
+    id                  SERIAL          PRIMARY KEY
+    , user_id           INTEGER         NOT NULL REFERENCES users(id) ON DELETE CASCADE
+    , name              VARCHAR(128)    NOT NULL
+    , prefix            VARCHAR(16)     NOT NULL
+    , hash              CHAR(64)        NOT NULL
+    , scopes            VARCHAR(512)    NOT NULL DEFAULT ''
+    , expires_at        TIMESTAMP
+    , last_used_at      TIMESTAMP
+    , created_at        TIMESTAMP       DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX api_keys_prefix_idx ON api_keys (prefix);
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN role             VARCHAR(16)     NOT NULL DEFAULT 'author';
+CREATE INDEX users_role_idx ON users (role);
@@ -0,0 +1,2 @@
+DELETE FROM idempotency_keys WHERE owner <> '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey, ADD PRIMARY KEY (key), DROP COLUMN IF EXISTS owner;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Keys are scoped to whoever sent them, anonymous requests share the empty owner.
+ALTER TABLE idempotency_keys
+    ADD COLUMN owner            VARCHAR(64)     NOT NULL DEFAULT ''
+    , DROP CONSTRAINT idempotency_keys_pkey
+    , ADD PRIMARY KEY (owner, key);
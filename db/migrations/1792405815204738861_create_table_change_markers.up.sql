@@ -0,0 +1,5 @@
+-- Markers of changes that leave no row behind, such as published posts being deleted or unpublished.
+CREATE TABLE change_markers (
+    name                VARCHAR(64)     PRIMARY KEY
+    , changed_at        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_revision_tags;
+ALTER TABLE post_revisions DROP COLUMN IF EXISTS format;
\ No newline at end of file
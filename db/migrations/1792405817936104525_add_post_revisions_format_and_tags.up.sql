@@ -0,0 +1,13 @@
+ALTER TABLE post_revisions
+    ADD COLUMN format           VARCHAR(16)     NOT NULL DEFAULT 'plain'
+        CHECK (format IN ('markdown', 'plain', 'html'));
+CREATE TABLE post_revision_tags (
+    revision_id     INTEGER         NOT NULL REFERENCES post_revisions(id) ON DELETE CASCADE
+    , tag_id        INTEGER         NOT NULL REFERENCES tags(id) ON DELETE CASCADE
+    , PRIMARY KEY (revision_id, tag_id)
+);
+CREATE INDEX post_revision_tags_tag_id_idx ON post_revision_tags (tag_id);
+-- Existing revisions didn't record format and tags, they are attributed the current ones of their posts.
+UPDATE post_revisions SET format = posts.format FROM posts WHERE posts.id = post_revisions.post_id;
+INSERT INTO post_revision_tags (revision_id, tag_id)
+SELECT post_revisions.id, post_tags.tag_id FROM post_revisions JOIN post_tags ON post_tags.post_id = post_revisions.post_id;
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Exports posts of the environment selected by ENV, so that they can be imported into another one.
+func main() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	logrus.Infof("exporting posts of %v env", cfg["envname"])
+
+	// Parse flags.
+	var dsn, file, format, columns, filter string
+	flag.StringVar(&dsn, "dsn", "", "override data source name, defaulted to config if not provided")
+	flag.StringVar(&file, "file", "", "file to export to, defaulted to stdout")
+	flag.StringVar(&format, "format", "", "one of csv, json or ndjson, defaulted to the file extension or json")
+	flag.StringVar(&columns, "columns", "", "comma separated CSV columns, defaulted to all of them")
+	flag.StringVar(&filter, "filter", "", "query string filtering posts like the API does, e.g. filter[status]=published")
+	flag.Parse()
+	if dsn == "" {
+		dsn = cfg["database.reader"]
+	}
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	if format == "" {
+		format = string(entities.TransferJSON)
+	}
+	values, err := url.ParseQuery(filter)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to parse filter")
+	}
+	opts, err := entities.PostResource.Parse(values)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to parse filter")
+	}
+
+	// Connect to the database.
+	// Silenced, it logs to stdout, which may be the export file.
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to connect to the database")
+	}
+	service, err := posts.NewPostsService(ctx, db, db)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to instantiate posts service")
+	}
+
+	// Export.
+	var out io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			logrus.WithError(err).Fatalf("failed to create %v", file)
+		}
+		defer f.Close()
+		out = f
+	}
+	var columnList []string
+	if columns != "" {
+		columnList = strings.Split(columns, ",")
+	}
+	count, err := service.ExportPosts(ctx, out, entities.TransferFormat(format), columnList, entities.AnyViewer, opts)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed after exporting %v posts", count)
+	}
+	logrus.Infof("exported %v posts", count)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	posts "github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/logic"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Imports posts into the environment selected by ENV, typically ones exported from another environment.
+func main() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	logrus.Infof("importing posts into %v env", cfg["envname"])
+
+	// Parse flags.
+	var dsn, file, format, mode, mapping string
+	var dryRun bool
+	var author uint
+	flag.StringVar(&dsn, "dsn", "", "override data source name, defaulted to config if not provided")
+	flag.StringVar(&file, "file", "", "file to import from, defaulted to stdin")
+	flag.StringVar(&format, "format", "", "one of csv, json or ndjson, defaulted to the file extension or json")
+	flag.StringVar(&mode, "mode", string(entities.ImportByID), "match stored posts by id or slug")
+	flag.StringVar(&mapping, "map", "", "comma separated header:column pairs mapping CSV headers to columns")
+	flag.BoolVar(&dryRun, "dry-run", false, "validate posts without writing them")
+	flag.UintVar(&author, "author", 0, "id of the user revisions are recorded for")
+	flag.Parse()
+	if dsn == "" {
+		dsn = cfg["database.writer"]
+	}
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	if format == "" {
+		format = string(entities.TransferJSON)
+	}
+	columns := make(map[string]string)
+	if mapping != "" {
+		for _, pair := range strings.Split(mapping, ",") {
+			header, column, ok := strings.Cut(pair, ":")
+			if !ok {
+				logrus.Fatalf("map has to be a list of header:column pairs, got %v", pair)
+			}
+			columns[header] = column
+		}
+	}
+
+	// Connect to the database.
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to connect to the database")
+	}
+	service, err := posts.NewPostsService(ctx, db, db)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to instantiate posts service")
+	}
+
+	// Import.
+	var in io.Reader = os.Stdin
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			logrus.WithError(err).Fatalf("failed to open %v", file)
+		}
+		defer f.Close()
+		in = f
+	}
+	logged := 0
+	report, err := service.ImportPosts(ctx, in, entities.ImportOptions{
+		Format:  entities.TransferFormat(format),
+		Mode:    entities.ImportMode(mode),
+		Columns: columns,
+		DryRun:  dryRun,
+		Progress: func(report entities.ImportReport) {
+			for _, e := range report.Errors[logged:] {
+				logrus.Warnf("record %v: %v", e.Record, e.Error)
+			}
+			logged = len(report.Errors)
+			logrus.Infof("read %v records, %v created, %v updated, %v failed", report.Records, report.Created, report.Updated, report.Failed)
+		},
+	}, userentities.UserID(author))
+	if err != nil {
+		logrus.WithError(err).Fatalf("import stopped after %v records", report.Records)
+	}
+	if dryRun {
+		logrus.Infof("dry run, nothing was written")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/config"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Changes a role of a user of the environment selected by ENV, which is how the first admin is appointed.
+func main() {
+	ctx := context.Background()
+	cfg := config.MustConfig()
+	logrus.Infof("changing a role of a user of %v env", cfg["envname"])
+
+	// Parse flags.
+	var dsn, role string
+	var user uint
+	flag.StringVar(&dsn, "dsn", "", "override data source name, defaulted to config if not provided")
+	flag.UintVar(&user, "user", 0, "id of the user whose role is changed")
+	flag.StringVar(&role, "role", string(policy.RoleAdmin), "one of admin, editor, author or reader")
+	flag.Parse()
+	if dsn == "" {
+		dsn = cfg["database.writer"]
+	}
+
+	// Connect to the database.
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to connect to the database")
+	}
+	service, err := users.NewUsersService(ctx, db, db)
+	if err != nil {
+		logrus.WithError(err).Fatalf("failed to instantiate users service")
+	}
+
+	// Change the role.
+	if err = service.SetRole(ctx, userentities.UserID(user), policy.Role(role)); err != nil {
+		logrus.WithError(err).Fatalf("failed to make user %v %v", user, role)
+	}
+	logrus.Infof("user %v is %v now", user, role)
+}
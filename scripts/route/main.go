@@ -23,6 +23,7 @@ var (
 type interpolationData struct {
 	Handlers    map[string]map[string][]string
 	Controllers map[string]string
+	Routes      map[string]string
 }
 
 // This will just panic if something goes wrong,
@@ -38,6 +39,7 @@ func main() {
 	data := interpolationData{
 		Handlers:    make(map[string]map[string][]string),
 		Controllers: make(map[string]string),
+		Routes:      make(map[string]string),
 	}
 	for scanner.Scan() {
 		row := strings.Split(strings.ReplaceAll(scanner.Text(), "\t", " "), " ")
@@ -54,7 +56,18 @@ func main() {
 		upC := strings.Split(row[2], ".")[0]
 		lowC := strings.ToLower(upC[:1]) + upC[1:]
 		data.Controllers[upC] = lowC
-		data.Handlers[row[1]][row[0]] = []string{lowC, strings.Split(row[2], ".")[1]}
+		// Optional trailing columns set Cache-Control of the endpoint responses and, prefixed with "scope:", the scope
+		// API keys need to be granted to use the endpoint.
+		cacheControl, scope := "", ""
+		for _, column := range row[3:] {
+			if strings.HasPrefix(column, "scope:") {
+				scope = strings.TrimPrefix(column, "scope:")
+				continue
+			}
+			cacheControl = column
+		}
+		data.Handlers[row[1]][row[0]] = []string{lowC, strings.Split(row[2], ".")[1], cacheControl, scope}
+		data.Routes[row[2]] = row[1]
 	}
 	rawTemplate, err := os.ReadFile(config.BasePath() + "/scripts/route/_template.go.tmp")
 	if err != nil {
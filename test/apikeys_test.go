@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	apikeyentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/apikeys/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeysController_Keys(t *testing.T) {
+	user := userentities.User{Fullname: "test-fullname", Email: "test-keys@example.com"}
+	response, err := apiClient.PostObject("/users", map[string]interface{}{
+		"fullname": user.Fullname,
+		"email":    user.Email,
+		"password": "test-password",
+	})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &user)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+	keys := fmt.Sprintf("/users/%v/keys", user.ID)
+
+	client := apiClient.WithCookies()
+	response, err = client.PostObject("/sessions", map[string]string{"email": user.Email, "password": "test-password"})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) {
+		return
+	}
+
+	// Unknown scope.
+	response, err = client.PostObject(keys, map[string]interface{}{"name": "test-key", "scopes": []string{"posts:delete"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+	response, err = client.PostObject(keys, map[string]interface{}{"name": "test-key", "scopes": []string{"posts:read"}})
+	var created apikeyentities.CreatedKey
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &created)) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	assert.Equal(t, apikeyentities.Scopes{"posts:read"}, created.Scopes)
+
+	// Keys are accepted in either header, within their scopes.
+	response, err = apiClient.GetWithAPIKey("/posts", created.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = apiClient.GetWithToken("/posts", created.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = apiClient.GetWithAPIKey("/users", created.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		assert.Contains(t, response.Header.Get("WWW-Authenticate"), `scope="users:read"`)
+	}
+	// Keys can't manage keys.
+	response, err = apiClient.GetWithAPIKey(keys, created.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+	response, err = apiClient.GetWithAPIKey("/posts", created.Key+"x")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	response, err = client.Get(keys)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		var listed []apikeyentities.APIKey
+		if assert.NoError(t, ParseJSONBody(response.Body, &listed)) && assert.Len(t, listed, 1) {
+			assert.Equal(t, created.Prefix, listed[0].Prefix)
+			assert.NotNil(t, listed[0].LastUsedAt)
+		}
+	}
+
+	response, err = client.Delete(fmt.Sprintf("%v/%v", keys, created.ID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = apiClient.GetWithAPIKey("/posts", created.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+	response, err = client.Delete(fmt.Sprintf("%v/%v", keys, created.ID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+}
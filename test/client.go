@@ -5,21 +5,59 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 )
 
 // APIClient implements some quality of life methods used by integration tests.
 type APIClient struct {
 	basePath string
+	client   *http.Client
 }
 
 // NewAPIClient instantiates APIClient with provided base path.
 func NewAPIClient(path string) *APIClient {
-	return &APIClient{basePath: path}
+	return &APIClient{basePath: path, client: http.DefaultClient}
+}
+
+// WithCookies returns a client with its own cookie jar, so that it stays logged in across requests.
+func (c *APIClient) WithCookies() *APIClient {
+	jar, _ := cookiejar.New(nil)
+	return &APIClient{basePath: c.basePath, client: &http.Client{Jar: jar}}
 }
 
 // Get ...
 func (c *APIClient) Get(route string) (*http.Response, error) {
-	return http.Get(c.basePath + route)
+	return c.client.Get(c.basePath + route)
+}
+
+// GetIfNoneMatch ...
+func (c *APIClient) GetIfNoneMatch(route string, tag string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, c.basePath+route, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("If-None-Match", tag)
+	return c.client.Do(request)
+}
+
+// GetWithToken ...
+func (c *APIClient) GetWithToken(route string, token string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, c.basePath+route, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return c.client.Do(request)
+}
+
+// GetWithAPIKey ...
+func (c *APIClient) GetWithAPIKey(route string, key string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, c.basePath+route, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-API-Key", key)
+	return c.client.Do(request)
 }
 
 // PutBytes  ...
@@ -29,7 +67,7 @@ func (c *APIClient) PutBytes(route string, body []byte) (*http.Response, error)
 		return nil, err
 	}
 	request.Header.Set("Content-Type", http.DetectContentType(body))
-	return http.DefaultClient.Do(request)
+	return c.client.Do(request)
 }
 
 // PutObject  ...
@@ -41,9 +79,41 @@ func (c *APIClient) PutObject(route string, object interface{}) (*http.Response,
 	return c.PutBytes(route, marshalled)
 }
 
+// PatchBytes ...
+func (c *APIClient) PatchBytes(route string, contentType string, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodPatch, c.basePath+route, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	return c.client.Do(request)
+}
+
 // PostBytes ...
 func (c *APIClient) PostBytes(route string, body []byte) (*http.Response, error) {
-	return http.Post(c.basePath+route, http.DetectContentType(body), bytes.NewBuffer(body))
+	return c.client.Post(c.basePath+route, http.DetectContentType(body), bytes.NewBuffer(body))
+}
+
+// PostBytesWithKey ...
+func (c *APIClient) PostBytesWithKey(route string, key string, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodPost, c.basePath+route, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", http.DetectContentType(body))
+	request.Header.Set("Idempotency-Key", key)
+	return c.client.Do(request)
+}
+
+// PostBytesIfMatch ...
+func (c *APIClient) PostBytesIfMatch(route string, tag string, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodPost, c.basePath+route, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", http.DetectContentType(body))
+	request.Header.Set("If-Match", tag)
+	return c.client.Do(request)
 }
 
 // PostObject ...
@@ -61,7 +131,27 @@ func (c *APIClient) Delete(route string) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	return http.DefaultClient.Do(request)
+	return c.client.Do(request)
+}
+
+// DeleteIfMatch ...
+func (c *APIClient) DeleteIfMatch(route string, tag string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodDelete, c.basePath+route, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("If-Match", tag)
+	return c.client.Do(request)
+}
+
+// DeleteBytes ...
+func (c *APIClient) DeleteBytes(route string, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodDelete, c.basePath+route, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", http.DetectContentType(body))
+	return c.client.Do(request)
 }
 
 // ParseJSONBody ...
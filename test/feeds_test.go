@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedsController_Feeds(t *testing.T) {
+	tag := entities.Tag{Name: "test-feed-tag"}
+	response, err := adminClient.PostObject("/tags", tag)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &tag)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/tags/%v", tag.ID))
+
+	post := entities.Post{Title: "test-feed-title", Content: "test-content", Tags: []entities.Tag{{Name: tag.Name}}}
+	response, err = adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+
+	// Drafts are left out.
+	response, err = apiClient.Get("/feeds/tags/test-feed-tag/posts.rss")
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, "application/rss+xml; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.NotContains(t, string(body), "test-feed-title")
+	}
+
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusOK, response.StatusCode) {
+		return
+	}
+	for _, route := range []string{"/feeds/posts.atom", "/feeds/tags/test-feed-tag/posts.atom", "/feeds/tags/test-feed-tag/posts.rss"} {
+		response, err = apiClient.Get(route)
+		if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode, route) {
+			body, _ := io.ReadAll(response.Body)
+			assert.Contains(t, string(body), "test-feed-title", route)
+			assert.NotEmpty(t, response.Header.Get("Last-Modified"), route)
+		}
+	}
+
+	// Conditional GET.
+	response, err = apiClient.Get("/feeds/posts.atom")
+	if assert.NoError(t, err) && assert.NotEmpty(t, response.Header.Get("ETag")) {
+		response, err = apiClient.GetIfNoneMatch("/feeds/posts.atom", response.Header.Get("ETag"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusNotModified, response.StatusCode)
+		}
+	}
+
+	response, err = apiClient.Get("/feeds/tags/test-feed-missing/posts.rss")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+}
@@ -5,15 +5,31 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/database"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/mail/mailtest"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+	users "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/logic"
+
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	adminEmail    = "test-admin@example.com"
+	adminPassword = "test-admin-password"
+)
+
 var (
 	basePath  string
 	apiClient *APIClient
+	// adminClient is logged in as an admin.
+	adminClient *APIClient
+	// mailServer receives emails sent by the API, which is configured to send them to 127.0.0.1:1025 in tests.
+	mailServer *mailtest.Server
 )
 
 func TestMain(m *testing.M) {
@@ -40,5 +56,66 @@ func setup(ctx context.Context) (func(context.Context), error) {
 	}
 	basePath = fmt.Sprintf("http://%v:%v", host, port)
 	apiClient = NewAPIClient(basePath)
-	return func(ctx context.Context) {}, nil
+	client, id, err := login(adminEmail, adminPassword)
+	if err != nil {
+		return func(ctx context.Context) {}, err
+	}
+	// Admins are only appointed out of band, the way scripts/role does.
+	if err = appoint(ctx, id, policy.RoleAdmin); err != nil {
+		return func(ctx context.Context) {}, err
+	}
+	adminClient = client
+	server, err := mailtest.NewServer("127.0.0.1:1025")
+	if err != nil {
+		return func(ctx context.Context) {}, err
+	}
+	mailServer = server
+	return func(ctx context.Context) { server.Close() }, nil
+}
+
+// appoint changes a role of a user directly in the database of the API.
+func appoint(ctx context.Context, id userentities.UserID, role policy.Role) error {
+	reader, err := database.GetReader(ctx)
+	if err != nil {
+		return err
+	}
+	writer, err := database.GetWriter(ctx)
+	if err != nil {
+		return err
+	}
+	service, err := users.NewUsersService(ctx, reader, writer)
+	if err != nil {
+		return err
+	}
+	return service.SetRole(ctx, id, role)
+}
+
+// login signs a user up unless they already exist, and returns a client logged in as them along with their id.
+func login(email string, password string) (*APIClient, userentities.UserID, error) {
+	response, err := apiClient.PostObject("/users", map[string]string{
+		"fullname": email,
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusConflict {
+		return nil, 0, fmt.Errorf("failed to sign %v up: %v", email, response.Status)
+	}
+	client := apiClient.WithCookies()
+	response, err = client.PostObject("/sessions", map[string]string{"email": email, "password": password})
+	if err != nil {
+		return nil, 0, err
+	}
+	if response.StatusCode != http.StatusCreated {
+		return nil, 0, fmt.Errorf("failed to log %v in: %v", email, response.Status)
+	}
+	var session struct {
+		UserID userentities.UserID `json:"user_id"`
+	}
+	if err = ParseJSONBody(response.Body, &session); err != nil {
+		return nil, 0, err
+	}
+	return client, session.UserID, nil
 }
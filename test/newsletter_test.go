@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	enewsletter "github.com/nataliia_hudzeliak/rest-api-framework/app/services/newsletter/entities"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	confirmLink     = regexp.MustCompile(`/subscribers/confirm/([0-9a-f]{64})`)
+	unsubscribeLink = regexp.MustCompile(`/subscribers/unsubscribe/([0-9a-f]{64})`)
+)
+
+func TestNewsletterController_Subscribers(t *testing.T) {
+	mailServer.Reset()
+	email := fmt.Sprintf("test-subscriber-%v@example.com", time.Now().UnixNano())
+
+	response, err := apiClient.PostObject("/subscribers", map[string]string{"email": "not an address"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+	response, err = apiClient.PostObject("/subscribers", map[string]string{"email": email})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusAccepted, response.StatusCode) {
+		return
+	}
+	token, ok := findToken(confirmLink, email)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	response, err = apiClient.Get("/subscribers/confirm/" + strings.Repeat("0", 64))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+	response, err = apiClient.Get("/subscribers/confirm/" + token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	var subscribers []enewsletter.Subscriber
+	response, err = adminClient.Get("/subscribers?filter[email]=" + email)
+	if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &subscribers)) && assert.Len(t, subscribers, 1) {
+		assert.Equal(t, enewsletter.SubscriberActive, subscribers[0].Status)
+	}
+
+	// Only users managing the newsletter report bounces.
+	response, err = apiClient.PostObject("/subscribers/bounces", map[string]string{"email": email})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// A published post is delivered by the background loop.
+	post := entities.Post{Title: "test-newsletter-title", Content: "test-content"}
+	response, err = adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/deliveries", post.ID), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+	}
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusOK, response.StatusCode) {
+		return
+	}
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/deliveries", post.ID), nil)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusAccepted, response.StatusCode) {
+		return
+	}
+	unsubscribeToken, ok := findToken(unsubscribeLink, email)
+	for i := 0; i < 50 && !ok; i++ {
+		time.Sleep(100 * time.Millisecond)
+		unsubscribeToken, ok = findToken(unsubscribeLink, email)
+	}
+	if !assert.True(t, ok) {
+		return
+	}
+	var stats enewsletter.DeliveryStats
+	response, err = adminClient.Get(fmt.Sprintf("/posts/%v/deliveries", post.ID))
+	if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &stats)) {
+		assert.GreaterOrEqual(t, stats.Sent, int64(1))
+	}
+
+	// One-click unsubscribe.
+	response, err = apiClient.PostBytes("/subscribers/unsubscribe/"+unsubscribeToken, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = adminClient.Get("/subscribers?filter[email]=" + email)
+	if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &subscribers)) && assert.Len(t, subscribers, 1) {
+		assert.Equal(t, enewsletter.SubscriberUnsubscribed, subscribers[0].Status)
+	}
+}
+
+// findToken finds a token in a link matched by link in the last email sent to an address.
+func findToken(link *regexp.Regexp, email string) (string, bool) {
+	messages := mailServer.Messages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].To[0] != email {
+			continue
+		}
+		// Quoted-printable bodies wrap long lines with soft line breaks.
+		match := link.FindStringSubmatch(strings.ReplaceAll(messages[i].Data, "=\r\n", ""))
+		if match != nil {
+			return match[1], true
+		}
+	}
+	return "", false
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
 
@@ -25,7 +27,7 @@ func TestPostsController_FindPost(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -36,7 +38,7 @@ func TestPostsController_FindPost(t *testing.T) {
 				return post.ID, nil
 			},
 			base: func(id entities.PostID) (*http.Response, error) {
-				return apiClient.Get(fmt.Sprintf("/posts/%v", id))
+				return adminClient.Get(fmt.Sprintf("/posts/%v", id))
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -48,7 +50,43 @@ func TestPostsController_FindPost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return err
+			},
+		},
+		// Unchanged since last fetch.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				response, err := adminClient.Get(fmt.Sprintf("/posts/%v", id))
+				if err != nil {
+					return nil, err
+				}
+				assert.Equal(t, "private,max-age=60", response.Header.Get("Cache-Control"))
+				assert.NotEmpty(t, response.Header.Get("Last-Modified"))
+				return adminClient.GetIfNoneMatch(fmt.Sprintf("/posts/%v", id), response.Header.Get("ETag"))
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusNotModified, response.StatusCode)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -56,18 +94,18 @@ func TestPostsController_FindPost(t *testing.T) {
 		{
 			setup: func() (entities.PostID, error) {
 				id := entities.PostID(69)
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				if err != nil {
 					return 0, err
 				}
 				return id, nil
 			},
 			base: func(id entities.PostID) (*http.Response, error) {
-				return apiClient.Get(fmt.Sprintf("/posts/%v", id))
+				return adminClient.Get(fmt.Sprintf("/posts/%v", id))
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
-					assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+					assert.Equal(t, response.StatusCode, http.StatusNotFound)
 					var message map[string]string
 					if err := ParseJSONBody(response.Body, &message); assert.NoError(t, err) {
 						if m, ok := message["message"]; assert.True(t, ok) {
@@ -89,6 +127,41 @@ func TestPostsController_FindPost(t *testing.T) {
 	}
 }
 
+func TestPostsController_FindPostBySlug(t *testing.T) {
+	post := entities.Post{
+		Title:   "Test Slug Lookup",
+		Content: "test-content",
+	}
+	response, err := adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	if !assert.Equal(t, "test-slug-lookup", post.Slug) {
+		return
+	}
+
+	response, err = adminClient.Get("/posts/by-slug/test-slug-lookup")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	// Former slugs redirect to the current one.
+	post.Title = "Test Slug Lookup Renamed"
+	_, err = adminClient.PutObject(fmt.Sprintf("/posts/%v", post.ID), post)
+	if !assert.NoError(t, err) {
+		return
+	}
+	response, err = adminClient.Get("/posts/by-slug/test-slug-lookup")
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		assert.Equal(t, "/posts/by-slug/test-slug-lookup-renamed", response.Request.URL.Path)
+		var found entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &found)) {
+			assert.Equal(t, post.ID, found.ID)
+		}
+	}
+}
+
 func TestPostsController_IndexPosts(t *testing.T) {
 	cases := []struct {
 		setup     func() (entities.PostID, error)
@@ -103,7 +176,7 @@ func TestPostsController_IndexPosts(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -111,7 +184,8 @@ func TestPostsController_IndexPosts(t *testing.T) {
 				if err != nil {
 					return 0, err
 				}
-				return post.ID, nil
+				_, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+				return post.ID, err
 			},
 			base: func(id entities.PostID) (*http.Response, error) {
 				return apiClient.Get("/posts")
@@ -128,10 +202,114 @@ func TestPostsController_IndexPosts(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return err
+			},
+		},
+		// Sparse fieldset.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				_, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+				return post.ID, err
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				return apiClient.Get("/posts?fields=id,title")
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusOK)
+					var posts []map[string]any
+					if err := ParseJSONBody(response.Body, &posts); assert.NoError(t, err) {
+						if assert.NotEmpty(t, posts) {
+							assert.Len(t, posts[0], 2)
+							assert.Contains(t, posts[0], "id")
+							assert.Contains(t, posts[0], "title")
+						}
+					}
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
+		// Paginated.
+		{
+			setup: func() (entities.PostID, error) {
+				for i := 0; i < 2; i++ {
+					post := entities.Post{
+						Title:   "test-title",
+						Content: "test-content",
+					}
+					response, err := adminClient.PostObject("/posts", post)
+					if err != nil {
+						return 0, err
+					}
+					err = ParseJSONBody(response.Body, &post)
+					if err != nil {
+						return 0, err
+					}
+					_, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+					if err != nil {
+						return 0, err
+					}
+				}
+				return 0, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				return apiClient.Get("/posts?limit=1&total=true")
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusOK)
+					assert.Contains(t, response.Header.Get("Link"), `rel="next"`)
+					assert.NotEmpty(t, response.Header.Get("X-Total-Count"))
+					var posts []entities.Post
+					if err := ParseJSONBody(response.Body, &posts); assert.NoError(t, err) {
+						assert.Len(t, posts, 1)
+					}
+				}
+			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
+		// Unsupported filter.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func(id entities.PostID) (*http.Response, error) {
+				return apiClient.Get("/posts?filter[content]=x&sort=-created_at,title")
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+				}
+			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
+		// Unknown field.
+		{
+			setup: func() (entities.PostID, error) { return 0, nil },
+			base: func(id entities.PostID) (*http.Response, error) {
+				return apiClient.Get("/posts?fields=id,password")
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+				}
+			},
+			cleanup: func(id entities.PostID) error { return nil },
+		},
 	}
 
 	for _, c := range cases {
@@ -157,7 +335,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -172,7 +350,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 					Title:   "test-title-new",
 					Content: "test-content-new",
 				}
-				return apiClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
+				return adminClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -184,7 +362,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -192,7 +370,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 		{
 			setup: func() (entities.PostID, error) {
 				id := entities.PostID(69)
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				if err != nil {
 					return 0, err
 				}
@@ -203,11 +381,11 @@ func TestPostsController_UpdatePost(t *testing.T) {
 					Title:   "test-title-new",
 					Content: "test-content-new",
 				}
-				return apiClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
+				return adminClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
-					assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+					assert.Equal(t, response.StatusCode, http.StatusNotFound)
 					var message map[string]string
 					if err := ParseJSONBody(response.Body, &message); assert.NoError(t, err) {
 						if m, ok := message["message"]; assert.True(t, ok) {
@@ -225,7 +403,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -240,7 +418,7 @@ func TestPostsController_UpdatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 256),
 					Content: "test-content-new",
 				}
-				return apiClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
+				return adminClient.PutObject(fmt.Sprintf("/posts/%v", id), post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -254,7 +432,128 @@ func TestPostsController_UpdatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return err
+			},
+		},
+	}
+
+	for _, c := range cases {
+		id, err := c.setup()
+		assert.Nil(t, err)
+		c.assertion(c.base(id))
+		err = c.cleanup(id)
+		assert.Nil(t, err)
+	}
+}
+
+func TestPostsController_PatchPost(t *testing.T) {
+	cases := []struct {
+		setup     func() (entities.PostID, error)
+		base      func(entities.PostID) (*http.Response, error)
+		assertion func(*http.Response, error)
+		cleanup   func(entities.PostID) error
+	}{
+		// Merge patch.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				body := `{"title": "test-title-new", "created_at": "2000-01-01T00:00:00Z"}`
+				return adminClient.PatchBytes(fmt.Sprintf("/posts/%v", id), "application/merge-patch+json", []byte(body))
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusOK)
+					var post entities.Post
+					if err := ParseJSONBody(response.Body, &post); assert.NoError(t, err) {
+						assert.Equal(t, "test-title-new", post.Title)
+						assert.Equal(t, "test-content", post.Content)
+						assert.NotEqual(t, 2000, post.CreatedAt.Year())
+					}
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return err
+			},
+		},
+		// JSON patch.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				body := `[{"op": "test", "path": "/title", "value": "test-title"}, {"op": "replace", "path": "/content", "value": "test-content-new"}]`
+				return adminClient.PatchBytes(fmt.Sprintf("/posts/%v", id), "application/json-patch+json", []byte(body))
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusOK)
+					var post entities.Post
+					if err := ParseJSONBody(response.Body, &post); assert.NoError(t, err) {
+						assert.Equal(t, "test-title", post.Title)
+						assert.Equal(t, "test-content-new", post.Content)
+					}
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return err
+			},
+		},
+		// Unsupported media type.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				return adminClient.PatchBytes(fmt.Sprintf("/posts/%v", id), "application/json", []byte(`{"title": "x"}`))
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, response.StatusCode, http.StatusUnsupportedMediaType)
+				}
+			},
+			cleanup: func(id entities.PostID) error {
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -284,7 +583,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 					Title:   "test-title-new",
 					Content: "test-content-new",
 				}
-				return apiClient.PostObject("/posts", post)
+				return adminClient.PostObject("/posts", post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -296,7 +595,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -307,7 +606,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -323,7 +622,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 					Title:   "test-title-new",
 					Content: "test-content-new",
 				}
-				return apiClient.PostObject("/posts", post)
+				return adminClient.PostObject("/posts", post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -337,7 +636,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -349,7 +648,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 					Title:   strings.Repeat("x", 256),
 					Content: "test-content-new",
 				}
-				return apiClient.PostObject("/posts", post)
+				return adminClient.PostObject("/posts", post)
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -363,7 +662,7 @@ func TestPostsController_CreatePost(t *testing.T) {
 				}
 			},
 			cleanup: func(id entities.PostID) error {
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				return err
 			},
 		},
@@ -385,6 +684,229 @@ func TestPostsController_CreatePost(t *testing.T) {
 	}
 }
 
+func TestPostsController_PostLifecycle(t *testing.T) {
+	post := entities.Post{
+		Title:   "test-title",
+		Content: "test-content",
+	}
+	response, err := adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	assert.Equal(t, entities.StatusDraft, post.Status)
+
+	// Drafts are hidden from lists.
+	response, err = apiClient.Get(fmt.Sprintf("/posts?filter[id]=%v", post.ID))
+	if assert.NoError(t, err) {
+		var posts []entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &posts)) {
+			assert.Empty(t, posts)
+		}
+	}
+
+	// Scheduled.
+	at := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), []byte(fmt.Sprintf(`{"at": %q}`, at)))
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		var scheduled entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &scheduled)) {
+			assert.Equal(t, entities.StatusScheduled, scheduled.Status)
+		}
+	}
+
+	// Published.
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		var published entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &published)) {
+			assert.Equal(t, entities.StatusPublished, published.Status)
+			assert.NotNil(t, published.PublishedAt)
+		}
+	}
+	response, err = apiClient.Get(fmt.Sprintf("/posts?filter[id]=%v", post.ID))
+	if assert.NoError(t, err) {
+		var posts []entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &posts)) {
+			assert.Len(t, posts, 1)
+		}
+	}
+
+	// Archived, and can't be published again without moving back to drafts.
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/archive", post.ID), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+	}
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/unpublish", post.ID), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+}
+
+func TestPostsController_Revisions(t *testing.T) {
+	post := entities.Post{
+		Title:   "test-title",
+		Content: "test-content",
+	}
+	response, err := adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	post.Title = "test-title-new"
+	_, err = adminClient.PutObject(fmt.Sprintf("/posts/%v", post.ID), post)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	response, err = adminClient.Get(fmt.Sprintf("/posts/%v/revisions", post.ID))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var revisions []entities.Revision
+	if !assert.NoError(t, ParseJSONBody(response.Body, &revisions)) || !assert.Len(t, revisions, 2) {
+		return
+	}
+
+	response, err = adminClient.Get(fmt.Sprintf("/posts/%v/revisions/diff?from=%v&to=%v", post.ID, revisions[1].ID, revisions[0].ID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Content-Type"), "text/x-diff")
+		diff, err := io.ReadAll(response.Body)
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(diff), "+title: test-title-new")
+		}
+	}
+
+	revert := fmt.Sprintf("/posts/%v/revisions/%v/revert", post.ID, revisions[1].ID)
+	response, err = adminClient.PostBytesIfMatch(revert, entities.Post{ID: post.ID, Version: revisions[1].Version}.ETag(), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+	}
+	response, err = adminClient.PostBytesIfMatch(revert, entities.Post{ID: post.ID, Version: revisions[0].Version}.ETag(), nil)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		var reverted entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &reverted)) {
+			assert.Equal(t, "test-title", reverted.Title)
+		}
+	}
+}
+
+func TestPostsController_Batch(t *testing.T) {
+	type result struct {
+		Index  int            `json:"index"`
+		Status int            `json:"status"`
+		Error  string         `json:"error"`
+		ETag   string         `json:"etag"`
+		Post   *entities.Post `json:"post"`
+	}
+	var body struct {
+		Results []result `json:"results"`
+	}
+
+	response, err := adminClient.PostBytes("/posts:batch?atomic=false", []byte(`{"posts": [
+		{"title": "test-title-1", "content": "test-content"},
+		{"title": "", "content": "test-content"}
+	]}`))
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusMultiStatus, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &body)) || !assert.Len(t, body.Results, 2) {
+		return
+	}
+	created := body.Results[0]
+	assert.Equal(t, http.StatusCreated, created.Status)
+	assert.Equal(t, http.StatusBadRequest, body.Results[1].Status)
+	assert.NotEmpty(t, body.Results[1].Error)
+	if !assert.NotNil(t, created.Post) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", created.Post.ID))
+
+	response, err = adminClient.PutBytes("/posts:batch", []byte(fmt.Sprintf(
+		`{"posts": [{"id": %v, "title": "test-title-new", "content": "test-content", "if_match": %q}]}`,
+		created.Post.ID, created.ETag,
+	)))
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) &&
+		assert.NoError(t, ParseJSONBody(response.Body, &body)) && assert.Len(t, body.Results, 1) {
+		assert.Equal(t, "test-title-new", body.Results[0].Post.Title)
+	}
+
+	// Stale tags fail like If-Match does.
+	response, err = adminClient.DeleteBytes("/posts:batch", []byte(fmt.Sprintf(
+		`{"posts": [{"id": %v, "if_match": %q}]}`, created.Post.ID, created.ETag,
+	)))
+	if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &body)) && assert.Len(t, body.Results, 1) {
+		assert.Equal(t, http.StatusPreconditionFailed, body.Results[0].Status)
+	}
+}
+
+func TestPostsController_CreatePostIdempotency(t *testing.T) {
+	key := fmt.Sprintf("test-key-%v", time.Now().UnixNano())
+	body := []byte(`{"title": "test-title", "content": "test-content"}`)
+
+	first, err := adminClient.PostBytesWithKey("/posts", key, body)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, first.StatusCode) {
+		return
+	}
+	var created entities.Post
+	if !assert.NoError(t, ParseJSONBody(first.Body, &created)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", created.ID))
+
+	// Retry is replayed.
+	retry, err := adminClient.PostBytesWithKey("/posts", key, body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusCreated, retry.StatusCode)
+		assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+		var replayed entities.Post
+		if assert.NoError(t, ParseJSONBody(retry.Body, &replayed)) {
+			assert.Equal(t, created.ID, replayed.ID)
+		}
+	}
+
+	// Reuse for a different request is rejected.
+	reused, err := adminClient.PostBytesWithKey("/posts", key, []byte(`{"title": "other-title", "content": "test-content"}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode)
+	}
+
+	// Keys are scoped to users.
+	other, otherID, err := login("test-idempotency@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", otherID))
+	response, err := other.PostBytesWithKey("/posts", key, body)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusCreated, response.StatusCode) {
+		assert.Empty(t, response.Header.Get("Idempotent-Replayed"))
+		var own entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &own)) {
+			assert.NotEqual(t, created.ID, own.ID)
+			defer adminClient.Delete(fmt.Sprintf("/posts/%v", own.ID))
+		}
+	}
+
+	// Bodies held for fingerprinting are limited.
+	large := []byte(fmt.Sprintf(`{"title": "test-title", "content": %q}`, strings.Repeat("x", 1<<20)))
+	response, err = adminClient.PostBytesWithKey("/posts", key+"-large", large)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.StatusCode)
+	}
+
+	// Credentials are never replayed, and anonymous keys are ignored.
+	login := []byte(fmt.Sprintf(`{"email": %q, "password": %q}`, adminEmail, adminPassword))
+	for i := 0; i < 2; i++ {
+		response, err = apiClient.PostBytesWithKey("/sessions", key, login)
+		if assert.NoError(t, err) && assert.Equal(t, http.StatusCreated, response.StatusCode) {
+			assert.Empty(t, response.Header.Get("Idempotent-Replayed"))
+		}
+	}
+}
+
 func TestPostsController_DeletePost(t *testing.T) {
 	cases := []struct {
 		setup     func() (entities.PostID, error)
@@ -398,7 +920,7 @@ func TestPostsController_DeletePost(t *testing.T) {
 					Title:   "test-title",
 					Content: "test-content",
 				}
-				response, err := apiClient.PostObject("/posts", post)
+				response, err := adminClient.PostObject("/posts", post)
 				if err != nil {
 					return 0, err
 				}
@@ -409,7 +931,7 @@ func TestPostsController_DeletePost(t *testing.T) {
 				return post.ID, nil
 			},
 			base: func(id entities.PostID) (*http.Response, error) {
-				return apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
@@ -423,22 +945,84 @@ func TestPostsController_DeletePost(t *testing.T) {
 				}
 			},
 		},
+		// Stale If-Match.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				response, err := adminClient.DeleteIfMatch(fmt.Sprintf("/posts/%v", id), fmt.Sprintf(`"%v-2"`, id))
+				if err != nil {
+					return nil, err
+				}
+				_, err = adminClient.Delete(fmt.Sprintf("/posts/%v", id))
+				return response, err
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+				}
+			},
+		},
+		// Current If-Match.
+		{
+			setup: func() (entities.PostID, error) {
+				post := entities.Post{
+					Title:   "test-title",
+					Content: "test-content",
+				}
+				response, err := adminClient.PostObject("/posts", post)
+				if err != nil {
+					return 0, err
+				}
+				err = ParseJSONBody(response.Body, &post)
+				if err != nil {
+					return 0, err
+				}
+				return post.ID, nil
+			},
+			base: func(id entities.PostID) (*http.Response, error) {
+				response, err := adminClient.Get(fmt.Sprintf("/posts/%v", id))
+				if err != nil {
+					return nil, err
+				}
+				assert.Equal(t, fmt.Sprintf(`"%v-1"`, id), response.Header.Get("ETag"))
+				return adminClient.DeleteIfMatch(fmt.Sprintf("/posts/%v", id), response.Header.Get("ETag"))
+			},
+			assertion: func(response *http.Response, err error) {
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusOK, response.StatusCode)
+				}
+			},
+		},
 		// Non-existing id.
 		{
 			setup: func() (entities.PostID, error) {
 				id := entities.PostID(69)
-				_, err := apiClient.Delete(fmt.Sprintf("/posts/%v", id))
+				_, err := adminClient.Delete(fmt.Sprintf("/posts/%v", id))
 				if err != nil {
 					return 0, err
 				}
 				return id, nil
 			},
 			base: func(id entities.PostID) (*http.Response, error) {
-				return apiClient.Get(fmt.Sprintf("/posts/%v", id))
+				return adminClient.Get(fmt.Sprintf("/posts/%v", id))
 			},
 			assertion: func(response *http.Response, err error) {
 				if assert.NoError(t, err) {
-					assert.Equal(t, response.StatusCode, http.StatusBadRequest)
+					assert.Equal(t, response.StatusCode, http.StatusNotFound)
 					var message map[string]string
 					if err := ParseJSONBody(response.Body, &message); assert.NoError(t, err) {
 						if m, ok := message["message"]; assert.True(t, ok) {
@@ -456,3 +1040,45 @@ func TestPostsController_DeletePost(t *testing.T) {
 		c.assertion(c.base(id))
 	}
 }
+
+func TestPostsController_Transfer(t *testing.T) {
+	var post entities.Post
+	response, err := adminClient.PostBytes("/posts", []byte(`{"title": "test-transfer", "content": "test-content"}`))
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+
+	route := fmt.Sprintf("/posts/export?format=csv&columns=id,title&filter[slug]=%v", post.Slug)
+	response, err = apiClient.Get(route)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+	response, err = adminClient.Get(route)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, "text/csv; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.Equal(t, fmt.Sprintf("id,title\n%v,test-transfer\n", post.ID), string(body))
+	}
+	response, err = adminClient.Get("/posts/export?format=xml")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+
+	var report entities.ImportReport
+	response, err = adminClient.PostBytes("/posts/import?format=csv&dry_run=true&map=ID:id,Headline:title", []byte(
+		fmt.Sprintf("ID,Headline\n%v,test-transfer-new\n0,\n", post.ID),
+	))
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusMultiStatus, response.StatusCode) &&
+		assert.NoError(t, ParseJSONBody(response.Body, &report)) {
+		assert.Equal(t, 2, report.Records)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Failed)
+		assert.True(t, report.DryRun)
+	}
+	response, err = adminClient.PostBytes("/posts/import?format=csv", []byte("body\ntest\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+}
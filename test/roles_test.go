@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/policy"
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles_Posts(t *testing.T) {
+	owner, ownerID, err := login("test-roles-owner@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", ownerID))
+	other, otherID, err := login("test-roles-other@example.com", "test-password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", otherID))
+
+	// Anonymous users can't write posts.
+	post := entities.Post{Title: "test-title", Content: "test-content"}
+	response, err := apiClient.PostObject("/posts", post)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// Authors own the posts they create, and can't create posts for others.
+	response, err = owner.PostObject("/posts", entities.Post{UserID: otherID, Title: "test-title", Content: "test-content"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+	response, err = owner.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	assert.Equal(t, ownerID, post.UserID)
+	route := fmt.Sprintf("/posts/%v", post.ID)
+
+	// Drafts are only seen by their owners and the users who can edit them.
+	for client, status := range map[*APIClient]int{apiClient: http.StatusNotFound, other: http.StatusNotFound, owner: http.StatusOK, adminClient: http.StatusOK} {
+		response, err = client.Get(route)
+		if assert.NoError(t, err) {
+			assert.Equal(t, status, response.StatusCode)
+		}
+	}
+
+	// Authors only edit their own posts, drafts of others don't exist for them.
+	response, err = other.PutObject(route, entities.Post{Title: "test-title-other", Content: "test-content"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+	response, err = other.PatchBytes(route, "application/merge-patch+json", []byte(`{"title": "test-title-other"}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+	response, err = other.Delete(route)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+	response, err = owner.PutObject(route, entities.Post{Title: "test-title-new", Content: "test-content"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+	}
+	// Nor give them away.
+	response, err = owner.PatchBytes(route, "application/merge-patch+json", []byte(fmt.Sprintf(`{"user_id": %v}`, otherID)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+	// Nor take them back by reverting to their own revisions.
+	response, err = adminClient.PutObject(route, entities.Post{UserID: otherID, Title: "test-title-given", Content: "test-content"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+	}
+	var revisions []entities.Revision
+	response, err = adminClient.Get(route + "/revisions")
+	if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &revisions)) && assert.NotEmpty(t, revisions) {
+		response, err = other.PostBytes(fmt.Sprintf("%v/revisions/%v/revert", route, revisions[len(revisions)-1].ID), nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		}
+	}
+
+	// Only admins change roles, and not their own.
+	role := fmt.Sprintf("/users/%v/role", otherID)
+	response, err = other.PutObject(role, map[string]policy.Role{"role": policy.RoleEditor})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+	response, err = adminClient.PutObject(role, map[string]policy.Role{"role": "owner"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+	response, err = adminClient.PutObject(role, map[string]policy.Role{"role": policy.RoleEditor})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	// Editors edit every post.
+	response, err = other.PutObject(route, entities.Post{Title: "test-title-other", Content: "test-content"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+	}
+
+	// And list every post, drafts of others included, just like they find them.
+	draft := entities.Post{Title: "test-title-draft", Content: "test-content"}
+	response, err = adminClient.PostObject("/posts", draft)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &draft)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", draft.ID))
+	for client, listed := range map[*APIClient]bool{apiClient: false, owner: false, other: true, adminClient: true} {
+		response, err = client.Get(fmt.Sprintf("/posts?filter[id]=%v", draft.ID))
+		var posts []entities.Post
+		if assert.NoError(t, err) && assert.NoError(t, ParseJSONBody(response.Body, &posts)) {
+			assert.Equal(t, listed, len(posts) == 1)
+		}
+	}
+}
+
+func TestRoles_Concurrent(t *testing.T) {
+	post := entities.Post{Title: "test-title", Content: "test-content"}
+	response, err := adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	route := fmt.Sprintf("/posts/%v", post.ID)
+
+	// Concurrent requests are authorized with their own identities, never with each other's.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for client, status := range map[*APIClient]int{apiClient: http.StatusNotFound, adminClient: http.StatusOK} {
+			wg.Add(1)
+			go func(client *APIClient, status int) {
+				defer wg.Done()
+				response, err := client.Get(route)
+				if assert.NoError(t, err) {
+					assert.Equal(t, status, response.StatusCode)
+				}
+			}(client, status)
+		}
+	}
+	wg.Wait()
+}
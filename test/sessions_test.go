@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	authentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/auth/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsController_Sessions(t *testing.T) {
+	user := userentities.User{Fullname: "test-fullname", Email: "test-sessions@example.com"}
+	response, err := apiClient.PostObject("/users", map[string]interface{}{
+		"fullname": user.Fullname,
+		"email":    user.Email,
+		"password": "test-password",
+	})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &user)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+	sessions := fmt.Sprintf("/users/%v/sessions", user.ID)
+
+	// Anonymous.
+	response, err = apiClient.Get(sessions)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+	// Wrong password.
+	response, err = apiClient.PostObject("/sessions", map[string]string{"email": user.Email, "password": "wrong-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	first := apiClient.WithCookies()
+	response, err = first.PostObject("/sessions", map[string]string{"email": user.Email, "password": "test-password"})
+	var session authentities.Session
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &session)) {
+		return
+	}
+	assert.Equal(t, user.ID, session.UserID)
+	if cookies := response.Cookies(); assert.Len(t, cookies, 1) {
+		assert.True(t, cookies[0].HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	}
+	second := apiClient.WithCookies()
+	response, err = second.PostObject("/sessions", map[string]string{"email": user.Email, "password": "test-password"})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) {
+		return
+	}
+
+	response, err = first.Get(sessions)
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		var listed []authentities.Session
+		if assert.NoError(t, ParseJSONBody(response.Body, &listed)) && assert.Len(t, listed, 2) {
+			for _, s := range listed {
+				assert.Equal(t, s.ID == session.ID, s.Current)
+			}
+		}
+	}
+	// Other users' sessions.
+	response, err = first.Get(fmt.Sprintf("/users/%v/sessions", user.ID+1))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+
+	// Revoking other sessions logs the second client out.
+	response, err = first.Delete(sessions)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = second.Get(sessions)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// Changing password requires the current one.
+	password := fmt.Sprintf("/users/%v/password", user.ID)
+	response, err = first.PutObject(password, map[string]string{"current_password": "wrong-password", "password": "test-new-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+	response, err = first.PutObject(password, map[string]string{"current_password": "test-password", "password": "test-new-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	response, err = first.Delete("/sessions/current")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = first.Get(sessions)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+}
+
+func TestSessionsController_Lockout(t *testing.T) {
+	user := userentities.User{Fullname: "test-fullname", Email: "test-lockout@example.com"}
+	response, err := apiClient.PostObject("/users", map[string]interface{}{
+		"fullname": user.Fullname,
+		"email":    user.Email,
+		"password": "test-password",
+	})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &user)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+
+	// Tests are configured to lock users out after 5 failed logins.
+	for i := 0; i < 4; i++ {
+		response, err = apiClient.PostObject("/sessions", map[string]string{"email": user.Email, "password": "wrong-password"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		}
+	}
+	response, err = apiClient.PostObject("/sessions", map[string]string{"email": user.Email, "password": "wrong-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+		assert.NotEmpty(t, response.Header.Get("Retry-After"))
+	}
+	response, err = apiClient.PostObject("/sessions", map[string]string{"email": user.Email, "password": "test-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	}
+}
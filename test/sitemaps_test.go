@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSitemapsController_Sitemap(t *testing.T) {
+	post := entities.Post{Title: "test-sitemap-title", Content: "test-content"}
+	response, err := adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	location := fmt.Sprintf("/posts/by-slug/%v</loc>", post.Slug)
+
+	// Drafts are left out.
+	response, err = apiClient.Get("/sitemap.xml")
+	if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode) {
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, "application/xml; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.Contains(t, string(body), "<urlset")
+		assert.NotContains(t, string(body), location)
+	}
+
+	response, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusOK, response.StatusCode) {
+		return
+	}
+	for _, route := range []string{"/sitemap.xml", "/sitemaps/posts-1.xml"} {
+		response, err = apiClient.Get(route)
+		if assert.NoError(t, err) && assert.Equal(t, http.StatusOK, response.StatusCode, route) {
+			body, _ := io.ReadAll(response.Body)
+			assert.Contains(t, string(body), location, route)
+			assert.NotEmpty(t, response.Header.Get("ETag"), route)
+		}
+	}
+
+	response, err = apiClient.Get("/sitemaps/posts-1000.xml")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsController_Tags(t *testing.T) {
+	tag := entities.Tag{Name: "test-tag"}
+	response, err := adminClient.PostObject("/tags", tag)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &tag)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/tags/%v", tag.ID))
+
+	// Duplicate name.
+	response, err = adminClient.PostObject("/tags", entities.Tag{Name: "test-tag"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+	}
+
+	post := entities.Post{
+		Title:   "test-title",
+		Content: "test-content",
+		Tags:    []entities.Tag{{Name: "test-tag"}},
+	}
+	response, err = adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	if assert.Len(t, post.Tags, 1) {
+		assert.Equal(t, tag.ID, post.Tags[0].ID)
+	}
+	_, err = adminClient.PostBytes(fmt.Sprintf("/posts/%v/publish", post.ID), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	response, err = apiClient.Get("/posts?filter[tag]=test-tag")
+	if assert.NoError(t, err) {
+		var posts []entities.Post
+		if assert.NoError(t, ParseJSONBody(response.Body, &posts)) && assert.Len(t, posts, 1) {
+			assert.Equal(t, post.ID, posts[0].ID)
+		}
+	}
+
+	response, err = apiClient.Get("/tags")
+	if assert.NoError(t, err) {
+		var counts []entities.TagCount
+		if assert.NoError(t, ParseJSONBody(response.Body, &counts)) {
+			for _, count := range counts {
+				if count.ID == tag.ID {
+					assert.Equal(t, int64(1), count.Count)
+				}
+			}
+		}
+	}
+}
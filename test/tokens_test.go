@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/jwt"
+	tokenentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/tokens/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testKey is the key configured by jwt.secret and jwt.secret_kid in test configs.
+var testKey = jwt.Key{ID: "default", Algorithm: jwt.HS256, Secret: []byte("insecure-development-secret")}
+
+func TestTokensController_Tokens(t *testing.T) {
+	user := userentities.User{Fullname: "test-fullname", Email: "test-tokens@example.com"}
+	response, err := apiClient.PostObject("/users", map[string]interface{}{
+		"fullname": user.Fullname,
+		"email":    user.Email,
+		"password": "test-password",
+	})
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &user)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+	sessions := fmt.Sprintf("/users/%v/sessions", user.ID)
+
+	response, err = apiClient.PostObject("/tokens", map[string]string{"email": user.Email, "password": "wrong-password"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+	response, err = apiClient.PostObject("/tokens", map[string]string{"email": user.Email, "password": "test-password"})
+	var first tokenentities.TokenPair
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &first)) {
+		return
+	}
+	assert.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+	assert.Equal(t, "Bearer", first.TokenType)
+
+	// Access tokens authenticate their user.
+	response, err = apiClient.GetWithToken(sessions, first.AccessToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	// Refresh tokens don't.
+	response, err = apiClient.GetWithToken(sessions, first.RefreshToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		assert.Contains(t, response.Header.Get("WWW-Authenticate"), "invalid_token")
+	}
+
+	response, err = apiClient.PostObject("/tokens/refresh", map[string]string{"refresh_token": first.RefreshToken})
+	var second tokenentities.TokenPair
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &second)) {
+		return
+	}
+	// Refresh tokens are single use.
+	response, err = apiClient.PostObject("/tokens/refresh", map[string]string{"refresh_token": first.RefreshToken})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	response, err = apiClient.PostObject("/tokens/revoke", map[string]string{"refresh_token": second.RefreshToken})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	response, err = apiClient.PostObject("/tokens/refresh", map[string]string{"refresh_token": second.RefreshToken})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+}
+
+func TestJWTMiddleware_Services(t *testing.T) {
+	now := time.Now()
+	sign := func(claims jwt.Claims) string {
+		token, err := jwt.Sign(claims, testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := jwt.Claims{Issuer: "test-service", Subject: "1", Audience: jwt.Audience{"rest-api-framework"}, ExpiresAt: now.Add(time.Minute).Unix()}
+
+	cases := []struct {
+		claims func() jwt.Claims
+		status int
+	}{
+		// Trusted service.
+		{
+			claims: func() jwt.Claims { return valid },
+			status: http.StatusOK,
+		},
+		// Expired.
+		{
+			claims: func() jwt.Claims {
+				claims := valid
+				claims.ExpiresAt = now.Add(-time.Hour).Unix()
+				return claims
+			},
+			status: http.StatusUnauthorized,
+		},
+		// Untrusted issuer.
+		{
+			claims: func() jwt.Claims {
+				claims := valid
+				claims.Issuer = "other-service"
+				return claims
+			},
+			status: http.StatusUnauthorized,
+		},
+		// Other audience.
+		{
+			claims: func() jwt.Claims {
+				claims := valid
+				claims.Audience = jwt.Audience{"other-audience"}
+				return claims
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		response, err := apiClient.GetWithToken("/posts", sign(c.claims()))
+		if assert.NoError(t, err) {
+			assert.Equal(t, c.status, response.StatusCode)
+		}
+	}
+
+	// Subjects of other issuers aren't users of this API.
+	response, err := apiClient.GetWithToken("/users/1/sessions", sign(valid))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+}
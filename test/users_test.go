@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/nataliia_hudzeliak/rest-api-framework/app/services/posts/entities"
+	userentities "github.com/nataliia_hudzeliak/rest-api-framework/app/services/users/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersController_Users(t *testing.T) {
+	user := userentities.User{Fullname: "test-fullname", Email: "test-users@example.com"}
+	response, err := adminClient.PostObject("/users", user)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusCreated, response.StatusCode) ||
+		!assert.NoError(t, ParseJSONBody(response.Body, &user)) {
+		return
+	}
+	defer adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+
+	response, err = adminClient.PostObject("/users", userentities.User{Fullname: ""})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+
+	response, err = adminClient.PutObject(fmt.Sprintf("/users/%v", user.ID), userentities.User{Fullname: "test-fullname-new"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+	}
+	response, err = apiClient.Get(fmt.Sprintf("/users/%v", user.ID))
+	if assert.NoError(t, err) {
+		var found userentities.User
+		if assert.NoError(t, ParseJSONBody(response.Body, &found)) {
+			assert.Equal(t, "test-fullname-new", found.Fullname)
+		}
+	}
+	response, err = apiClient.Get("/users?filter[fullname]=test-fullname-new")
+	if assert.NoError(t, err) {
+		var users []userentities.User
+		if assert.NoError(t, ParseJSONBody(response.Body, &users)) && assert.Len(t, users, 1) {
+			assert.Equal(t, user.ID, users[0].ID)
+			assert.Empty(t, users[0].Email)
+		}
+	}
+
+	// Emails are only served to the user and to admins, and can't be filtered on.
+	response, err = apiClient.Get(fmt.Sprintf("/users/%v", user.ID))
+	if assert.NoError(t, err) {
+		var found userentities.User
+		if assert.NoError(t, ParseJSONBody(response.Body, &found)) {
+			assert.Empty(t, found.Email)
+		}
+	}
+	response, err = adminClient.Get(fmt.Sprintf("/users/%v", user.ID))
+	if assert.NoError(t, err) {
+		var found userentities.User
+		if assert.NoError(t, ParseJSONBody(response.Body, &found)) {
+			assert.Equal(t, "test-users@example.com", found.Email)
+		}
+	}
+	response, err = apiClient.Get("/users?filter[email]=test-users@example.com")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+
+	// Posts reference existing users only, and keep them from being deleted.
+	response, err = adminClient.PostObject("/posts", entities.Post{UserID: math.MaxInt32, Title: "test-title", Content: "test-content"})
+	if assert.NoError(t, err) {
+		body := make(map[string]string)
+		if assert.Equal(t, http.StatusBadRequest, response.StatusCode) && assert.NoError(t, ParseJSONBody(response.Body, &body)) {
+			assert.Equal(t, userentities.ErrUserNotFound.Error(), body["message"])
+		}
+	}
+	post := entities.Post{UserID: user.ID, Title: "test-title", Content: "test-content"}
+	response, err = adminClient.PostObject("/posts", post)
+	if !assert.NoError(t, err) || !assert.NoError(t, ParseJSONBody(response.Body, &post)) {
+		return
+	}
+	response, err = adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+	}
+	_, err = adminClient.Delete(fmt.Sprintf("/posts/%v", post.ID))
+	assert.NoError(t, err)
+	response, err = adminClient.Delete(fmt.Sprintf("/users/%v", user.ID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+}